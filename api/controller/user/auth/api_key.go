@@ -0,0 +1,77 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type ApiKey struct {
+	apiKeyUseCase domain_user_auth.ApiKeyUseCase
+}
+
+func NewApiKeyController(apiKeyUseCase domain_user_auth.ApiKeyUseCase) *ApiKey {
+	return &ApiKey{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
+
+func (a *ApiKey) CreateApiKeyController(c *fiber.Ctx) error {
+	var input domain_user_auth.CreateApiKeyRequest
+	var response payload.Response
+
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := a.apiKeyUseCase.Create(userLocal.ID, &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.ApiKeyCreated)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func (a *ApiKey) ListApiKeyController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := a.apiKeyUseCase.List(userLocal.ID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (a *ApiKey) RevokeApiKeyController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := a.apiKeyUseCase.Revoke(userLocal.ID, c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.ApiKeyRevoked)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,63 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/api/routes/middleware"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type AuthEvent struct {
+	authEventUseCase domain_user_auth.AuthEventUseCase
+}
+
+func NewAuthEventController(authEventUseCase domain_user_auth.AuthEventUseCase) *AuthEvent {
+	return &AuthEvent{
+		authEventUseCase: authEventUseCase,
+	}
+}
+
+// recordAuthEvent melengkapi IP dan user agent dari request lalu mencatat event
+func recordAuthEvent(c *fiber.Ctx, authEventUseCase domain_user_auth.AuthEventUseCase, input domain_user_auth.AuthEventInput) {
+	input.IP = middleware.GetPublicIP(c)
+	input.UserAgent = c.Get("User-Agent")
+	authEventUseCase.Record(input)
+}
+
+// RecentSignInsController riwayat sign-in milik user yang sedang login
+func (a *AuthEvent) RecentSignInsController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := a.authEventUseCase.RecentSignIns(userLocal.ID, c.QueryInt("limit"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SearchAuthEventsController pencarian event untuk admin saat review insiden
+func (a *AuthEvent) SearchAuthEventsController(c *fiber.Ctx) error {
+	var input domain_user_auth.AuthEventSearchRequest
+	var response payload.Response
+
+	if err := c.QueryParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := a.authEventUseCase.Search(&input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,105 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/api/routes/middleware"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type Impersonation struct {
+	impersonationUseCase domain_user_auth.ImpersonationUseCase
+}
+
+func NewImpersonationController(impersonationUseCase domain_user_auth.ImpersonationUseCase) *Impersonation {
+	return &Impersonation{
+		impersonationUseCase: impersonationUseCase,
+	}
+}
+
+// StartImpersonationController admin mendapat token read-only atas nama user client.
+// Token dikirim di body saja supaya cookie session admin tidak tertimpa.
+func (i *Impersonation) StartImpersonationController(c *fiber.Ctx) error {
+	var input domain_user_auth.StartImpersonationRequest
+	var response payload.Response
+
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := i.impersonationUseCase.Start(userLocal.ID, c.Params("id"), &input, middleware.GetPublicIP(c), c.Get("User-Agent"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.ImpersonationStarted)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// EndImpersonationController dipanggil dengan token impersonation untuk mengakhiri sesinya sendiri
+func (i *Impersonation) EndImpersonationController(c *fiber.Ctx) error {
+	var response payload.Response
+	impersonation, ok := c.Locals("impersonation").(model.Impersonation)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.NotImpersonating)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := i.impersonationUseCase.End(impersonation.Id, model.ImpersonationEndedByOperator); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.ImpersonationStopped)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// TerminateImpersonationController admin lain menghentikan impersonation yang masih aktif
+func (i *Impersonation) TerminateImpersonationController(c *fiber.Ctx) error {
+	var response payload.Response
+	if err := i.impersonationUseCase.End(c.Params("id"), model.ImpersonationEndedByAdmin); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.ImpersonationStopped)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (i *Impersonation) SearchImpersonationController(c *fiber.Ctx) error {
+	var input domain_user_auth.ImpersonationSearchRequest
+	var response payload.Response
+
+	if err := c.QueryParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := i.impersonationUseCase.Search(&input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// DetailImpersonationController catatan audit lengkap dengan semua path yang diakses
+func (i *Impersonation) DetailImpersonationController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := i.impersonationUseCase.Detail(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
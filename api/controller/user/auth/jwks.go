@@ -0,0 +1,28 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	jwttoken "xops-admin/util/token_jwt"
+)
+
+type Jwks struct{}
+
+func NewJwksController() *Jwks {
+	return &Jwks{}
+}
+
+// JwksController kunci publik access token (aktif + pensiun) untuk service lain
+func (j *Jwks) JwksController(c *fiber.Ctx) error {
+	loadconfig, _ := config.LoadConfig(".")
+	set, err := jwttoken.JWKS(loadconfig.AccessTokenKeyring())
+	if err != nil {
+		response := payload.NewErrorResponse(errorenum.SomethingError)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return c.Status(fiber.StatusOK).JSON(set)
+}
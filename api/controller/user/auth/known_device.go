@@ -0,0 +1,72 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type KnownDevice struct {
+	knownDeviceUseCase domain_user_auth.KnownDeviceUseCase
+}
+
+func NewKnownDeviceController(knownDeviceUseCase domain_user_auth.KnownDeviceUseCase) *KnownDevice {
+	return &KnownDevice{
+		knownDeviceUseCase: knownDeviceUseCase,
+	}
+}
+
+func (k *KnownDevice) ListDeviceController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := k.knownDeviceUseCase.ListDevices(userLocal.ID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (k *KnownDevice) ForgetDeviceController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := k.knownDeviceUseCase.ForgetDevice(userLocal.ID, c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.DeviceForgotten)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// NotMeController dipanggil frontend dari link "this wasn't me" di email device baru
+func (k *KnownDevice) NotMeController(c *fiber.Ctx) error {
+	var input domain_user_auth.NotMeRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := k.knownDeviceUseCase.ReportNotMe(input.Token); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.NotMeReported)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,41 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+)
+
+type Lockout struct {
+	lockoutUseCase domain_user_auth.LockoutUseCase
+}
+
+func NewLockoutController(lockoutUseCase domain_user_auth.LockoutUseCase) *Lockout {
+	return &Lockout{
+		lockoutUseCase: lockoutUseCase,
+	}
+}
+
+func (l *Lockout) LockStatusController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := l.lockoutUseCase.Status(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UnlockController endpoint admin untuk membuka akun yang terkunci
+func (l *Lockout) UnlockController(c *fiber.Ctx) error {
+	var response payload.Response
+	if err := l.lockoutUseCase.Unlock(c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.AccountUnlocked)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -11,14 +11,31 @@ import (
 )
 
 type Login struct {
-	loginUseCase domain_user_auth.LoginUseCase
+	loginUseCase     domain_user_auth.LoginUseCase
+	lockoutUseCase   domain_user_auth.LockoutUseCase
+	authEventUseCase domain_user_auth.AuthEventUseCase
 }
 
-func NewLoginController(loginUsecase domain_user_auth.LoginUseCase) *Login {
+func NewLoginController(loginUsecase domain_user_auth.LoginUseCase, lockoutUseCase domain_user_auth.LockoutUseCase, authEventUseCase domain_user_auth.AuthEventUseCase) *Login {
 	return &Login{
-		loginUseCase: loginUsecase,
+		loginUseCase:     loginUsecase,
+		lockoutUseCase:   lockoutUseCase,
+		authEventUseCase: authEventUseCase,
 	}
 }
+
+func (l *Login) recordLogin(c *fiber.Ctx, user *model.User, email, outcome, reason string) {
+	input := domain_user_auth.AuthEventInput{
+		Email:     email,
+		EventType: model.AuthEventLogin,
+		Outcome:   outcome,
+		Reason:    reason,
+	}
+	if user != nil {
+		input.IdUser = user.Id
+	}
+	recordAuthEvent(c, l.authEventUseCase, input)
+}
 func (l *Login) LoginUserControlller(c *fiber.Ctx) error {
 	var input domain_user_auth.LoginRequest
 	var response payload.Response
@@ -32,25 +49,48 @@ func (l *Login) LoginUserControlller(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
 	user, err := l.loginUseCase.LoginUser(input.Email)
-	if err == errorenum.DataNotFound {
+	if err != nil {
+		l.recordLogin(c, nil, input.Email, model.AuthOutcomeFailure, "unknown_email")
 		response = payload.NewErrorResponse(errorenum.FailedLogin)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
+	if err := l.lockoutUseCase.CheckLocked(user.Id); err != nil {
+		l.recordLogin(c, user, input.Email, model.AuthOutcomeFailure, "account_locked")
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusLocked).JSON(response)
+	}
 	// if err := l.loginUseCase.IsUserVerified(user); err != nil {
 	// 	response = payload.NewErrorResponse(err)
 	// 	return c.Status(fiber.StatusBadRequest).JSON(response)
 	// }
 	if !user.IsVerified {
+		l.recordLogin(c, user, input.Email, model.AuthOutcomeFailure, "account_not_verified")
 		response := payload.NewErrorResponse(errorenum.FailedLogin)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
 
 	if err := l.loginUseCase.ComparePasswordHash(user, input.Password); err != nil {
+		l.recordLogin(c, user, input.Email, model.AuthOutcomeFailure, "wrong_password")
+		if err := l.lockoutUseCase.RegisterFailure(user, domain_user_auth.FailedAttemptPassword); err != nil {
+			response = payload.NewErrorResponse(err)
+			return c.Status(fiber.StatusLocked).JSON(response)
+		}
 		response = payload.NewErrorResponse(errorenum.FailedLogin)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
+	l.lockoutUseCase.ResetFailures(user.Id, domain_user_auth.FailedAttemptPassword)
+	l.recordLogin(c, user, input.Email, model.AuthOutcomeSuccess, "password_verified")
 	//send otp
 	l.loginUseCase.SendOtpVerifedCode(user)
+	if user.TwoFAMethod != domain_user_auth.TwoFAMethodTOTP {
+		recordAuthEvent(c, l.authEventUseCase, domain_user_auth.AuthEventInput{
+			IdUser:    user.Id,
+			Email:     user.Email,
+			EventType: model.AuthEventOtpSend,
+			Outcome:   model.AuthOutcomeSuccess,
+			Reason:    "login",
+		})
+	}
 	config, _ := config.LoadConfig(".")
 
 	accessTokenDetails, err := l.loginUseCase.GenerateTokenJwt(config.AccessTokenExpiresIn, user.Id, config.AccessTokenPrivateKey)
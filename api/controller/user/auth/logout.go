@@ -0,0 +1,60 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type Logout struct {
+	logoutUseCase    domain_user_auth.LogoutUseCase
+	authEventUseCase domain_user_auth.AuthEventUseCase
+}
+
+func NewLogoutController(logoutUseCase domain_user_auth.LogoutUseCase, authEventUseCase domain_user_auth.AuthEventUseCase) *Logout {
+	return &Logout{
+		logoutUseCase:    logoutUseCase,
+		authEventUseCase: authEventUseCase,
+	}
+}
+
+func (l *Logout) LogoutController(c *fiber.Ctx) error {
+	var response payload.Response
+	tokenUuid, _ := c.Locals("access_token_uuid").(string)
+	expires, _ := c.Locals("access_token_expires").(int64)
+	sessionID, _ := c.Locals("session_id").(string)
+	if tokenUuid == "" {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+
+	if err := l.logoutUseCase.Logout(tokenUuid, expires, sessionID); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	clearAuthCookies(c)
+	userLocal, _ := c.Locals("user").(model.UserResponse)
+	recordAuthEvent(c, l.authEventUseCase, domain_user_auth.AuthEventInput{
+		IdUser:    userLocal.ID,
+		Email:     userLocal.Email,
+		EventType: model.AuthEventLogout,
+		Outcome:   model.AuthOutcomeSuccess,
+		SessionID: sessionID,
+	})
+	response = payload.NewSuccessResponse(nil, errorenum.LogoutSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RevokeUserTokensController endpoint admin untuk mencabut semua token milik user tertentu
+func (l *Logout) RevokeUserTokensController(c *fiber.Ctx) error {
+	var response payload.Response
+	if err := l.logoutUseCase.RevokeUserTokens(c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.UserTokensRevoked)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
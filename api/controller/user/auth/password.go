@@ -0,0 +1,107 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type Password struct {
+	passwordUseCase domain_user_auth.PasswordUseCase
+}
+
+func NewPasswordController(passwordUseCase domain_user_auth.PasswordUseCase) *Password {
+	return &Password{
+		passwordUseCase: passwordUseCase,
+	}
+}
+
+func (p *Password) ForgotPasswordController(c *fiber.Ctx) error {
+	var input domain_user_auth.ForgotPasswordRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := p.passwordUseCase.ForgotPassword(input.Email); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.ResetLinkSent)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (p *Password) ResetPasswordController(c *fiber.Ctx) error {
+	var input domain_user_auth.ResetPasswordRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := p.passwordUseCase.ResetPassword(input.Token, input.NewPassword); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.PasswordChanged)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (p *Password) ActivateAccountController(c *fiber.Ctx) error {
+	var input domain_user_auth.ActivateAccountRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := p.passwordUseCase.ActivateAccount(input.Token, input.NewPassword); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.AccountActivated)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (p *Password) ChangePasswordController(c *fiber.Ctx) error {
+	var input domain_user_auth.ChangePasswordRequest
+	var response payload.Response
+
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := p.passwordUseCase.ChangePassword(userLocal.ID, input.CurrentPassword, input.NewPassword); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	// semua session dicabut, termasuk yang sedang dipakai
+	clearAuthCookies(c)
+	response = payload.NewSuccessResponse(nil, errorenum.PasswordChanged)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
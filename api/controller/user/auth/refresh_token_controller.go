@@ -3,19 +3,25 @@ package controller_user
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"xops-admin/api/routes/middleware"
 	"xops-admin/config"
 	domain_user "xops-admin/domain/user/auth"
 	"xops-admin/helper/errorenum"
 	"xops-admin/helper/payload"
+	"xops-admin/model"
 )
 
 type RefreshToken struct {
 	refreshTokenUsecase domain_user.RefreshTokenUsecase
+	sessionUseCase      domain_user.SessionUseCase
+	authEventUseCase    domain_user.AuthEventUseCase
 }
 
-func NewRefreshTokenController(refreshTokenUsecase domain_user.RefreshTokenUsecase) *RefreshToken {
+func NewRefreshTokenController(refreshTokenUsecase domain_user.RefreshTokenUsecase, sessionUseCase domain_user.SessionUseCase, authEventUseCase domain_user.AuthEventUseCase) *RefreshToken {
 	return &RefreshToken{
 		refreshTokenUsecase: refreshTokenUsecase,
+		sessionUseCase:      sessionUseCase,
+		authEventUseCase:    authEventUseCase,
 	}
 }
 
@@ -29,22 +35,22 @@ func (r *RefreshToken) RefreshTokenController(c *fiber.Ctx) error {
 		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	tokenClaims, err := r.refreshTokenUsecase.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
-		return c.Status(fiber.StatusUnauthorized).JSON(response)
-	}
-	user, err := r.refreshTokenUsecase.FindUserBYID(tokenClaims.UserID)
+
+	//rotasi refresh token, token yang sudah pernah dipakai akan mencabut session
+	user, session, refreshTokenDetails, err := r.sessionUseCase.RotateRefreshToken(refresh_token, domain_user.SessionMeta{
+		UserAgent: c.Get("User-Agent"),
+		IP:        middleware.GetPublicIP(c),
+	})
 	if err != nil {
-		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		recordAuthEvent(c, r.authEventUseCase, domain_user.AuthEventInput{
+			EventType: model.AuthEventTokenRefresh,
+			Outcome:   model.AuthOutcomeFailure,
+			Reason:    err.Error(),
+		})
+		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 
-	if user.RefreshToken != refresh_token {
-		response = payload.NewErrorResponse(errorenum.Forbidden)
-		return c.Status(fiber.StatusForbidden).JSON(response)
-	}
-
 	//dapatin lagi acccess keynya
 	accesToken, err := r.refreshTokenUsecase.GenerateTokenJwt(loadconfig.AccessTokenExpiresIn, user.Id, loadconfig.AccessTokenPrivateKey)
 	if err != nil {
@@ -52,6 +58,13 @@ func (r *RefreshToken) RefreshTokenController(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(response)
 	}
 	tokenAcces := accesToken.Token
+	recordAuthEvent(c, r.authEventUseCase, domain_user.AuthEventInput{
+		IdUser:    user.Id,
+		Email:     user.Email,
+		EventType: model.AuthEventTokenRefresh,
+		Outcome:   model.AuthOutcomeSuccess,
+		SessionID: session.Id,
+	})
 
 	c.Cookie(&fiber.Cookie{
 		Name:     "access_token",
@@ -63,6 +76,16 @@ func (r *RefreshToken) RefreshTokenController(c *fiber.Ctx) error {
 		SameSite: "None",
 	})
 
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    *refreshTokenDetails.Token,
+		Path:     "/",
+		MaxAge:   loadconfig.RefreshTokenMaxAge * 60,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: "None",
+	})
+
 	c.Cookie(&fiber.Cookie{
 		Name:     "logged_in",
 		Value:    "true",
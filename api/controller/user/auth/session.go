@@ -0,0 +1,88 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type Session struct {
+	sessionUseCase domain_user_auth.SessionUseCase
+}
+
+func NewSessionController(sessionUseCase domain_user_auth.SessionUseCase) *Session {
+	return &Session{
+		sessionUseCase: sessionUseCase,
+	}
+}
+
+func (s *Session) ListSessionController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	currentSessionID, _ := c.Locals("session_id").(string)
+
+	result, err := s.sessionUseCase.ListActiveSessions(userLocal.ID, currentSessionID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (s *Session) RevokeSessionController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := s.sessionUseCase.RevokeSession(userLocal.ID, c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	// session yang sedang dipakai ikut dicabut, hapus juga cookienya
+	if currentSessionID, _ := c.Locals("session_id").(string); currentSessionID == c.Params("id") {
+		clearAuthCookies(c)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.SessionRevoked)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RevokeAllSessionController sign out dari semua device termasuk device ini
+func (s *Session) RevokeAllSessionController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := s.sessionUseCase.RevokeAllSessions(userLocal.ID, domain_user_auth.SessionRevokedSignOut); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	clearAuthCookies(c)
+	response = payload.NewSuccessResponse(nil, errorenum.SessionsRevoked)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func clearAuthCookies(c *fiber.Ctx) {
+	for _, name := range []string{"access_token", "refresh_token", "logged_in"} {
+		c.Cookie(&fiber.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			MaxAge:   -1,
+			Secure:   true,
+			HTTPOnly: name != "logged_in",
+			SameSite: "None",
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package controller_user
+
+import (
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/api/routes/middleware"
+	"xops-admin/config"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+	jwttoken "xops-admin/util/token_jwt"
+)
+
+type SsoController struct {
+	ssoUseCase         domain_user_auth.SsoUseCase
+	sessionUseCase     domain_user_auth.SessionUseCase
+	lockoutUseCase     domain_user_auth.LockoutUseCase
+	authEventUseCase   domain_user_auth.AuthEventUseCase
+	knownDeviceUseCase domain_user_auth.KnownDeviceUseCase
+}
+
+func NewSsoController(ssoUseCase domain_user_auth.SsoUseCase, sessionUseCase domain_user_auth.SessionUseCase, lockoutUseCase domain_user_auth.LockoutUseCase, authEventUseCase domain_user_auth.AuthEventUseCase, knownDeviceUseCase domain_user_auth.KnownDeviceUseCase) *SsoController {
+	return &SsoController{
+		ssoUseCase:         ssoUseCase,
+		sessionUseCase:     sessionUseCase,
+		lockoutUseCase:     lockoutUseCase,
+		authEventUseCase:   authEventUseCase,
+		knownDeviceUseCase: knownDeviceUseCase,
+	}
+}
+
+// SsoLoginController mengarahkan browser ke halaman login IdP milik client
+func (s *SsoController) SsoLoginController(c *fiber.Ctx) error {
+	authURL, err := s.ssoUseCase.BeginLogin(c.UserContext(), c.Params("clientId"))
+	if err != nil {
+		return s.redirectWithError(c, err)
+	}
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// SsoCallbackController menerima redirect dari IdP, lalu menerbitkan cookie
+// access/refresh yang sama seperti login biasa
+func (s *SsoController) SsoCallbackController(c *fiber.Ctx) error {
+	ssoEvent := domain_user_auth.AuthEventInput{
+		EventType: model.AuthEventSsoLogin,
+		Outcome:   model.AuthOutcomeFailure,
+	}
+	if idpError := c.Query("error"); idpError != "" {
+		ssoEvent.Reason = "idp: " + idpError
+		recordAuthEvent(c, s.authEventUseCase, ssoEvent)
+		return s.redirectWithError(c, errorenum.SsoFailed)
+	}
+	user, err := s.ssoUseCase.CompleteLogin(c.UserContext(), c.Query("state"), c.Query("code"))
+	if err != nil {
+		ssoEvent.Reason = err.Error()
+		recordAuthEvent(c, s.authEventUseCase, ssoEvent)
+		return s.redirectWithError(c, err)
+	}
+	ssoEvent.IdUser = user.Id
+	ssoEvent.Email = user.Email
+	if err := s.lockoutUseCase.CheckLocked(user.Id); err != nil {
+		ssoEvent.Reason = "account_locked"
+		recordAuthEvent(c, s.authEventUseCase, ssoEvent)
+		return s.redirectWithError(c, err)
+	}
+
+	loadconfig, _ := config.LoadConfig(".")
+	accessTokenDetails, err := jwttoken.GenerateTokenJwt(loadconfig.AccessTokenExpiresIn, user.Id, loadconfig.AccessTokenPrivateKey)
+	if err != nil {
+		return s.redirectWithError(c, errorenum.SsoFailed)
+	}
+	session, refreshTokenDetails, err := s.sessionUseCase.StartSession(user, domain_user_auth.SessionMeta{
+		UserAgent: c.Get("User-Agent"),
+		IP:        middleware.GetPublicIP(c),
+	})
+	if err != nil {
+		return s.redirectWithError(c, errorenum.SsoFailed)
+	}
+	ssoEvent.Outcome = model.AuthOutcomeSuccess
+	ssoEvent.SessionID = session.Id
+	recordAuthEvent(c, s.authEventUseCase, ssoEvent)
+	s.knownDeviceUseCase.CheckSignIn(user, middleware.GetPublicIP(c), c.Get("User-Agent"))
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "access_token",
+		Value:    *accessTokenDetails.Token,
+		Path:     "/",
+		MaxAge:   loadconfig.AccessTokenMaxAge * 60,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: "None",
+	})
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    *refreshTokenDetails.Token,
+		Path:     "/",
+		MaxAge:   loadconfig.RefreshTokenMaxAge * 60,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: "None",
+	})
+	c.Cookie(&fiber.Cookie{
+		Name:     "logged_in",
+		Value:    "true",
+		Path:     "/",
+		MaxAge:   loadconfig.AccessTokenMaxAge * 60,
+		Secure:   true,
+		HTTPOnly: false,
+		SameSite: "None",
+	})
+	return c.Redirect(loadconfig.FrontendURL+"/sso/callback", fiber.StatusFound)
+}
+
+func (s *SsoController) UpdateSsoConfigController(c *fiber.Ctx) error {
+	var input domain_user_auth.SsoConfigRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := s.ssoUseCase.UpdateSsoConfig(c.Params("id"), &input); err != nil {
+		response = payload.NewErrorResponse(err)
+		if err == errorenum.DataNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(response)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.SsoConfigUpdated)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// callback SSO dibuka langsung oleh browser, jadi error dikirim ke frontend lewat query
+func (s *SsoController) redirectWithError(c *fiber.Ctx, err error) error {
+	loadconfig, _ := config.LoadConfig(".")
+	return c.Redirect(loadconfig.FrontendURL+"/sso/callback?error="+url.QueryEscape(err.Error()), fiber.StatusFound)
+}
@@ -0,0 +1,111 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type TwoFactor struct {
+	twoFactorUseCase domain_user_auth.TwoFactorUseCase
+}
+
+func NewTwoFactorController(twoFactorUseCase domain_user_auth.TwoFactorUseCase) *TwoFactor {
+	return &TwoFactor{
+		twoFactorUseCase: twoFactorUseCase,
+	}
+}
+
+func (t *TwoFactor) currentUser(c *fiber.Ctx) (*model.User, error) {
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		return nil, errorenum.Unauthorized
+	}
+	return t.twoFactorUseCase.FindUserBYID(userLocal.ID)
+}
+
+func (t *TwoFactor) StatusController(c *fiber.Ctx) error {
+	var response payload.Response
+	user, err := t.currentUser(c)
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := t.twoFactorUseCase.Status(user)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (t *TwoFactor) EnrollTOTPController(c *fiber.Ctx) error {
+	var response payload.Response
+	user, err := t.currentUser(c)
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := t.twoFactorUseCase.EnrollTOTP(user)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (t *TwoFactor) ConfirmTOTPController(c *fiber.Ctx) error {
+	var input domain_user_auth.TOTPConfirmRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	user, err := t.currentUser(c)
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := t.twoFactorUseCase.ConfirmTOTP(user, input.Code)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.TOTPEnabled)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (t *TwoFactor) DisableTOTPController(c *fiber.Ctx) error {
+	var input domain_user_auth.TOTPDisableRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	user, err := t.currentUser(c)
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := t.twoFactorUseCase.DisableTOTP(user, input.Password, input.Code); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.TOTPDisabled)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -3,6 +3,7 @@ package controller_user
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"xops-admin/api/routes/middleware"
 	"xops-admin/config"
 	domain_user_auth "xops-admin/domain/user/auth"
 	"xops-admin/helper/errorenum"
@@ -12,11 +13,23 @@ import (
 
 type Verified2fa struct {
 	verified2faUseCase domain_user_auth.Verified2faCase
+	sessionUseCase     domain_user_auth.SessionUseCase
+	lockoutUseCase     domain_user_auth.LockoutUseCase
+	// webauthnUseCase passkey sebagai faktor kedua: challenge dari WebauthnAssertionOptionsController,
+	// assertion-nya diverifikasi di verify-otp sebagai pengganti kode
+	webauthnUseCase    domain_user_auth.WebauthnUseCase
+	authEventUseCase   domain_user_auth.AuthEventUseCase
+	knownDeviceUseCase domain_user_auth.KnownDeviceUseCase
 }
 
-func NewVerified2faController(verified2faUseCase domain_user_auth.Verified2faCase) *Verified2fa {
+func NewVerified2faController(verified2faUseCase domain_user_auth.Verified2faCase, sessionUseCase domain_user_auth.SessionUseCase, lockoutUseCase domain_user_auth.LockoutUseCase, webauthnUseCase domain_user_auth.WebauthnUseCase, authEventUseCase domain_user_auth.AuthEventUseCase, knownDeviceUseCase domain_user_auth.KnownDeviceUseCase) *Verified2fa {
 	return &Verified2fa{
 		verified2faUseCase: verified2faUseCase,
+		sessionUseCase:     sessionUseCase,
+		lockoutUseCase:     lockoutUseCase,
+		webauthnUseCase:    webauthnUseCase,
+		authEventUseCase:   authEventUseCase,
+		knownDeviceUseCase: knownDeviceUseCase,
 	}
 }
 
@@ -38,7 +51,7 @@ func (v *Verified2fa) Verified2faControlller(c *fiber.Ctx) error {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
-	tokenClaims, err := v.verified2faUseCase.ValidateToken(access_token_cookies, loadconfig.AccessTokenPublicKey)
+	tokenClaims, err := v.verified2faUseCase.ValidateToken(access_token_cookies, loadconfig.AccessTokenKeyring())
 	if err != nil {
 		response = payload.NewErrorResponse(err.Error())
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -52,22 +65,34 @@ func (v *Verified2fa) Verified2faControlller(c *fiber.Ctx) error {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	verifiedTOTP := v.verified2faUseCase.VerifyTOTP(input.Code, user.TOTPKey)
+	if err := v.lockoutUseCase.CheckLocked(user.Id); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusLocked).JSON(response)
+	}
+	verifiedTOTP, err := v.verified2faUseCase.VerifyTOTP(user, input.Code)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
 	if !verifiedTOTP {
+		if err := v.lockoutUseCase.RegisterFailure(user, domain_user_auth.FailedAttemptOTP); err != nil {
+			response = payload.NewErrorResponse(err)
+			return c.Status(fiber.StatusLocked).JSON(response)
+		}
 		response = payload.NewErrorResponse(errorenum.CodeTidakValid)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
-	refreshTokenDetails, err := v.verified2faUseCase.GenerateTokenJwt(loadconfig.RefreshTokenExpiresIn, user.Id, loadconfig.RefreshTokenPrivateKey)
+	v.lockoutUseCase.ClearLock(user.Id)
+	_, refreshTokenDetails, err := v.sessionUseCase.StartSession(user, domain_user_auth.SessionMeta{
+		DeviceLabel: input.DeviceLabel,
+		UserAgent:   c.Get("User-Agent"),
+		IP:          middleware.GetPublicIP(c),
+	})
 	if err != nil {
 		response = payload.NewErrorResponse(err.Error())
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
-	// user, err = l.loginUseCase.SaveRefreshToken(*refreshTokenDetails.Token, user)
-	// if err != nil {
-	// 	response = payload.NewErrorResponse(err)
-	// 	return c.Status(fiber.StatusBadRequest).JSON(response)
-	// }
-	if err := v.verified2faUseCase.UpdateUser(user, *refreshTokenDetails.Token); err != nil {
+	if err := v.verified2faUseCase.UpdateUser(user); err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
@@ -91,3 +116,33 @@ func (v *Verified2fa) Verified2faControlller(c *fiber.Ctx) error {
 	response = payload.NewSuccessResponse(resultResponse, errorenum.OKSuccess)
 	return c.Status(fiber.StatusOK).JSON(response)
 }
+
+// WebauthnAssertionOptionsController challenge passkey untuk langkah kedua login,
+// memakai access_token hasil login seperti verify-otp
+func (v *Verified2fa) WebauthnAssertionOptionsController(c *fiber.Ctx) error {
+	var response payload.Response
+	loadconfig, _ := config.LoadConfig(".")
+	access_token_cookies := c.Cookies("access_token")
+
+	if access_token_cookies == "" {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	tokenClaims, err := v.verified2faUseCase.ValidateToken(access_token_cookies, loadconfig.AccessTokenKeyring())
+	if err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	user, err := v.verified2faUseCase.FindUserBYID(tokenClaims.UserID)
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := v.webauthnUseCase.BeginAssertion(user)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
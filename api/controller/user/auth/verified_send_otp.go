@@ -1,8 +1,11 @@
 package controller_user
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 
+	"xops-admin/api/routes/middleware"
 	"xops-admin/config"
 	domain_user_auth "xops-admin/domain/user/auth"
 	"xops-admin/helper/errorenum"
@@ -10,9 +13,14 @@ import (
 	"xops-admin/model"
 )
 
-func NewVerifiedOtPfaController(verified2faUseCase domain_user_auth.Verified2faCase) *Verified2fa {
+func NewVerifiedOtPfaController(verified2faUseCase domain_user_auth.Verified2faCase, sessionUseCase domain_user_auth.SessionUseCase, lockoutUseCase domain_user_auth.LockoutUseCase, webauthnUseCase domain_user_auth.WebauthnUseCase, authEventUseCase domain_user_auth.AuthEventUseCase, knownDeviceUseCase domain_user_auth.KnownDeviceUseCase) *Verified2fa {
 	return &Verified2fa{
 		verified2faUseCase: verified2faUseCase,
+		sessionUseCase:     sessionUseCase,
+		lockoutUseCase:     lockoutUseCase,
+		webauthnUseCase:    webauthnUseCase,
+		authEventUseCase:   authEventUseCase,
+		knownDeviceUseCase: knownDeviceUseCase,
 	}
 }
 func (v *Verified2fa) SendOtpVerifedCode(c *fiber.Ctx) error {
@@ -29,7 +37,7 @@ func (v *Verified2fa) SendOtpVerifedCode(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 	if access_token_cookies != "" {
-		tokenClaims, err := v.verified2faUseCase.ValidateToken(access_token_cookies, loadconfig.AccessTokenPublicKey)
+		tokenClaims, err := v.verified2faUseCase.ValidateToken(access_token_cookies, loadconfig.AccessTokenKeyring())
 		if err != nil {
 			response = payload.NewErrorResponse(err.Error())
 			return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -55,8 +63,29 @@ func (v *Verified2fa) SendOtpVerifedCode(c *fiber.Ctx) error {
 		}
 	}
 
-	v.verified2faUseCase.SendOtpVerifedCode(user)
-	response = payload.NewSuccessResponse(nil, errorenum.SendOtp)
+	result, err := v.verified2faUseCase.SendOtpVerifedCode(user)
+	otpEvent := domain_user_auth.AuthEventInput{
+		IdUser:    user.Id,
+		Email:     user.Email,
+		EventType: model.AuthEventOtpSend,
+		Outcome:   model.AuthOutcomeSuccess,
+	}
+	if err != nil {
+		otpEvent.Outcome = model.AuthOutcomeFailure
+		otpEvent.Reason = err.Error()
+	}
+	recordAuthEvent(c, v.authEventUseCase, otpEvent)
+	if err == errorenum.OtpResendCooldown {
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(result.ResendAfterSeconds, 10))
+		response = payload.NewErrorResponse(err)
+		response.Data = result
+		return c.Status(fiber.StatusTooManyRequests).JSON(response)
+	}
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.SendOtp)
 	return c.Status(fiber.StatusOK).JSON(response)
 
 }
@@ -78,7 +107,7 @@ func (v *Verified2fa) VerifiedOtpControlller(c *fiber.Ctx) error {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
-	tokenClaims, err := v.verified2faUseCase.ValidateToken(access_token_cookies, loadconfig.AccessTokenPublicKey)
+	tokenClaims, err := v.verified2faUseCase.ValidateToken(access_token_cookies, loadconfig.AccessTokenKeyring())
 	if err != nil {
 		response = payload.NewErrorResponse(err.Error())
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -97,23 +126,68 @@ func (v *Verified2fa) VerifiedOtpControlller(c *fiber.Ctx) error {
 	// 	response = payload.NewErrorResponse(errorenum.CodeTidakValid)
 	// 	return c.Status(fiber.StatusBadRequest).JSON(response)
 	// }
-	userData, err := v.verified2faUseCase.UserVerifyOtp(user.Id, input.Code, 5)
+	verifyEvent := domain_user_auth.AuthEventInput{
+		IdUser:    user.Id,
+		Email:     user.Email,
+		EventType: model.AuthEventOtpVerify,
+		Outcome:   model.AuthOutcomeFailure,
+	}
+	if input.Webauthn != nil {
+		verifyEvent.EventType = model.AuthEventPasskeyVerify
+	}
+	if err := v.lockoutUseCase.CheckLocked(user.Id); err != nil {
+		verifyEvent.Reason = "account_locked"
+		recordAuthEvent(c, v.authEventUseCase, verifyEvent)
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusLocked).JSON(response)
+	}
+	passkeyRequired := v.webauthnUseCase.PasskeyRequired(user)
+	hasPasskey := v.webauthnUseCase.HasCredentials(user.Id)
+	var userData *model.User
+	if input.Webauthn != nil {
+		// assertion passkey menggantikan kode OTP
+		if !user.IsVerified {
+			response = payload.NewErrorResponse(errorenum.Unauthorized)
+			return c.Status(fiber.StatusUnauthorized).JSON(response)
+		}
+		err = v.webauthnUseCase.VerifyAssertion(user, input.Webauthn)
+		userData = user
+	} else if passkeyRequired && hasPasskey {
+		verifyEvent.Reason = "passkey_required"
+		recordAuthEvent(c, v.authEventUseCase, verifyEvent)
+		response = payload.NewErrorResponse(errorenum.PasskeyRequired)
+		return c.Status(fiber.StatusForbidden).JSON(response)
+	} else {
+		userData, err = v.verified2faUseCase.UserVerifyOtp(user.Id, input.Code)
+	}
 	if err != nil {
+		verifyEvent.Reason = err.Error()
+		recordAuthEvent(c, v.authEventUseCase, verifyEvent)
+		if err := v.lockoutUseCase.RegisterFailure(user, domain_user_auth.FailedAttemptOTP); err != nil {
+			response = payload.NewErrorResponse(err)
+			return c.Status(fiber.StatusLocked).JSON(response)
+		}
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
+	v.lockoutUseCase.ClearLock(userData.Id)
 
-	refreshTokenDetails, err := v.verified2faUseCase.GenerateTokenJwt(loadconfig.RefreshTokenExpiresIn, userData.Id, loadconfig.RefreshTokenPrivateKey)
+	session, refreshTokenDetails, err := v.sessionUseCase.StartSession(userData, domain_user_auth.SessionMeta{
+		DeviceLabel: input.DeviceLabel,
+		UserAgent:   c.Get("User-Agent"),
+		IP:          middleware.GetPublicIP(c),
+	})
 	if err != nil {
 		response = payload.NewErrorResponse(err.Error())
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
-	// user, err = l.loginUseCase.SaveRefreshToken(*refreshTokenDetails.Token, user)
-	// if err != nil {
-	// 	response = payload.NewErrorResponse(err)
-	// 	return c.Status(fiber.StatusBadRequest).JSON(response)
-	// }
-	if err := v.verified2faUseCase.UpdateUser(userData, *refreshTokenDetails.Token); err != nil {
+	verifyEvent.Outcome = model.AuthOutcomeSuccess
+	verifyEvent.SessionID = session.Id
+	recordAuthEvent(c, v.authEventUseCase, verifyEvent)
+	v.knownDeviceUseCase.CheckSignIn(userData, middleware.GetPublicIP(c), c.Get("User-Agent"))
+	// client mewajibkan passkey tapi user belum punya: login dengan OTP hanya untuk mendaftarkannya
+	userData.MustRegisterPasskey = passkeyRequired && !hasPasskey
+	if err := v.verified2faUseCase.UpdateUser(userData); err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
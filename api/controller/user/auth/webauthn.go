@@ -0,0 +1,138 @@
+package controller_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type Webauthn struct {
+	webauthnUseCase domain_user_auth.WebauthnUseCase
+}
+
+func NewWebauthnController(webauthnUseCase domain_user_auth.WebauthnUseCase) *Webauthn {
+	return &Webauthn{
+		webauthnUseCase: webauthnUseCase,
+	}
+}
+
+func (w *Webauthn) RegisterOptionsController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := w.webauthnUseCase.BeginRegistration(userLocal.ID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (w *Webauthn) RegisterController(c *fiber.Ctx) error {
+	var input domain_user_auth.WebauthnRegisterRequest
+	var response payload.Response
+
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := w.webauthnUseCase.FinishRegistration(userLocal.ID, &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.PasskeyRegistered)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func (w *Webauthn) ListCredentialController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := w.webauthnUseCase.ListCredentials(userLocal.ID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (w *Webauthn) RenameCredentialController(c *fiber.Ctx) error {
+	var input domain_user_auth.WebauthnRenameRequest
+	var response payload.Response
+
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := w.webauthnUseCase.RenameCredential(userLocal.ID, c.Params("id"), input.Name); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (w *Webauthn) DeleteCredentialController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := w.webauthnUseCase.DeleteCredential(userLocal.ID, c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		if err == errorenum.LastPasskeyRequired {
+			return c.Status(fiber.StatusConflict).JSON(response)
+		}
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.PasskeyRemoved)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// PasskeyPolicyController admin mewajibkan / melepas passkey untuk satu client
+func (w *Webauthn) PasskeyPolicyController(c *fiber.Ctx) error {
+	var input domain_user_auth.PasskeyPolicyRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := w.webauthnUseCase.SetPasskeyPolicy(c.Params("id"), input.Required); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(input, errorenum.PasskeyPolicyUpdated)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
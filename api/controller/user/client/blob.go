@@ -0,0 +1,47 @@
+package controller_client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	util_blobstore "xops-admin/util/blob_store"
+)
+
+type BlobHandler struct {
+	store *util_blobstore.LocalStore
+}
+
+func NewBlobHandler(store *util_blobstore.LocalStore) *BlobHandler {
+	return &BlobHandler{
+		store: store,
+	}
+}
+
+// DownloadBlobController mengirim file blob lokal jika token di link masih berlaku, tanpa login
+func (h *BlobHandler) DownloadBlobController(c *fiber.Ctx) error {
+	var response payload.Response
+	key, err := h.store.KeyFromToken(c.Query("token"))
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.InvalidBlobLink)
+		return c.Status(fiber.StatusForbidden).JSON(response)
+	}
+	file, contentType, err := h.store.Open(context.TODO(), key)
+	if errors.Is(err, util_blobstore.ErrNotFound) {
+		response = payload.NewErrorResponse(errorenum.DataNotFound)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.SomethingError)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	// fiber menutup file setelah isinya terkirim
+	return c.Status(fiber.StatusOK).SendStream(file)
+}
@@ -0,0 +1,121 @@
+package controller_client
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type EngagementHandler struct {
+	usecase domain_client.EngagementUseCase
+}
+
+func NewEngagementHandler(u domain_client.EngagementUseCase) *EngagementHandler {
+	return &EngagementHandler{
+		usecase: u,
+	}
+}
+
+func engagementErrorStatus(err error) int {
+	switch err {
+	case errorenum.NotClientMember:
+		return fiber.StatusForbidden
+	case errorenum.DataNotFound, errorenum.EngagementNotFound:
+		return fiber.StatusNotFound
+	case errorenum.SomethingError:
+		return fiber.StatusInternalServerError
+	}
+	return fiber.StatusBadRequest
+}
+
+// ListClientEngagementsController engagement milik organisasi user yang login
+func (h *EngagementHandler) ListClientEngagementsController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := h.usecase.ListClientEngagements(userLocal.ID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(engagementErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *EngagementHandler) ListEngagementsController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := h.usecase.ListEngagements(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(engagementErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *EngagementHandler) DetailEngagementController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := h.usecase.GetEngagement(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(engagementErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *EngagementHandler) CreateEngagementController(c *fiber.Ctx) error {
+	var input domain_client.EngagementRequest
+	var response payload.Response
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := h.usecase.CreateEngagement(c.Params("id"), &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(engagementErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.EngagementCreated)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func (h *EngagementHandler) UpdateEngagementController(c *fiber.Ctx) error {
+	var input domain_client.EngagementRequest
+	var response payload.Response
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := h.usecase.UpdateEngagement(c.Params("id"), &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(engagementErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.EngagementUpdated)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *EngagementHandler) DeleteEngagementController(c *fiber.Ctx) error {
+	var response payload.Response
+	if err := h.usecase.DeleteEngagement(c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(engagementErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.EngagementDeleted)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,136 @@
+package controller_client
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type ClientMemberHandler struct {
+	usecase domain_client.ClientMemberUseCase
+}
+
+func NewClientMemberHandler(u domain_client.ClientMemberUseCase) *ClientMemberHandler {
+	return &ClientMemberHandler{
+		usecase: u,
+	}
+}
+
+// memberErrorStatus membedakan user yang bukan owner dari data yang tidak ditemukan
+func memberErrorStatus(err error) int {
+	switch err {
+	case errorenum.NotClientMember, errorenum.NotClientOwner, errorenum.ContractEnded:
+		return fiber.StatusForbidden
+	case errorenum.DataNotFound:
+		return fiber.StatusNotFound
+	case errorenum.SomethingError:
+		return fiber.StatusInternalServerError
+	}
+	return fiber.StatusBadRequest
+}
+
+func (h *ClientMemberHandler) ListMembersController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := h.usecase.ListMembers(userLocal.ID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(memberErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientMemberHandler) InviteController(c *fiber.Ctx) error {
+	var input domain_client.InviteMemberRequest
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	invitation, err := h.usecase.Invite(userLocal.ID, &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(memberErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(invitation, errorenum.InvitationSent)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func (h *ClientMemberHandler) RevokeInvitationController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := h.usecase.RevokeInvitation(userLocal.ID, c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(memberErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.InvitationRevoked)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientMemberHandler) RemoveMemberController(c *fiber.Ctx) error {
+	var response payload.Response
+	userLocal, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if err := h.usecase.RemoveMember(userLocal.ID, c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(memberErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.MemberRemoved)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// PreviewInvitationController dipanggil frontend saat membuka link undangan
+func (h *ClientMemberHandler) PreviewInvitationController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := h.usecase.PreviewInvitation(c.Query("token"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(memberErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientMemberHandler) AcceptInvitationController(c *fiber.Ctx) error {
+	var input domain_client.AcceptInvitationRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := h.usecase.AcceptInvitation(&input); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(memberErrorStatus(err)).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.InvitationAccepted)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
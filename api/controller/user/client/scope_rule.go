@@ -0,0 +1,61 @@
+package controller_client
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+func (h *ClientUserHandler) ListScopeRulesController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := h.usecase.ListScopeRules(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		if err == errorenum.DataNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(response)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientUserHandler) AddScopeRuleController(c *fiber.Ctx) error {
+	var input domain_client.ScopeRuleRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := h.usecase.AddScopeRule(c.Params("id"), &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		switch err {
+		case errorenum.DataNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(response)
+		case errorenum.SomethingError:
+			return c.Status(fiber.StatusInternalServerError).JSON(response)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.ScopeRuleAdded)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func (h *ClientUserHandler) RemoveScopeRuleController(c *fiber.Ctx) error {
+	var response payload.Response
+	if err := h.usecase.RemoveScopeRule(c.Params("id"), c.Params("ruleId")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.ScopeRuleRemoved)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
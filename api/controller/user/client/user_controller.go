@@ -1,27 +1,30 @@
 package controller_client
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/gofiber/fiber/v2"
 
-	"xops-admin/config"
 	domain_client "xops-admin/domain/user/client"
 	domain_user "xops-admin/domain/user/client"
+	domain_overview "xops-admin/domain/user/overview"
 	"xops-admin/helper/errorenum"
 	"xops-admin/helper/payload"
-	util_jwttoken "xops-admin/util/token_jwt"
+	"xops-admin/model"
 )
 
 type ClientUserHandler struct {
 	usecase       domain_user.ClientUseCase
+	scope         domain_overview.ScopeUseCase
 	elasticSearch *elasticsearch.Client
 }
 
-func NewClientUserHandler(u domain_user.ClientUseCase, es *elasticsearch.Client) *ClientUserHandler {
+func NewClientUserHandler(u domain_user.ClientUseCase, scope domain_overview.ScopeUseCase, es *elasticsearch.Client) *ClientUserHandler {
 	return &ClientUserHandler{
 		usecase:       u,
+		scope:         scope,
 		elasticSearch: es,
 	}
 }
@@ -35,6 +38,14 @@ func (h *ClientUserHandler) CreateClient(c *fiber.Ctx) error {
 	req.Email = c.FormValue("email")
 	req.StartDate = c.FormValue("start_date")
 	req.EndDate = c.FormValue("end_date")
+	if idRole := c.FormValue("id_role"); idRole != "" {
+		parsed, err := strconv.Atoi(idRole)
+		if err != nil {
+			response = payload.NewErrorResponse(errorenum.InvalidRole)
+			return c.Status(fiber.StatusBadRequest).JSON(response)
+		}
+		req.IdRole = parsed
+	}
 
 	req.IsVerified = true
 	req.IsTwoFA = true
@@ -65,6 +76,12 @@ func (h *ClientUserHandler) CreateClient(c *fiber.Ctx) error {
 	clientResponse, err := h.usecase.CreateUserClient(&req)
 	if err != nil {
 		response = payload.NewErrorResponse(err.Error())
+		switch err {
+		case errorenum.InvalidLogo, errorenum.LogoTooLarge, errorenum.InvalidRole:
+			return c.Status(fiber.StatusBadRequest).JSON(response)
+		case errorenum.RoleNotClientScoped:
+			return c.Status(fiber.StatusForbidden).JSON(response)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(response)
 	}
 
@@ -73,21 +90,19 @@ func (h *ClientUserHandler) CreateClient(c *fiber.Ctx) error {
 }
 func (h *ClientUserHandler) GetDomainClient(c *fiber.Ctx) error {
 	var response payload.Response
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := h.usecase.GetDomainByClientID(id.UserID)
+	domains, err := h.scope.ResolveDomains(id.ID, c.Query("domain"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 
 	// Create client (this will create user first, then client)
-	clientResponse, err := h.usecase.GetClientWithLastPentest(id.UserID, nameDomain.Domain, h.elasticSearch)
+	clientResponse, err := h.usecase.GetClientWithLastPentest(id.ID, domains, h.elasticSearch)
 	if err != nil {
 		response = payload.NewErrorResponse(err.Error())
 		return c.Status(fiber.StatusInternalServerError).JSON(response)
@@ -96,3 +111,204 @@ func (h *ClientUserHandler) GetDomainClient(c *fiber.Ctx) error {
 	response = payload.NewSuccessResponse(clientResponse, errorenum.OKSuccess)
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
+
+// ListDomainsController domain aktif yang bisa dipilih lewat query param "domain"
+func (h *ClientUserHandler) ListDomainsController(c *fiber.Ctx) error {
+	var response payload.Response
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := h.usecase.ListDomains(id.ID)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientUserHandler) SearchClientsController(c *fiber.Ctx) error {
+	var input domain_client.ClientSearchRequest
+	var response payload.Response
+
+	if err := c.QueryParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := h.usecase.SearchClients(&input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientUserHandler) DetailClientController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := h.usecase.GetClient(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateClientController multipart seperti CreateClient, field kosong tidak diubah
+func (h *ClientUserHandler) UpdateClientController(c *fiber.Ctx) error {
+	var response payload.Response
+	var req domain_client.UpdateClientRequest
+
+	req.CompanyName = strings.TrimSpace(c.FormValue("company_name"))
+	req.Email = strings.TrimSpace(c.FormValue("email"))
+	req.StartDate = c.FormValue("start_date")
+	req.EndDate = c.FormValue("end_date")
+	if file, err := c.FormFile("logo"); err == nil {
+		req.Logo = file
+	}
+
+	if err := h.usecase.UpdateUserClient(c.Params("id"), &req); err != nil {
+		response = payload.NewErrorResponse(err)
+		switch err {
+		case errorenum.DataNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(response)
+		case errorenum.DuplicateEmail:
+			return c.Status(fiber.StatusConflict).JSON(response)
+		case errorenum.SomethingError:
+			return c.Status(fiber.StatusInternalServerError).JSON(response)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.ClientUpdated)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientUserHandler) DeleteClientController(c *fiber.Ctx) error {
+	var response payload.Response
+	if err := h.usecase.DeleteClient(c.Params("id")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.ClientDeleted)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientUserHandler) AddDomainController(c *fiber.Ctx) error {
+	var input domain_client.AddDomainRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := h.usecase.AddDomain(c.Params("id"), &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		switch err {
+		case errorenum.DataNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(response)
+		case errorenum.DuplicateDomain:
+			return c.Status(fiber.StatusConflict).JSON(response)
+		case errorenum.SomethingError:
+			return c.Status(fiber.StatusInternalServerError).JSON(response)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.DomainAdded)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+func (h *ClientUserHandler) SetDomainActiveController(c *fiber.Ctx) error {
+	var input domain_client.SetDomainActiveRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := h.usecase.SetDomainActive(c.Params("id"), c.Params("domainId"), *input.Active); err != nil {
+		response = payload.NewErrorResponse(err)
+		if err == errorenum.DomainNotVerified {
+			return c.Status(fiber.StatusConflict).JSON(response)
+		}
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.DomainUpdated)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientUserHandler) RemoveDomainController(c *fiber.Ctx) error {
+	var response payload.Response
+	if err := h.usecase.RemoveDomain(c.Params("id"), c.Params("domainId")); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.DomainRemoved)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+func (h *ClientUserHandler) DomainVerificationController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := h.usecase.DomainVerification(c.Params("id"), c.Params("domainId"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// VerifyDomainController cek ulang kepemilikan, hasil gagal tetap 200 dengan detail error-nya
+func (h *ClientUserHandler) VerifyDomainController(c *fiber.Ctx) error {
+	var response payload.Response
+	result, err := h.usecase.VerifyDomain(c.UserContext(), c.Params("id"), c.Params("domainId"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		if err == errorenum.DataNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(response)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	message := errorenum.DomainVerified
+	if result.Status != model.DomainVerificationVerified {
+		message = errorenum.DomainVerificationFailed
+	}
+	response = payload.NewSuccessResponse(result, message)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// UpdateContractController memperpanjang atau memperbarui jendela kontrak client
+func (h *ClientUserHandler) UpdateContractController(c *fiber.Ctx) error {
+	var input domain_client.ContractWindowRequest
+	var response payload.Response
+
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	result, err := h.usecase.UpdateContractWindow(c.Params("id"), &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		if err == errorenum.DataNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(response)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.ContractUpdated)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -9,34 +9,33 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
-	"xops-admin/config"
 	"xops-admin/domain"
 	domain_listbug "xops-admin/domain/user/list_bug"
+	domain_overview "xops-admin/domain/user/overview"
 	"xops-admin/helper/errorenum"
 	"xops-admin/helper/payload"
-	util_jwttoken "xops-admin/util/token_jwt"
+	"xops-admin/model"
 )
 
 type ListBugTableHandler struct {
 	usecase domain_listbug.ListBugUseCase
+	scope   domain_overview.ScopeUseCase
 }
 
-func NewListBugTableHandler(u domain_listbug.ListBugUseCase) *ListBugTableHandler {
-	return &ListBugTableHandler{usecase: u}
+func NewListBugTableHandler(u domain_listbug.ListBugUseCase, scope domain_overview.ScopeUseCase) *ListBugTableHandler {
+	return &ListBugTableHandler{usecase: u, scope: scope}
 }
 
 func (h *ListBugTableHandler) List(c *fiber.Ctx) error {
 	var response payload.Response
 
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 
-	nameDomain, err := h.usecase.GetDomainByClientID(id.UserID)
+	scope, err := h.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -83,9 +82,10 @@ func (h *ListBugTableHandler) List(c *fiber.Ctx) error {
 		Direction: direction,
 
 		// Filter parameters
-		Severity:   c.Query("severity"),
-		Status:     c.Query("status"),
-		FlagDomain: nameDomain.Domain,
+		Severity:     c.Query("severity"),
+		Status:       c.Query("status"),
+		FlagDomains:  scope.Domains,
+		IdEngagement: scope.IdEngagement,
 
 		// Search parameter
 		Search: strings.TrimSpace(c.Query("search")),
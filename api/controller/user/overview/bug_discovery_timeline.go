@@ -9,47 +9,47 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
-	"xops-admin/config"
 	domain_overview "xops-admin/domain/user/overview"
 	"xops-admin/helper/errorenum"
 	"xops-admin/helper/payload"
-	util_jwttoken "xops-admin/util/token_jwt"
+	"xops-admin/model"
 )
 
 type BugDiscoveryTimelineHandler struct {
 	service domain_overview.BugDiscoveryTimelineUseCase
+	scope   domain_overview.ScopeUseCase
 }
 
-func NewBugDiscoveryTimelineHandler(service domain_overview.BugDiscoveryTimelineUseCase) *BugDiscoveryTimelineHandler {
+func NewBugDiscoveryTimelineHandler(service domain_overview.BugDiscoveryTimelineUseCase, scope domain_overview.ScopeUseCase) *BugDiscoveryTimelineHandler {
 	return &BugDiscoveryTimelineHandler{
 		service: service,
+		scope:   scope,
 	}
 }
 
 // Existing endpoint - Chart 1: Vulnerability Timeline
 func (l *BugDiscoveryTimelineHandler) BugDiscoveryTimelineController(c *fiber.Ctx) error {
 	var response payload.Response
-	period := c.QueryInt("period")
-	filter := c.Query("filter")
-	if period == 0 {
-		period = 30
+	rng, err := chartRange(c, 30)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
+	filter := c.Query("filter")
 	if filter == "all_severity" {
 		filter = ""
 	}
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	chartData, err := l.service.GetVulnerabilityChart(context.TODO(), period, nameDomain.Domain, filter)
+	chartData, err := l.service.GetVulnerabilityChart(context.TODO(), rng, scope, filter)
 	if err != nil || chartData == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -63,25 +63,27 @@ func (l *BugDiscoveryTimelineHandler) BugDiscoveryTimelineController(c *fiber.Ct
 func (l *BugDiscoveryTimelineHandler) BugSeverityDistributionController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	period := c.QueryInt("period")
+	rng, err := chartRange(c, 0)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
 	status := c.Query("status")
 	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 	if status == "all_severity" {
 		status = ""
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	distributions, err := l.service.GetBugSeverityDistribution(context.TODO(), nameDomain.Domain, period, status)
+	distributions, err := l.service.GetBugSeverityDistribution(context.TODO(), scope, rng, status)
 	if err != nil || distributions == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -95,17 +97,19 @@ func (l *BugDiscoveryTimelineHandler) BugSeverityDistributionController(c *fiber
 func (l *BugDiscoveryTimelineHandler) BugStatusDistributionController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	period := c.QueryInt("period")
+	rng, err := chartRange(c, 0)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
 	status := c.Query("status")
 	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -113,7 +117,7 @@ func (l *BugDiscoveryTimelineHandler) BugStatusDistributionController(c *fiber.C
 	if status == "all_status" {
 		status = ""
 	}
-	distributions, err := l.service.GetBugStatusDistribution(context.TODO(), nameDomain.Domain, period, status)
+	distributions, err := l.service.GetBugStatusDistribution(context.TODO(), scope, rng, status)
 	if err != nil || distributions == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -127,17 +131,19 @@ func (l *BugDiscoveryTimelineHandler) BugStatusDistributionController(c *fiber.C
 func (l *BugDiscoveryTimelineHandler) BugValidationDistributionController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	period := c.QueryInt("period")
+	rng, err := chartRange(c, 0)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
 	status := c.Query("status")
 	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -145,7 +151,7 @@ func (l *BugDiscoveryTimelineHandler) BugValidationDistributionController(c *fib
 	if status == "all_validation" {
 		status = ""
 	}
-	distributions, err := l.service.GetBugValidationDistribution(context.TODO(), nameDomain.Domain, period, status)
+	distributions, err := l.service.GetBugValidationDistribution(context.TODO(), scope, rng, status)
 	if err != nil || distributions == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -159,21 +165,23 @@ func (l *BugDiscoveryTimelineHandler) BugValidationDistributionController(c *fib
 func (l *BugDiscoveryTimelineHandler) HostBugsExposureController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	period := c.QueryInt("period")
-	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
+	rng, err := chartRange(c, 0)
 	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	// JWT Token validation
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	exposure, err := l.service.GetHostBugsExposure(context.TODO(), nameDomain.Domain, period)
+	exposure, err := l.service.GetHostBugsExposure(context.TODO(), scope, rng)
 	if err != nil || exposure == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -187,23 +195,25 @@ func (l *BugDiscoveryTimelineHandler) HostBugsExposureController(c *fiber.Ctx) e
 func (l *BugDiscoveryTimelineHandler) PentestersActivityStatsController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	period := c.QueryInt("period")
-	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
+	rng, err := chartRange(c, 0)
 	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	// JWT Token validation
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 
-	activity, err := l.service.GetPentestersActivityStats(context.TODO(), nameDomain.Domain, period)
+	activity, err := l.service.GetPentestersActivityStats(context.TODO(), scope, rng)
 	if err != nil || activity == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -218,20 +228,22 @@ func (l *BugDiscoveryTimelineHandler) BugTypeFrequencyController(c *fiber.Ctx) e
 	var response payload.Response
 
 	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	period := c.QueryInt("period")
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	rng, err := chartRange(c, 0)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	frequency, err := l.service.GetBugTypeFrequency(context.TODO(), nameDomain.Domain, period)
+	frequency, err := l.service.GetBugTypeFrequency(context.TODO(), scope, rng)
 	if err != nil || frequency == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -245,19 +257,22 @@ func (l *BugDiscoveryTimelineHandler) GetTotalFindingsWithTrendController(c *fib
 	var response payload.Response
 
 	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	frequency, err := l.service.GetTotalFindingsWithTrend(context.TODO(), nameDomain.Domain)
+	rng, err := chartRange(c, 0)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	frequency, err := l.service.GetTotalFindingsWithTrend(context.TODO(), scope, rng)
 	if err != nil || frequency == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -271,19 +286,22 @@ func (l *BugDiscoveryTimelineHandler) PentesterEffectivenessController(c *fiber.
 	var response payload.Response
 
 	// JWT Token validation
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	frequency, err := l.service.GetRealTimePentesterStatus(context.TODO(), nameDomain.Domain)
+	rng, err := chartRange(c, 0)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	frequency, err := l.service.GetRealTimePentesterStatus(context.TODO(), scope, rng)
 	if err != nil || frequency == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -295,27 +313,30 @@ func (l *BugDiscoveryTimelineHandler) PentesterEffectivenessController(c *fiber.
 func (l *BugDiscoveryTimelineHandler) GetLogActivityController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 
+	// start_date / end_date lama dipetakan ke from / to, formatnya sama (YYYY-MM-DD)
+	rng, err := domain_overview.ParseChartRange(c.Query("from", c.Query("start_date")), c.Query("to", c.Query("end_date")), "", c.QueryInt("period"), 0, time.Now())
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
 	params := domain_overview.LogActivityPaginationParams{
-		Domain:    nameDomain.Domain,
-		EndDate:   c.Query("end_date"),
-		StartDate: c.Query("start_date"),
-		Search:    c.Query("search"),
+		Scope:  scope,
+		Range:  rng,
+		Search: c.Query("search"),
 	}
 
-	// Load semua data (default 1 bulan, desc by time)
+	// Load semua data di range (desc by time)
 	frequency, err := l.service.GetLogActivity(context.TODO(), params)
 	if err != nil || frequency == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
@@ -349,6 +370,12 @@ func (l *BugDiscoveryTimelineHandler) GetLogActivityController(c *fiber.Ctx) err
 
 }
 
+// chartRange membaca query param from, to dan interval; period (N hari terakhir) masih diterima
+// untuk klien lama. defaultDays dipakai jika keduanya kosong, 0 berarti semua waktu.
+func chartRange(c *fiber.Ctx, defaultDays int) (domain_overview.ChartRange, error) {
+	return domain_overview.ParseChartRange(c.Query("from"), c.Query("to"), c.Query("interval"), c.QueryInt("period"), defaultDays, time.Now())
+}
+
 // Helper functions (existing)
 func addAlpha(hex string, alpha int) string {
 	if alpha < 0 {
@@ -0,0 +1,146 @@
+package controller_role
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	domain_role "xops-admin/domain/user/role"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+type RoleHandler struct {
+	usecase domain_role.RoleUseCase
+}
+
+func NewRoleHandler(u domain_role.RoleUseCase) *RoleHandler {
+	return &RoleHandler{usecase: u}
+}
+
+// List
+func (h *RoleHandler) List(c *fiber.Ctx) error {
+	var response payload.Response
+	roles, err := h.usecase.List()
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.SomethingError)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(roles, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetByID
+func (h *RoleHandler) GetByID(c *fiber.Ctx) error {
+	var response payload.Response
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.InvalidRole)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	role, err := h.usecase.GetByID(id)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusNotFound).JSON(response)
+	}
+	response = payload.NewSuccessResponse(role, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// Create
+func (h *RoleHandler) Create(c *fiber.Ctx) error {
+	var input domain_role.RoleRequest
+	var response payload.Response
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	role, err := h.usecase.Create(&input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(role, errorenum.OKSuccess)
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// Update
+func (h *RoleHandler) Update(c *fiber.Ctx) error {
+	var input domain_role.RoleRequest
+	var response payload.Response
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.InvalidRole)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := model.ValidateStruct(input); len(err) > 0 {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	role, err := h.usecase.Update(id, &input)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(role, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SetPermissions mengganti seluruh permission milik role
+func (h *RoleHandler) SetPermissions(c *fiber.Ctx) error {
+	var input domain_role.RolePermissionsRequest
+	var response payload.Response
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.InvalidRole)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := c.BodyParser(&input); err != nil {
+		response = payload.NewErrorResponse(err.Error())
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	role, err := h.usecase.SetPermissions(id, input.Permissions)
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(role, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// Delete
+func (h *RoleHandler) Delete(c *fiber.Ctx) error {
+	var response payload.Response
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.InvalidRole)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	if err := h.usecase.Delete(id); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusBadRequest).JSON(response)
+	}
+	response = payload.NewSuccessResponse(nil, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ListPermissions
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	var response payload.Response
+	permissions, err := h.usecase.ListPermissions()
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.SomethingError)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(permissions, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
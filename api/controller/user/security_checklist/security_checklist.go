@@ -7,39 +7,38 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
-	"xops-admin/config"
 	domain_overview "xops-admin/domain/user/overview"
 	"xops-admin/helper/errorenum"
 	"xops-admin/helper/payload"
-	util_jwttoken "xops-admin/util/token_jwt"
+	"xops-admin/model"
 )
 
 type SecurityCheklistHandler struct {
 	service domain_overview.SecurityCheklistUseCase
+	scope   domain_overview.ScopeUseCase
 }
 
-func NewSecurityCheklistHandler(service domain_overview.SecurityCheklistUseCase) *SecurityCheklistHandler {
+func NewSecurityCheklistHandler(service domain_overview.SecurityCheklistUseCase, scope domain_overview.ScopeUseCase) *SecurityCheklistHandler {
 	return &SecurityCheklistHandler{
 		service: service,
+		scope:   scope,
 	}
 }
 
 func (l *SecurityCheklistHandler) GetTotalFindingsController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	chartData, err := l.service.GetTotalFindings(context.TODO(), nameDomain.Domain)
+	chartData, err := l.service.GetTotalFindings(context.TODO(), scope)
 	if err != nil || chartData == nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusNotFound).JSON(response)
@@ -51,19 +50,17 @@ func (l *SecurityCheklistHandler) GetTotalFindingsController(c *fiber.Ctx) error
 func (l *SecurityCheklistHandler) GetTotalBugStatusListController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
 	}
-	result, err := l.service.GetTotalBugStatusList(context.TODO(), nameDomain.Domain)
+	result, err := l.service.GetTotalBugStatusList(context.TODO(), scope)
 	if err != nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
@@ -74,14 +71,12 @@ func (l *SecurityCheklistHandler) GetTotalBugStatusListController(c *fiber.Ctx)
 func (l *SecurityCheklistHandler) GetSecurityChecklistTableController(c *fiber.Ctx) error {
 	var response payload.Response
 
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -128,7 +123,7 @@ func (l *SecurityCheklistHandler) GetSecurityChecklistTableController(c *fiber.C
 			params.SortOrder = sortOrder
 		}
 	}
-	result, err := l.service.GetSecurityChecklistTable(context.TODO(), nameDomain.Domain, params)
+	result, err := l.service.GetSecurityChecklistTable(context.TODO(), scope, params)
 	if err != nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
@@ -145,8 +140,18 @@ func (l *SecurityCheklistHandler) GetSecurityChecklistTableController(c *fiber.C
 func (l *SecurityCheklistHandler) GetSecurityChecklistTableDetailIdController(c *fiber.Ctx) error {
 	var response payload.Response
 
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	scope, err := l.scope.ResolveScope(id.ID, domain_overview.AllDomains, c.Query("engagement_id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
 	idData := c.Params("id")
-	result, err := l.service.GetSecurityChecklistDetailByESID(context.TODO(), idData)
+	result, err := l.service.GetSecurityChecklistDetailByESID(context.TODO(), idData, scope)
 	if err != nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
@@ -159,16 +164,14 @@ func (l *SecurityCheklistHandler) GetURLListController(c *fiber.Ctx) error {
 	var response payload.Response
 
 	// 🔑 Ambil & validasi token
-	loadconfig, _ := config.LoadConfig(".")
-	refresh_token := c.Cookies("refresh_token")
-	id, err := util_jwttoken.ValidateToken(refresh_token, loadconfig.RefreshTokenPublicKey)
-	if err != nil {
-		response = payload.NewErrorResponse(err.Error())
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 
 	// 🔑 Ambil domain berdasarkan client ID
-	nameDomain, err := l.service.GetDomainByClientID(id.UserID)
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
 	if err != nil {
 		response = payload.NewErrorResponse(err)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
@@ -205,7 +208,7 @@ func (l *SecurityCheklistHandler) GetURLListController(c *fiber.Ctx) error {
 	}
 
 	// 🚀 Call service untuk ambil data
-	result, err := l.service.GetURLList(context.TODO(), nameDomain.Domain, params)
+	result, err := l.service.GetURLList(context.TODO(), scope, params)
 	if err != nil {
 		response = payload.NewErrorResponse(errorenum.DataNotFound)
 		return c.Status(fiber.StatusBadRequest).JSON(response)
@@ -231,6 +234,29 @@ func (l *SecurityCheklistHandler) GetURLListController(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(result)
 }
 
+// GetOutOfScopeActivityController traffic yang cocok dengan rule exclude client, per host dan pentester
+func (l *SecurityCheklistHandler) GetOutOfScopeActivityController(c *fiber.Ctx) error {
+	var response payload.Response
+
+	id, ok := c.Locals("user").(model.UserResponse)
+	if !ok {
+		response = payload.NewErrorResponse(errorenum.Unauthorized)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	scope, err := l.scope.ResolveScope(id.ID, c.Query("domain"), c.Query("engagement_id"))
+	if err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	result, err := l.service.GetOutOfScopeActivity(context.TODO(), scope)
+	if err != nil {
+		response = payload.NewErrorResponse(errorenum.SomethingError)
+		return c.Status(fiber.StatusInternalServerError).JSON(response)
+	}
+	response = payload.NewSuccessResponse(result, errorenum.OKSuccess)
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
 func (l *SecurityCheklistHandler) ListVulnController(c *fiber.Ctx) error {
 	search := c.Query("search")
 	page, _ := strconv.Atoi(c.Query("page"))
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+// checkCredentialRevoked menolak kredensial (token JWT atau API key) yang masuk denylist atau
+// terbit sebelum semua token user dicabut (logout semua device, ganti password, "not me", admin).
+// issuedAt dalam unix milidetik.
+func checkCredentialRevoked(userID, credentialID string, issuedAt int64) (int, error) {
+	denylisted, err := config.IsTokenDenylisted(credentialID)
+	if err != nil || denylisted {
+		return fiber.StatusUnauthorized, errorenum.TokenRevoked
+	}
+	revokedAt, err := config.UserTokensRevokedAt(userID)
+	if err != nil || (revokedAt > 0 && issuedAt < revokedAt) {
+		return fiber.StatusUnauthorized, errorenum.TokenRevoked
+	}
+	return 0, nil
+}
+
+// checkAccountState gate yang sama untuk session JWT dan API key, supaya kewajiban akun
+// (verifikasi, ganti password, daftar passkey) tidak bisa dilewati dengan jalur auth lain
+func checkAccountState(c *fiber.Ctx, user *model.User, credentialID string, issuedAt int64) (int, error) {
+	if status, err := checkCredentialRevoked(user.Id, credentialID, issuedAt); err != nil {
+		return status, err
+	}
+	if !user.IsVerified {
+		return fiber.StatusForbidden, errorenum.Forbidden
+	}
+	//akun dengan password sementara hanya boleh ganti password dulu
+	if user.MustChangePassword && c.Path() != ChangePasswordPath {
+		return fiber.StatusForbidden, errorenum.PasswordChangeNeeded
+	}
+	//client mewajibkan passkey, user harus mendaftarkan passkey dulu
+	if user.MustRegisterPasskey && !strings.HasPrefix(c.Path(), PasskeyRegisterPath) {
+		return fiber.StatusForbidden, errorenum.PasskeyEnrollmentNeeded
+	}
+	return 0, nil
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+	util_apikey "xops-admin/util/api_key"
+)
+
+// permissionScopes memetakan permission role ke scope API key. Permission yang
+// tidak ada di sini tidak bisa dipakai lewat API key sama sekali.
+var permissionScopes = map[string]string{
+	model.PermissionFindingsRead: model.ApiKeyScopeFindingsRead,
+}
+
+// authenticateApiKey alternatif dari pasangan cookie JWT untuk akses mesin (CI, script)
+func authenticateApiKey(c *fiber.Ctx, rawKey string) error {
+	var response payload.Response
+	var apiKey model.ApiKey
+	if err := config.DB.First(&apiKey, "key_hash = ?", util_apikey.HashAPIKey(rawKey)); err.RowsAffected == 0 || !apiKey.IsActive() {
+		response = payload.NewErrorResponse(errorenum.InvalidApiKey)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+
+	var user model.User
+	if err := config.DB.First(&user, "id = ?", apiKey.IdUser); err.RowsAffected == 0 {
+		response = payload.NewErrorResponse(errorenum.InvalidApiKey)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	// API key diperlakukan seperti token yang terbit saat key dibuat
+	if status, err := checkAccountState(c, &user, apiKey.Id, apiKey.CreatedAt.UnixMilli()); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(status).JSON(response)
+	}
+
+	// last_used cukup diperbarui per menit supaya tidak menulis ke db di setiap request
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > time.Minute {
+		config.DB.Model(&apiKey).Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": GetPublicIP(c)})
+	}
+
+	c.Locals("user", model.ConvertUser(&user))
+	c.Locals("api_key", apiKey)
+	return c.Next()
+}
+
+// RequireSession menolak request yang diautentikasi dengan API key atau token impersonation,
+// untuk endpoint yang mengelola akun sendiri (2fa, session, password, api key)
+func RequireSession(c *fiber.Ctx) error {
+	if _, ok := c.Locals("api_key").(model.ApiKey); ok {
+		response := payload.NewErrorResponse(errorenum.ApiKeyNotAllowed)
+		return c.Status(fiber.StatusForbidden).JSON(response)
+	}
+	if _, ok := c.Locals("impersonation").(model.Impersonation); ok {
+		response := payload.NewErrorResponse(errorenum.ImpersonationForbidden)
+		return c.Status(fiber.StatusForbidden).JSON(response)
+	}
+	return c.Next()
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+	token "xops-admin/util/token_jwt"
+	util_uuid "xops-admin/util/uuid"
+)
+
+// ImpersonationEndPath satu-satunya route non-GET yang boleh dipanggil dengan token impersonation
+const ImpersonationEndPath = "/api/v1/impersonation/end"
+
+// authenticateImpersonation menangani token impersonation: tidak terikat session,
+// hanya boleh membaca, dan setiap request dicatat ke audit impersonation
+func authenticateImpersonation(c *fiber.Ctx, tokenClaims *token.TokenDetails, user *model.User) error {
+	var response payload.Response
+	var impersonation model.Impersonation
+	if err := config.DB.First(&impersonation, "id = ?", tokenClaims.ImpersonationID); err.RowsAffected == 0 ||
+		!impersonation.IsActive() || impersonation.IdUser != user.Id || impersonation.IdAdmin != tokenClaims.ImpersonatorID {
+		response = payload.NewErrorResponse(errorenum.ImpersonationEnded)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	c.Set("X-Impersonated-By", impersonation.IdAdmin)
+
+	if !isReadOnlyRequest(c) && c.Path() != ImpersonationEndPath {
+		logImpersonationRequest(c, impersonation.Id, fiber.StatusForbidden, true)
+		response = payload.NewErrorResponse(errorenum.ImpersonationReadOnly)
+		return c.Status(fiber.StatusForbidden).JSON(response)
+	}
+
+	c.Locals("user", model.ConvertUser(user))
+	c.Locals("access_token_uuid", tokenClaims.TokenUuid)
+	c.Locals("access_token_expires", *tokenClaims.ExpiresIn)
+	c.Locals("impersonation", impersonation)
+
+	err := c.Next()
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		}
+	}
+	logImpersonationRequest(c, impersonation.Id, status, false)
+	return err
+}
+
+func isReadOnlyRequest(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func logImpersonationRequest(c *fiber.Ctx, idImpersonation string, status int, blocked bool) {
+	request := &model.ImpersonationRequest{
+		Id:              util_uuid.GenerateID(),
+		IdImpersonation: idImpersonation,
+		Method:          c.Method(),
+		Path:            c.OriginalURL(),
+		Status:          status,
+		Blocked:         blocked,
+		CreatedAt:       time.Now(),
+	}
+	if err := config.DB.Create(request).Error; err != nil {
+		log.Println("impersonation audit:", err)
+	}
+}
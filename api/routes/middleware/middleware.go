@@ -1,9 +1,7 @@
 package middleware
 
 import (
-	"net"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,35 +13,31 @@ import (
 	token "xops-admin/util/token_jwt"
 )
 
-func MiddlewareApiKey(c *fiber.Ctx) error {
-	var response payload.Response
-	_, err := net.Dial("tcp", "www.google.com:80")
-	if err != nil {
-		response = payload.NewErrorResponse("Tidak ada koneksi internet")
-		return c.Status(fiber.StatusServiceUnavailable).JSON(response)
-	}
-	return c.Next()
-}
+// ChangePasswordPath satu-satunya route yang boleh diakses selama MustChangePassword aktif
+const ChangePasswordPath = "/api/v1/auth/change-password"
+
+// PasskeyRegisterPath prefix route registrasi passkey, satu-satunya yang boleh diakses
+// selama MustRegisterPasskey aktif
+const PasskeyRegisterPath = "/api/v1/auth/webauthn/register"
 
 func DeserializeUser(c *fiber.Ctx) error {
 	var access_token string
 	var response payload.Response
-	_, err := net.Dial("tcp", "www.google.com:80")
-	if err != nil {
-		return c.Status(fiber.StatusServiceUnavailable).SendString("Tidak ada koneksi internet.")
-	}
 	authorization := c.Get("Authorization")
 	// refresh_token := c.Cookies("refresh_token")
 	if strings.HasPrefix(authorization, "Bearer ") {
 		access_token = strings.TrimPrefix(authorization, "Bearer ")
 	}
 	if access_token == "" {
+		if apiKey := c.Get("api-key"); apiKey != "" {
+			return authenticateApiKey(c, apiKey)
+		}
 		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
 	loadconfig, _ := config.LoadConfig(".")
 
-	tokenClaims, err := token.ValidateToken(access_token, loadconfig.AccessTokenPublicKey)
+	tokenClaims, err := token.ValidateToken(access_token, loadconfig.AccessTokenKeyring())
 	if err != nil {
 		response = payload.NewErrorResponse(err.Error())
 		return c.Status(fiber.StatusForbidden).JSON(response)
@@ -54,21 +48,44 @@ func DeserializeUser(c *fiber.Ctx) error {
 	refresh_token := c.Cookies("refresh_token")
 	access_token_cookies := c.Cookies("access_token")
 
-	if err := config.DB.First(&user, "id = ?", userId); err.RowsAffected < 0 {
+	if err := config.DB.First(&user, "id = ?", userId); err.RowsAffected == 0 {
 		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	if user.IdRole != 1 {
+	//token impersonation dikirim lewat header saja, tidak memakai cookie session admin
+	if tokenClaims.ImpersonationID != "" {
+		if status, err := checkCredentialRevoked(userId, tokenClaims.TokenUuid, tokenClaims.IssuedAt); err != nil {
+			response = payload.NewErrorResponse(err)
+			return c.Status(status).JSON(response)
+		}
+		if !user.IsVerified {
+			response = payload.NewErrorResponse(errorenum.Forbidden)
+			return c.Status(fiber.StatusForbidden).JSON(response)
+		}
+		return authenticateImpersonation(c, tokenClaims, &user)
+	}
+	if access_token_cookies != access_token {
 		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	if user.RefreshToken != refresh_token || access_token_cookies != access_token {
+	//refresh token harus milik session yang masih aktif
+	refreshClaims, err := token.ValidateToken(refresh_token, loadconfig.RefreshTokenKeyring())
+	if err != nil || refreshClaims.UserID != userId || refreshClaims.SessionID == "" {
 		response = payload.NewErrorResponse(errorenum.Unauthorized)
 		return c.Status(fiber.StatusUnauthorized).JSON(response)
 	}
-	if !user.IsVerified {
-		response = payload.NewErrorResponse(errorenum.Forbidden)
-		return c.Status(fiber.StatusForbidden).JSON(response)
+	var session model.Session
+	if err := config.DB.First(&session, "id = ?", refreshClaims.SessionID); err.RowsAffected == 0 ||
+		!session.IsActive() || session.RefreshTokenUuid != refreshClaims.TokenUuid {
+		response = payload.NewErrorResponse(errorenum.SessionExpired)
+		return c.Status(fiber.StatusUnauthorized).JSON(response)
+	}
+	if time.Since(session.LastSeenAt) > 5*time.Minute {
+		config.DB.Model(&session).Update("last_seen_at", time.Now())
+	}
+	if status, err := checkAccountState(c, &user, tokenClaims.TokenUuid, tokenClaims.IssuedAt); err != nil {
+		response = payload.NewErrorResponse(err)
+		return c.Status(status).JSON(response)
 	}
 	if err := config.DB.First(&user, "id = ?", userId); err.RowsAffected < 0 {
 		response = payload.NewErrorResponse(errorenum.Forbidden)
@@ -76,56 +93,14 @@ func DeserializeUser(c *fiber.Ctx) error {
 	}
 	c.Locals("user", model.ConvertUser(&user))
 	c.Locals("access_token_uuid", tokenClaims.TokenUuid)
+	c.Locals("access_token_expires", *tokenClaims.ExpiresIn)
+	c.Locals("session_id", session.Id)
 
 	return c.Next()
 }
-func GetPublicIP(c *fiber.Ctx) string {
-	if ip := c.Get("X-Forwarded-For"); ip != "" {
-		ips := strings.Split(ip, ",")
-		return strings.TrimSpace(ips[0])
-	}
-
-	if ip := c.Get("X-Real-IP"); ip != "" {
-		return ip
-	}
 
+// GetPublicIP IP client. Header proxy hanya dipercaya jika request datang dari TRUSTED_PROXIES
+// (lihat konfigurasi fiber di main.go), selain itu dipakai alamat koneksi langsung.
+func GetPublicIP(c *fiber.Ctx) string {
 	return c.IP()
 }
-func RateLimitApi(windowSize time.Duration, requestLimit int) fiber.Handler {
-	ipRequests := make(map[string]int)
-	mutex := sync.RWMutex{}
-	var response payload.Response
-
-	go func() {
-		for {
-			time.Sleep(windowSize)
-			mutex.Lock()
-			ipRequests = make(map[string]int)
-			mutex.Unlock()
-		}
-	}()
-
-	return func(c *fiber.Ctx) error {
-		apiKey := c.Get("api-key")
-
-		if apiKey == "dev-sector" {
-			return c.Next()
-		}
-
-		ip := GetPublicIP(c)
-
-		mutex.RLock()
-		if count, exists := ipRequests[ip]; exists && count >= requestLimit {
-			mutex.RUnlock()
-			response = payload.NewErrorResponse(errorenum.RateLimit)
-			return c.Status(fiber.StatusTooManyRequests).JSON(response)
-		}
-		mutex.RUnlock()
-
-		mutex.Lock()
-		ipRequests[ip]++
-		mutex.Unlock()
-
-		return c.Next()
-	}
-}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+)
+
+// RequirePermission harus dipasang setelah DeserializeUser. Semua permission yang
+// disebut wajib dimiliki role user, dan kalau lewat API key juga wajib ada scope-nya.
+// Untuk anggota client, permission role juga diiris dengan batas role keanggotaan dan
+// dikurangi menjadi read-only setelah kontrak client berakhir.
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var response payload.Response
+		user, ok := c.Locals("user").(model.UserResponse)
+		if !ok {
+			response = payload.NewErrorResponse(errorenum.Unauthorized)
+			return c.Status(fiber.StatusUnauthorized).JSON(response)
+		}
+
+		var granted []string
+		if err := config.DB.Table("permissions").
+			Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+			Where("role_permissions.role_id = ?", user.IdRole).
+			Pluck("permissions.name", &granted).Error; err != nil {
+			response = payload.NewErrorResponse(errorenum.SomethingError)
+			return c.Status(fiber.StatusInternalServerError).JSON(response)
+		}
+
+		grantedSet := make(map[string]bool, len(granted))
+		for _, name := range granted {
+			grantedSet[name] = true
+		}
+		// anggota client dibatasi lagi oleh role keanggotaannya
+		readOnly := false
+		var member model.ClientMember
+		if err := config.DB.Where("id_user = ?", user.ID).Limit(1).Find(&member); err.Error != nil {
+			response = payload.NewErrorResponse(errorenum.SomethingError)
+			return c.Status(fiber.StatusInternalServerError).JSON(response)
+		} else if err.RowsAffected > 0 {
+			allowed := make(map[string]bool)
+			for _, name := range model.ClientMemberPermissions[member.Role] {
+				allowed[name] = true
+			}
+			// setelah kontrak berakhir semua anggota hanya bisa membaca
+			var client model.Client
+			if config.DB.Select("id", "end_date").Limit(1).Find(&client, "id = ?", member.IdClient).RowsAffected > 0 && client.ContractExpired(time.Now()) {
+				allowed = map[string]bool{model.PermissionFindingsRead: true}
+				readOnly = true
+			}
+			for name := range grantedSet {
+				if !allowed[name] {
+					delete(grantedSet, name)
+				}
+			}
+		}
+		apiKey, viaApiKey := c.Locals("api_key").(model.ApiKey)
+		for _, permission := range permissions {
+			if !grantedSet[permission] {
+				if readOnly {
+					response = payload.NewErrorResponse(errorenum.ContractEnded)
+					return c.Status(fiber.StatusForbidden).JSON(response)
+				}
+				response = payload.NewErrorResponse(errorenum.Forbidden)
+				return c.Status(fiber.StatusForbidden).JSON(response)
+			}
+			if scope, mapped := permissionScopes[permission]; viaApiKey && (!mapped || !apiKey.HasScope(scope)) {
+				response = payload.NewErrorResponse(errorenum.Forbidden)
+				return c.Status(fiber.StatusForbidden).JSON(response)
+			}
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+	"xops-admin/helper/payload"
+	"xops-admin/model"
+	util_apikey "xops-admin/util/api_key"
+	token "xops-admin/util/token_jwt"
+)
+
+// slidingWindowScript menyimpan timestamp request di sorted set. Mengembalikan
+// {diizinkan, jumlah request dalam window, ms sampai slot tertua keluar window}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local member = ARGV[4]
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", key, now, member)
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", key, window)
+
+local reset = window
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+var (
+	rateLimitOnce      sync.Once
+	rateLimitPolicies  map[string]config.RateLimitPolicy
+	rateLimitAllowList map[string]bool
+)
+
+func loadRateLimitConfig() {
+	loadconfig, _ := config.LoadConfig(".")
+	policies, err := config.ParseRateLimitPolicies(loadconfig.RateLimitPolicies)
+	if err != nil {
+		log.Fatal("Invalid RATE_LIMIT_POLICIES: ", err.Error())
+	}
+	rateLimitPolicies = policies
+	rateLimitAllowList = config.ParseRateLimitAllowList(loadconfig.RateLimitAllowList)
+}
+
+// RateLimit membatasi request memakai policy bernama dari config (login, otp, password_reset, ...)
+// dengan sliding window di Redis sehingga berlaku untuk semua instance.
+func RateLimit(policyName string) fiber.Handler {
+	rateLimitOnce.Do(loadRateLimitConfig)
+	policy, ok := rateLimitPolicies[policyName]
+	if !ok {
+		log.Fatal("Unknown rate limit policy: ", policyName)
+	}
+
+	return func(c *fiber.Ctx) error {
+		var response payload.Response
+		ip := GetPublicIP(c)
+		apiKey := c.Get("api-key")
+		if rateLimitAllowList[ip] || (apiKey != "" && rateLimitAllowList[apiKey]) {
+			return c.Next()
+		}
+
+		key := fmt.Sprintf("ratelimit:%s:%s", policy.Name, rateLimitSubject(c, policy.KeyBy, ip, apiKey))
+		now := time.Now().UnixMilli()
+		member := strconv.FormatInt(time.Now().UnixNano(), 10)
+		result, err := slidingWindowScript.Run(context.Background(), config.RedisClient, []string{key},
+			now, policy.Window.Milliseconds(), policy.Limit, member).Int64Slice()
+		if err != nil {
+			// redis bermasalah: jangan sampai semua login ikut mati
+			log.Printf("rate limit %s: %v", policy.Name, err)
+			return c.Next()
+		}
+
+		allowed, count, resetMs := result[0] == 1, result[1], result[2]
+		resetSeconds := (resetMs + 999) / 1000
+		remaining := int64(policy.Limit) - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+		c.Set("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Set("RateLimit-Reset", strconv.FormatInt(resetSeconds, 10))
+		c.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, int64(policy.Window.Seconds())))
+
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(resetSeconds, 10))
+			response = payload.NewErrorResponse(errorenum.RateLimit)
+			return c.Status(fiber.StatusTooManyRequests).JSON(response)
+		}
+		return c.Next()
+	}
+}
+
+// isExportRequest request yang meminta file report (?convert=csv), bukan daftar biasa
+func isExportRequest(c *fiber.Ctx) bool {
+	return strings.TrimSpace(c.Query("convert")) != ""
+}
+
+// ExportAccess hanya berlaku untuk request unduhan file: request lewat API key wajib punya
+// scope export, lalu dibatasi policy export. Request daftar biasa cukup dengan scope baca
+// dan tidak menghabiskan kuota export.
+func ExportAccess() fiber.Handler {
+	limit := RateLimit("export")
+	return func(c *fiber.Ctx) error {
+		if !isExportRequest(c) {
+			return c.Next()
+		}
+		if apiKey, ok := c.Locals("api_key").(model.ApiKey); ok && !apiKey.HasScope(model.ApiKeyScopeExport) {
+			response := payload.NewErrorResponse(errorenum.Forbidden)
+			return c.Status(fiber.StatusForbidden).JSON(response)
+		}
+		return limit(c)
+	}
+}
+
+// rateLimitSubject menentukan siapa yang dihitung. Kalau user belum bisa dikenali
+// (misal sebelum login selesai) jatuh ke IP.
+func rateLimitSubject(c *fiber.Ctx, keyBy, ip, apiKey string) string {
+	switch keyBy {
+	case config.RateLimitKeyUser:
+		if user, ok := c.Locals("user").(model.UserResponse); ok {
+			return "user:" + user.ID
+		}
+		accessToken := c.Cookies("access_token")
+		if authorization := c.Get("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+			accessToken = strings.TrimPrefix(authorization, "Bearer ")
+		}
+		if accessToken != "" {
+			loadconfig, _ := config.LoadConfig(".")
+			if claims, err := token.ValidateToken(accessToken, loadconfig.AccessTokenKeyring()); err == nil {
+				return "user:" + claims.UserID
+			}
+		}
+	case config.RateLimitKeyApiKey:
+		if apiKey != "" {
+			return "api_key:" + util_apikey.HashAPIKey(apiKey)[:16]
+		}
+	}
+	return "ip:" + ip
+}
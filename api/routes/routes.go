@@ -9,17 +9,37 @@ import (
 	routes_user "xops-admin/api/routes/user"
 	"xops-admin/helper/errorenum"
 	"xops-admin/helper/payload"
+	util_blobstore "xops-admin/util/blob_store"
 )
 
-func SetUpRoutes(app *fiber.App, postgres *gorm.DB, elasticSearch *elasticsearch.Client) {
+func SetUpRoutes(app *fiber.App, postgres *gorm.DB, elasticSearch *elasticsearch.Client, blobStore util_blobstore.BlobStore) {
+
+	routes_user.JwksRoutes(app)
 
 	routes := app.Group("/api")
+	routes_user.BlobRoutes(routes, blobStore)
 	routes_user.AuthRoutes(routes, postgres)
+	routes_user.SsoRoutes(routes, postgres)
+	routes_user.InvitationRoutes(routes, postgres)
 	apiV1 := routes.Group("/v1", middleware.DeserializeUser)
 	routes_user.OverviewRoutes(apiV1, postgres, elasticSearch)
 	routes_user.SecurityChecklistRoutes(apiV1, postgres, elasticSearch)
-	routes_user.ClientRoutes(apiV1, postgres, elasticSearch)
+	routes_user.ClientRoutes(apiV1, postgres, elasticSearch, blobStore)
+	routes_user.SsoConfigRoutes(apiV1, postgres)
 	routes_user.ListBugRoutes(apiV1, postgres, elasticSearch)
+	routes_user.TwoFactorRoutes(apiV1, postgres)
+	routes_user.SessionRoutes(apiV1, postgres)
+	routes_user.KnownDeviceRoutes(apiV1, postgres)
+	routes_user.LogoutRoutes(apiV1, postgres)
+	routes_user.AuthEventRoutes(apiV1, postgres)
+	routes_user.PasswordRoutes(apiV1, postgres)
+	routes_user.ApiKeyRoutes(apiV1, postgres)
+	routes_user.WebauthnRoutes(apiV1, postgres)
+	routes_user.RoleRoutes(apiV1, postgres)
+	routes_user.LockoutRoutes(apiV1, postgres)
+	routes_user.ImpersonationRoutes(apiV1, postgres)
+	routes_user.ClientMemberRoutes(apiV1, postgres)
+	routes_user.EngagementRoutes(apiV1, postgres)
 
 	routes.All("*", func(c *fiber.Ctx) error {
 		path := c.Path()
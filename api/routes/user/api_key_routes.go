@@ -0,0 +1,24 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func ApiKeyRoutes(app fiber.Router, db *gorm.DB) {
+	ApiKeyRepo := postgres.NewApiKeyRepo(db)
+
+	apiKeyUsecase := usecase_user.NewApiKeyUseCase(ApiKeyRepo)
+	apiKeyController := controller_user_auth.NewApiKeyController(apiKeyUsecase)
+
+	// key hanya bisa dikelola dari sesi browser, bukan dengan API key lain
+	r := app.Group("/api-keys", middleware.RequireSession)
+	r.Get("/", apiKeyController.ListApiKeyController)
+	r.Post("/", apiKeyController.CreateApiKeyController)
+	r.Delete("/:id", apiKeyController.RevokeApiKeyController)
+}
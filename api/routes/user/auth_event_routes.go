@@ -0,0 +1,22 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func AuthEventRoutes(app fiber.Router, db *gorm.DB) {
+	AuthEventRepo := postgres.NewAuthEventRepo(db)
+
+	authEventUsecase := usecase_user.NewAuthEventUseCase(AuthEventRepo)
+	authEventController := controller_user_auth.NewAuthEventController(authEventUsecase)
+
+	app.Get("/auth/sign-ins", middleware.RequireSession, authEventController.RecentSignInsController)
+	app.Get("/admin/auth-events", middleware.RequirePermission(model.PermissionUsersManage), authEventController.SearchAuthEventsController)
+}
@@ -1,8 +1,6 @@
 package routes_user
 
 import (
-	"time"
-
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 
@@ -15,6 +13,10 @@ import (
 func AuthRoutes(app fiber.Router, db *gorm.DB) {
 	UserRepo := postgres.NewUserRepo(db)
 	RoleRepo := postgres.NewRoleRepo(db)
+	RecoveryCodeRepo := postgres.NewRecoveryCodeRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+	AuthEventRepo := postgres.NewAuthEventRepo(db)
+	ApiKeyRepo := postgres.NewApiKeyRepo(db)
 
 	//
 
@@ -23,19 +25,34 @@ func AuthRoutes(app fiber.Router, db *gorm.DB) {
 	//register 2fa
 
 	///
-	verified2faUsecase := usecase_user.NewVerified2faUseCase(UserRepo, RoleRepo)
-	verified2faController := controller_user_auth.NewVerified2faController(verified2faUsecase)
-	LoginUseCase := usecase_user.NewLoginUseCase(UserRepo)
-	LoginController := controller_user_auth.NewLoginController(LoginUseCase)
+	sessionUsecase := usecase_user.NewSessionUseCase(SessionRepo, UserRepo)
+	authEventUsecase := usecase_user.NewAuthEventUseCase(AuthEventRepo)
+	knownDeviceUsecase := usecase_user.NewKnownDeviceUseCase(postgres.NewKnownDeviceRepo(db), UserRepo, SessionRepo, ApiKeyRepo)
+	knownDeviceController := controller_user_auth.NewKnownDeviceController(knownDeviceUsecase)
+	lockoutUsecase := usecase_user.NewLockoutUseCase(UserRepo)
+	otpUsecase := usecase_user.NewOtpUseCase()
+	verified2faUsecase := usecase_user.NewVerified2faUseCase(UserRepo, RoleRepo, RecoveryCodeRepo, otpUsecase)
+	webauthnUsecase := usecase_user.NewWebauthnUseCase(postgres.NewWebauthnCredentialRepo(db), UserRepo, postgres.NewClientRepo(db))
+	verified2faController := controller_user_auth.NewVerified2faController(verified2faUsecase, sessionUsecase, lockoutUsecase, webauthnUsecase, authEventUsecase, knownDeviceUsecase)
+	LoginUseCase := usecase_user.NewLoginUseCase(UserRepo, otpUsecase)
+	LoginController := controller_user_auth.NewLoginController(LoginUseCase, lockoutUsecase, authEventUsecase)
+
+	passwordUsecase := usecase_user.NewPasswordUseCase(UserRepo, SessionRepo, ApiKeyRepo)
+	passwordController := controller_user_auth.NewPasswordController(passwordUsecase)
 
 	refreshTokenUsecase := usecase_user.NewRefreshTokenUseCase(UserRepo)
-	refreshTokenControler := controller_user_auth.NewRefreshTokenController(refreshTokenUsecase)
+	refreshTokenControler := controller_user_auth.NewRefreshTokenController(refreshTokenUsecase, sessionUsecase, authEventUsecase)
 	///
-	app.Post("/v1/verify-otp", middleware.RateLimitApi(15*time.Minute, 5), verified2faController.VerifiedOtpControlller)
-	app.Post("/v1/send-otp", middleware.RateLimitApi(15*time.Minute, 5), verified2faController.SendOtpVerifedCode)
+	app.Post("/v1/verify-otp", middleware.RateLimit("otp"), verified2faController.VerifiedOtpControlller)
+	app.Post("/v1/webauthn/assertion/options", middleware.RateLimit("otp"), verified2faController.WebauthnAssertionOptionsController)
+	app.Post("/v1/send-otp", middleware.RateLimit("otp"), verified2faController.SendOtpVerifedCode)
 	app.Post("/refresh-token", refreshTokenControler.RefreshTokenController)
 
-	apiAuthGroup := app.Group("/auth", middleware.MiddlewareApiKey)
-	apiAuthGroup.Post("/login", middleware.RateLimitApi(15*time.Minute, 5), LoginController.LoginUserControlller)
+	apiAuthGroup := app.Group("/auth")
+	apiAuthGroup.Post("/login", middleware.RateLimit("login"), LoginController.LoginUserControlller)
+	apiAuthGroup.Post("/forgot-password", middleware.RateLimit("password_reset"), passwordController.ForgotPasswordController)
+	apiAuthGroup.Post("/reset-password", middleware.RateLimit("password_reset"), passwordController.ResetPasswordController)
+	apiAuthGroup.Post("/activate", middleware.RateLimit("password_reset"), passwordController.ActivateAccountController)
+	apiAuthGroup.Post("/not-me", middleware.RateLimit("password_reset"), knownDeviceController.NotMeController)
 
 }
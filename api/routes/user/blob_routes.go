@@ -0,0 +1,19 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	controller_user_client "xops-admin/api/controller/user/client"
+	util_blobstore "xops-admin/util/blob_store"
+)
+
+// BlobRoutes endpoint unduhan untuk backend lokal, URL S3 langsung mengarah ke bucket
+func BlobRoutes(app fiber.Router, blobStore util_blobstore.BlobStore) {
+	local, ok := blobStore.(*util_blobstore.LocalStore)
+	if !ok {
+		return
+	}
+	blobController := controller_user_client.NewBlobHandler(local)
+
+	app.Get("/blobs", blobController.DownloadBlobController)
+}
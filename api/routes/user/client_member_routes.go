@@ -0,0 +1,42 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_client "xops-admin/api/controller/user/client"
+	"xops-admin/api/routes/middleware"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_client "xops-admin/usecase/user/client"
+)
+
+func newClientMemberHandler(db *gorm.DB) *controller_user_client.ClientMemberHandler {
+	UserRepo := postgres.NewUserRepo(db)
+	ClientRepo := postgres.NewClientRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+	ClientMemberRepo := postgres.NewClientMemberRepo(db)
+	ApiKeyRepo := postgres.NewApiKeyRepo(db)
+
+	memberUsecase := usecase_client.NewClientMemberUseCase(ClientMemberRepo, ClientRepo, UserRepo, SessionRepo, ApiKeyRepo)
+	return controller_user_client.NewClientMemberHandler(memberUsecase)
+}
+
+// ClientMemberRoutes kelola anggota organisasi client, dicek owner di usecase
+func ClientMemberRoutes(app fiber.Router, db *gorm.DB) {
+	memberController := newClientMemberHandler(db)
+
+	r := app.Group("/members", middleware.RequireSession)
+	r.Get("/", memberController.ListMembersController)
+	r.Post("/invitations", memberController.InviteController)
+	r.Delete("/invitations/:id", memberController.RevokeInvitationController)
+	r.Delete("/:id", memberController.RemoveMemberController)
+}
+
+// InvitationRoutes endpoint publik untuk penerima undangan yang belum punya akun
+func InvitationRoutes(app fiber.Router, db *gorm.DB) {
+	memberController := newClientMemberHandler(db)
+
+	r := app.Group("/invitations", middleware.RateLimit("invitation"))
+	r.Get("/", memberController.PreviewInvitationController)
+	r.Post("/accept", memberController.AcceptInvitationController)
+}
@@ -6,17 +6,53 @@ import (
 	"gorm.io/gorm"
 
 	controller_user_client "xops-admin/api/controller/user/client"
+	"xops-admin/api/routes/middleware"
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/model"
 	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
 	usecase_client "xops-admin/usecase/user/client"
+	usecase_scope "xops-admin/usecase/user/scope"
+	util_blobstore "xops-admin/util/blob_store"
+	util_domainverify "xops-admin/util/domain_verify"
 )
 
-func ClientRoutes(app fiber.Router, db *gorm.DB, elasticSearch *elasticsearch.Client) {
+// NewClientUseCase satu-satunya tempat usecase client dirakit, dipakai route dan job latar
+// belakang di main supaya dependensinya tidak berbeda
+func NewClientUseCase(db *gorm.DB, blobStore util_blobstore.BlobStore) domain_client.ClientUseCase {
 	UserRepo := postgres.NewUserRepo(db)
 	ClientRepo := postgres.NewClientRepo(db)
+	RoleRepo := postgres.NewRoleRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+	ClientMemberRepo := postgres.NewClientMemberRepo(db)
+	ApiKeyRepo := postgres.NewApiKeyRepo(db)
 
-	clientUsecase := usecase_client.NewClientUseCase(ClientRepo, UserRepo)
-	clientController := controller_user_client.NewClientUserHandler(clientUsecase, elasticSearch)
+	passwordUsecase := usecase_user.NewPasswordUseCase(UserRepo, SessionRepo, ApiKeyRepo)
 
-	app.Post("/clients", clientController.CreateClient)
-	app.Get("/clients", clientController.GetDomainClient)
+	return usecase_client.NewClientUseCase(ClientRepo, UserRepo, RoleRepo, ClientMemberRepo, SessionRepo, ApiKeyRepo, passwordUsecase, util_domainverify.NewVerifier(nil, nil), blobStore)
+}
+
+func ClientRoutes(app fiber.Router, db *gorm.DB, elasticSearch *elasticsearch.Client, blobStore util_blobstore.BlobStore) {
+	clientUsecase := NewClientUseCase(db, blobStore)
+	scopeUsecase := usecase_scope.NewScopeUseCase(postgres.NewClientRepo(db), postgres.NewEngagementRepo(db))
+	clientController := controller_user_client.NewClientUserHandler(clientUsecase, scopeUsecase, elasticSearch)
+
+	app.Post("/clients", middleware.RequirePermission(model.PermissionClientsManage), clientController.CreateClient)
+	app.Get("/clients", middleware.RequirePermission(model.PermissionFindingsRead), clientController.GetDomainClient)
+	app.Get("/domains", middleware.RequirePermission(model.PermissionFindingsRead), clientController.ListDomainsController)
+
+	admin := app.Group("/admin/clients", middleware.RequirePermission(model.PermissionClientsManage))
+	admin.Get("/", clientController.SearchClientsController)
+	admin.Get("/:id", clientController.DetailClientController)
+	admin.Patch("/:id", clientController.UpdateClientController)
+	admin.Delete("/:id", clientController.DeleteClientController)
+	admin.Post("/:id/domains", clientController.AddDomainController)
+	admin.Patch("/:id/domains/:domainId", clientController.SetDomainActiveController)
+	admin.Delete("/:id/domains/:domainId", clientController.RemoveDomainController)
+	admin.Put("/:id/contract", clientController.UpdateContractController)
+	admin.Get("/:id/scope-rules", clientController.ListScopeRulesController)
+	admin.Post("/:id/scope-rules", clientController.AddScopeRuleController)
+	admin.Delete("/:id/scope-rules/:ruleId", clientController.RemoveScopeRuleController)
+	admin.Get("/:id/domains/:domainId/verification", clientController.DomainVerificationController)
+	admin.Post("/:id/domains/:domainId/verification", clientController.VerifyDomainController)
 }
@@ -0,0 +1,30 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_client "xops-admin/api/controller/user/client"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_client "xops-admin/usecase/user/client"
+)
+
+func EngagementRoutes(app fiber.Router, db *gorm.DB) {
+	EngagementRepo := postgres.NewEngagementRepo(db)
+	ClientRepo := postgres.NewClientRepo(db)
+	UserRepo := postgres.NewUserRepo(db)
+
+	engagementUsecase := usecase_client.NewEngagementUseCase(EngagementRepo, ClientRepo, UserRepo)
+	engagementController := controller_user_client.NewEngagementHandler(engagementUsecase)
+	canManage := middleware.RequirePermission(model.PermissionClientsManage)
+
+	app.Get("/engagements", middleware.RequirePermission(model.PermissionFindingsRead), engagementController.ListClientEngagementsController)
+
+	app.Get("/admin/clients/:id/engagements", canManage, engagementController.ListEngagementsController)
+	app.Post("/admin/clients/:id/engagements", canManage, engagementController.CreateEngagementController)
+	app.Get("/admin/engagements/:id", canManage, engagementController.DetailEngagementController)
+	app.Put("/admin/engagements/:id", canManage, engagementController.UpdateEngagementController)
+	app.Delete("/admin/engagements/:id", canManage, engagementController.DeleteEngagementController)
+}
@@ -0,0 +1,29 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func ImpersonationRoutes(app fiber.Router, db *gorm.DB) {
+	ImpersonationRepo := postgres.NewImpersonationRepo(db)
+	UserRepo := postgres.NewUserRepo(db)
+
+	impersonationUsecase := usecase_user.NewImpersonationUseCase(ImpersonationRepo, UserRepo)
+	impersonationController := controller_user_auth.NewImpersonationController(impersonationUsecase)
+
+	app.Post("/admin/users/:id/impersonate", middleware.RequireSession, middleware.RequirePermission(model.PermissionUsersImpersonate), impersonationController.StartImpersonationController)
+	// path ini dikecualikan dari blokir read-only di middleware
+	app.Post("/impersonation/end", impersonationController.EndImpersonationController)
+
+	r := app.Group("/admin/impersonations", middleware.RequirePermission(model.PermissionUsersManage))
+	r.Get("/", impersonationController.SearchImpersonationController)
+	r.Get("/:id", impersonationController.DetailImpersonationController)
+	r.Delete("/:id", impersonationController.TerminateImpersonationController)
+}
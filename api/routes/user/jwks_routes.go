@@ -0,0 +1,13 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+)
+
+func JwksRoutes(app fiber.Router) {
+	jwksController := controller_user_auth.NewJwksController()
+
+	app.Get("/.well-known/jwks.json", jwksController.JwksController)
+}
@@ -0,0 +1,25 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func KnownDeviceRoutes(app fiber.Router, db *gorm.DB) {
+	UserRepo := postgres.NewUserRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+	KnownDeviceRepo := postgres.NewKnownDeviceRepo(db)
+	ApiKeyRepo := postgres.NewApiKeyRepo(db)
+
+	knownDeviceUsecase := usecase_user.NewKnownDeviceUseCase(KnownDeviceRepo, UserRepo, SessionRepo, ApiKeyRepo)
+	knownDeviceController := controller_user_auth.NewKnownDeviceController(knownDeviceUsecase)
+
+	r := app.Group("/devices", middleware.RequireSession)
+	r.Get("/", knownDeviceController.ListDeviceController)
+	r.Delete("/:id", knownDeviceController.ForgetDeviceController)
+}
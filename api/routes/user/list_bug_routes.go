@@ -6,8 +6,11 @@ import (
 	"gorm.io/gorm"
 
 	controller_list_bug "xops-admin/api/controller/user/list_bug"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
 	postgres "xops-admin/repo/repo_postgres"
 	"xops-admin/usecase/user/list_bug"
+	"xops-admin/usecase/user/scope"
 )
 
 func ListBugRoutes(app fiber.Router, db *gorm.DB, elasticSearch *elasticsearch.Client) {
@@ -16,33 +19,40 @@ func ListBugRoutes(app fiber.Router, db *gorm.DB, elasticSearch *elasticsearch.C
 	listVulnRepo := postgres.NewListVulnerabilityRepo(db)
 	listBugRepo := postgres.NewListBugRepository(db)
 	ClientRepo := postgres.NewClientRepo(db)
+	EngagementRepo := postgres.NewEngagementRepo(db)
 
 	// init usecase
 	typeBugUsecase := list_bug.NewListBug(typeBugRepo)
 
 	listVulnUsecase := list_bug.NewListVulnerabilityUseCase(listVulnRepo)
 
-	listBugUsecase := list_bug.NewListBugTableUseCase(listBugRepo, ClientRepo)
+	listBugUsecase := list_bug.NewListBugTableUseCase(listBugRepo)
+	scopeUsecase := scope.NewScopeUseCase(ClientRepo, EngagementRepo)
 	// init handler
 	typeBugHandler := controller_list_bug.NewTypeBugHandler(typeBugUsecase)
 
 	listVuln := controller_list_bug.NewListVulnerabilityHandler(listVulnUsecase)
 
-	listBugHandler := controller_list_bug.NewListBugTableHandler(listBugUsecase)
+	listBugHandler := controller_list_bug.NewListBugTableHandler(listBugUsecase, scopeUsecase)
+
+	canRead := middleware.RequirePermission(model.PermissionFindingsRead)
+	canEdit := middleware.RequirePermission(model.PermissionCatalogEdit)
 
 	r := app.Group("/type-bugs")
-	r.Post("/", typeBugHandler.Create)      // create
-	r.Get("/", typeBugHandler.List)         // list all (with optional search)
-	r.Get("/:id", typeBugHandler.GetByID)   // get by id
-	r.Put("/:id", typeBugHandler.Update)    // update
-	r.Delete("/:id", typeBugHandler.Delete) // delete
+	r.Post("/", canEdit, typeBugHandler.Create)      // create
+	r.Get("/", canRead, typeBugHandler.List)         // list all (with optional search)
+	r.Get("/:id", canRead, typeBugHandler.GetByID)   // get by id
+	r.Put("/:id", canEdit, typeBugHandler.Update)    // update
+	r.Delete("/:id", canEdit, typeBugHandler.Delete) // delete
 	v := app.Group("/vulnerabilities")
-	v.Post("/", listVuln.Create)
-	v.Get("/", listVuln.List)
-	v.Get("/:id", listVuln.GetByID)
-	v.Put("/:id", listVuln.Update)
-	v.Delete("/:id", listVuln.Delete)
-
-	app.Get("/findings", listBugHandler.List)
+	v.Post("/", canEdit, listVuln.Create)
+	v.Get("/", canRead, listVuln.List)
+	v.Get("/:id", canRead, listVuln.GetByID)
+	v.Put("/:id", canEdit, listVuln.Update)
+	v.Delete("/:id", canEdit, listVuln.Delete)
+
+	// dipakai dashboard dan script / CI lewat API key untuk menarik temuan. Unduhan csv
+	// dibatasi policy export dan lewat API key butuh scope export
+	app.Get("/findings", canRead, middleware.ExportAccess(), listBugHandler.List)
 
 }
@@ -0,0 +1,23 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func LockoutRoutes(app fiber.Router, db *gorm.DB) {
+	UserRepo := postgres.NewUserRepo(db)
+
+	lockoutUsecase := usecase_user.NewLockoutUseCase(UserRepo)
+	lockoutController := controller_user_auth.NewLockoutController(lockoutUsecase)
+
+	r := app.Group("/admin/users/:id", middleware.RequirePermission(model.PermissionUsersManage))
+	r.Get("/lock", lockoutController.LockStatusController)
+	r.Post("/unlock", lockoutController.UnlockController)
+}
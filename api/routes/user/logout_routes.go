@@ -0,0 +1,25 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func LogoutRoutes(app fiber.Router, db *gorm.DB) {
+	UserRepo := postgres.NewUserRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+	ApiKeyRepo := postgres.NewApiKeyRepo(db)
+
+	logoutUsecase := usecase_user.NewLogoutUseCase(SessionRepo, UserRepo, ApiKeyRepo)
+	authEventUsecase := usecase_user.NewAuthEventUseCase(postgres.NewAuthEventRepo(db))
+	logoutController := controller_user_auth.NewLogoutController(logoutUsecase, authEventUsecase)
+
+	app.Post("/auth/logout", middleware.RequireSession, logoutController.LogoutController)
+	app.Post("/admin/users/:id/revoke-tokens", middleware.RequirePermission(model.PermissionUsersManage), logoutController.RevokeUserTokensController)
+}
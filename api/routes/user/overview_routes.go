@@ -6,31 +6,37 @@ import (
 	"gorm.io/gorm"
 
 	controller_overview "xops-admin/api/controller/user/overview"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
 	"xops-admin/repo/repo_elasticsearch"
 	postgres "xops-admin/repo/repo_postgres"
 	"xops-admin/usecase/user/overview"
+	"xops-admin/usecase/user/scope"
 )
 
 func OverviewRoutes(app fiber.Router, db *gorm.DB, elasticSearch *elasticsearch.Client) {
 	OverviewRepoRedis := repo_elasticsearch.NewBugDiscoveryTimelineRepo(elasticSearch)
 	ClientRepo := postgres.NewClientRepo(db)
-	OverviewUserUseCase := overview.NewBugDiscoveryTimeline(OverviewRepoRedis, ClientRepo)
-	BugDiscoveryTimelineController := controller_overview.NewBugDiscoveryTimelineHandler(OverviewUserUseCase)
+	EngagementRepo := postgres.NewEngagementRepo(db)
+	OverviewUserUseCase := overview.NewBugDiscoveryTimeline(OverviewRepoRedis)
+	ScopeUseCase := scope.NewScopeUseCase(ClientRepo, EngagementRepo)
+	BugDiscoveryTimelineController := controller_overview.NewBugDiscoveryTimelineHandler(OverviewUserUseCase, ScopeUseCase)
+	canRead := middleware.RequirePermission(model.PermissionFindingsRead)
 
-	app.Get("/discovery-timeline", BugDiscoveryTimelineController.BugDiscoveryTimelineController)
-	app.Get("/severity-distribution", BugDiscoveryTimelineController.BugSeverityDistributionController)
-	app.Get("/status-distribution", BugDiscoveryTimelineController.BugStatusDistributionController)
-	app.Get("/validation-distribution", BugDiscoveryTimelineController.BugValidationDistributionController)
+	app.Get("/discovery-timeline", canRead, BugDiscoveryTimelineController.BugDiscoveryTimelineController)
+	app.Get("/severity-distribution", canRead, BugDiscoveryTimelineController.BugSeverityDistributionController)
+	app.Get("/status-distribution", canRead, BugDiscoveryTimelineController.BugStatusDistributionController)
+	app.Get("/validation-distribution", canRead, BugDiscoveryTimelineController.BugValidationDistributionController)
 
-	app.Get("/host-exposure", BugDiscoveryTimelineController.HostBugsExposureController)
-	app.Get("/pentester-activity", BugDiscoveryTimelineController.PentestersActivityStatsController)
+	app.Get("/host-exposure", canRead, BugDiscoveryTimelineController.HostBugsExposureController)
+	app.Get("/pentester-activity", canRead, BugDiscoveryTimelineController.PentestersActivityStatsController)
 
-	app.Get("/bug-type-frequency", BugDiscoveryTimelineController.BugTypeFrequencyController)
+	app.Get("/bug-type-frequency", canRead, BugDiscoveryTimelineController.BugTypeFrequencyController)
 
-	app.Get("/total-finding-discovered", BugDiscoveryTimelineController.GetTotalFindingsWithTrendController)
+	app.Get("/total-finding-discovered", canRead, BugDiscoveryTimelineController.GetTotalFindingsWithTrendController)
 
-	app.Get("/pentesters-effectiveness", BugDiscoveryTimelineController.PentesterEffectivenessController)
+	app.Get("/pentesters-effectiveness", canRead, BugDiscoveryTimelineController.PentesterEffectivenessController)
 
-	app.Get("/log-activity", BugDiscoveryTimelineController.GetLogActivityController)
+	app.Get("/log-activity", canRead, BugDiscoveryTimelineController.GetLogActivityController)
 
 }
@@ -0,0 +1,22 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func PasswordRoutes(app fiber.Router, db *gorm.DB) {
+	UserRepo := postgres.NewUserRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+	ApiKeyRepo := postgres.NewApiKeyRepo(db)
+
+	passwordUsecase := usecase_user.NewPasswordUseCase(UserRepo, SessionRepo, ApiKeyRepo)
+	passwordController := controller_user_auth.NewPasswordController(passwordUsecase)
+
+	app.Post("/auth/change-password", middleware.RequireSession, passwordController.ChangePasswordController)
+}
@@ -0,0 +1,30 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_role "xops-admin/api/controller/user/role"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	"xops-admin/usecase/user/role"
+)
+
+func RoleRoutes(app fiber.Router, db *gorm.DB) {
+	roleRepo := postgres.NewRoleRepo(db)
+	permissionRepo := postgres.NewPermissionRepo(db)
+
+	roleUsecase := role.NewRoleUseCase(roleRepo, permissionRepo)
+	roleHandler := controller_role.NewRoleHandler(roleUsecase)
+
+	r := app.Group("/roles", middleware.RequirePermission(model.PermissionRolesManage))
+	r.Get("/", roleHandler.List)
+	r.Post("/", roleHandler.Create)
+	r.Get("/:id", roleHandler.GetByID)
+	r.Put("/:id", roleHandler.Update)
+	r.Put("/:id/permissions", roleHandler.SetPermissions)
+	r.Delete("/:id", roleHandler.Delete)
+
+	app.Get("/permissions", middleware.RequirePermission(model.PermissionRolesManage), roleHandler.ListPermissions)
+}
@@ -6,28 +6,37 @@ import (
 	"gorm.io/gorm"
 
 	controller_security_checklist "xops-admin/api/controller/user/security_checklist"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
 	postgres_1 "xops-admin/repo"
 	"xops-admin/repo/repo_elasticsearch"
 	postgres "xops-admin/repo/repo_postgres"
+	"xops-admin/usecase/user/scope"
 	"xops-admin/usecase/user/security_checklist"
 )
 
 func SecurityChecklistRoutes(app fiber.Router, db *gorm.DB, elasticSearch *elasticsearch.Client) {
 	SecurityChecklistRepoRedis := repo_elasticsearch.NewSecurityCheklistRepo(elasticSearch)
 	ClientRepo := postgres.NewClientRepo(db)
+	EngagementRepo := postgres.NewEngagementRepo(db)
 	listVulnRepo := postgres.NewListVulnerabilityRepo(db)
 	bulkDataSecurityRepo := postgres_1.NewBulkUpdateSecurityChecklistRepository(db, elasticSearch)
 
-	OverviewUserUseCase := security_checklist.NewSecurityChecklist(SecurityChecklistRepoRedis, ClientRepo, listVulnRepo, bulkDataSecurityRepo)
-	SecurityChecklistController := controller_security_checklist.NewSecurityCheklistHandler(OverviewUserUseCase)
+	OverviewUserUseCase := security_checklist.NewSecurityChecklist(SecurityChecklistRepoRedis, listVulnRepo, bulkDataSecurityRepo)
+	ScopeUseCase := scope.NewScopeUseCase(ClientRepo, EngagementRepo)
+	SecurityChecklistController := controller_security_checklist.NewSecurityCheklistHandler(OverviewUserUseCase, ScopeUseCase)
+
+	canRead := middleware.RequirePermission(model.PermissionFindingsRead)
+	canTriage := middleware.RequirePermission(model.PermissionFindingsTriage)
 
 	app_security_checklist := app.Group("/security-checklist")
-	app_security_checklist.Get("/total-findings", SecurityChecklistController.GetTotalFindingsController)
-	app_security_checklist.Get("/checklist-table", SecurityChecklistController.GetSecurityChecklistTableController)
-	app_security_checklist.Get("/list-url", SecurityChecklistController.GetURLListController)
-	app_security_checklist.Get("/list-vulnerabilities", SecurityChecklistController.ListVulnController)
-	app_security_checklist.Get("/total-bug-status", SecurityChecklistController.GetTotalBugStatusListController)
-	app_security_checklist.Get("/checklist-table/:id", SecurityChecklistController.GetSecurityChecklistTableDetailIdController)
+	app_security_checklist.Get("/total-findings", canRead, SecurityChecklistController.GetTotalFindingsController)
+	app_security_checklist.Get("/checklist-table", canRead, SecurityChecklistController.GetSecurityChecklistTableController)
+	app_security_checklist.Get("/list-url", canRead, SecurityChecklistController.GetURLListController)
+	app_security_checklist.Get("/list-vulnerabilities", canRead, SecurityChecklistController.ListVulnController)
+	app_security_checklist.Get("/total-bug-status", canRead, SecurityChecklistController.GetTotalBugStatusListController)
+	app_security_checklist.Get("/checklist-table/:id", canRead, SecurityChecklistController.GetSecurityChecklistTableDetailIdController)
+	app_security_checklist.Get("/out-of-scope-activity", canRead, SecurityChecklistController.GetOutOfScopeActivityController)
 
-	app_security_checklist.Post("/checklist-table/bulk-update", SecurityChecklistController.BulkUpdate)
+	app_security_checklist.Post("/checklist-table/bulk-update", canTriage, SecurityChecklistController.BulkUpdate)
 }
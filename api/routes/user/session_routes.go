@@ -0,0 +1,24 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func SessionRoutes(app fiber.Router, db *gorm.DB) {
+	UserRepo := postgres.NewUserRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+
+	sessionUsecase := usecase_user.NewSessionUseCase(SessionRepo, UserRepo)
+	sessionController := controller_user_auth.NewSessionController(sessionUsecase)
+
+	r := app.Group("/sessions", middleware.RequireSession)
+	r.Get("/", sessionController.ListSessionController)
+	r.Delete("/", sessionController.RevokeAllSessionController)
+	r.Delete("/:id", sessionController.RevokeSessionController)
+}
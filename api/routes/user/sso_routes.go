@@ -0,0 +1,40 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func newSsoController(db *gorm.DB) *controller_user_auth.SsoController {
+	UserRepo := postgres.NewUserRepo(db)
+	ClientRepo := postgres.NewClientRepo(db)
+	SessionRepo := postgres.NewSessionRepo(db)
+	ClientMemberRepo := postgres.NewClientMemberRepo(db)
+
+	ssoUsecase := usecase_user.NewSsoUseCase(UserRepo, ClientRepo, ClientMemberRepo)
+	sessionUsecase := usecase_user.NewSessionUseCase(SessionRepo, UserRepo)
+	lockoutUsecase := usecase_user.NewLockoutUseCase(UserRepo)
+	authEventUsecase := usecase_user.NewAuthEventUseCase(postgres.NewAuthEventRepo(db))
+	knownDeviceUsecase := usecase_user.NewKnownDeviceUseCase(postgres.NewKnownDeviceRepo(db), UserRepo, SessionRepo, postgres.NewApiKeyRepo(db))
+	return controller_user_auth.NewSsoController(ssoUsecase, sessionUsecase, lockoutUsecase, authEventUsecase, knownDeviceUsecase)
+}
+
+// SsoRoutes endpoint publik yang dibuka browser (tanpa header api key)
+func SsoRoutes(app fiber.Router, db *gorm.DB) {
+	ssoController := newSsoController(db)
+
+	app.Get("/sso/callback", middleware.RateLimit("login"), ssoController.SsoCallbackController)
+	app.Get("/sso/:clientId/login", middleware.RateLimit("login"), ssoController.SsoLoginController)
+}
+
+func SsoConfigRoutes(app fiber.Router, db *gorm.DB) {
+	ssoController := newSsoController(db)
+
+	app.Put("/clients/:id/sso", middleware.RequirePermission(model.PermissionClientsManage), ssoController.UpdateSsoConfigController)
+}
@@ -0,0 +1,25 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func TwoFactorRoutes(app fiber.Router, db *gorm.DB) {
+	UserRepo := postgres.NewUserRepo(db)
+	RecoveryCodeRepo := postgres.NewRecoveryCodeRepo(db)
+
+	twoFactorUsecase := usecase_user.NewTwoFactorUseCase(UserRepo, RecoveryCodeRepo)
+	twoFactorController := controller_user_auth.NewTwoFactorController(twoFactorUsecase)
+
+	r := app.Group("/2fa", middleware.RequireSession)
+	r.Get("/", twoFactorController.StatusController)
+	r.Post("/totp/enroll", twoFactorController.EnrollTOTPController)
+	r.Post("/totp/confirm", twoFactorController.ConfirmTOTPController)
+	r.Post("/totp/disable", twoFactorController.DisableTOTPController)
+}
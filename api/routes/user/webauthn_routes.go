@@ -0,0 +1,30 @@
+package routes_user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	controller_user_auth "xops-admin/api/controller/user/auth"
+	"xops-admin/api/routes/middleware"
+	"xops-admin/model"
+	postgres "xops-admin/repo/repo_postgres"
+	usecase_user "xops-admin/usecase/user/auth"
+)
+
+func WebauthnRoutes(app fiber.Router, db *gorm.DB) {
+	UserRepo := postgres.NewUserRepo(db)
+	ClientRepo := postgres.NewClientRepo(db)
+	WebauthnCredentialRepo := postgres.NewWebauthnCredentialRepo(db)
+
+	webauthnUsecase := usecase_user.NewWebauthnUseCase(WebauthnCredentialRepo, UserRepo, ClientRepo)
+	webauthnController := controller_user_auth.NewWebauthnController(webauthnUsecase)
+
+	r := app.Group("/auth/webauthn", middleware.RequireSession)
+	r.Post("/register/options", webauthnController.RegisterOptionsController)
+	r.Post("/register", webauthnController.RegisterController)
+	r.Get("/credentials", webauthnController.ListCredentialController)
+	r.Patch("/credentials/:id", webauthnController.RenameCredentialController)
+	r.Delete("/credentials/:id", webauthnController.DeleteCredentialController)
+
+	app.Put("/clients/:id/passkey-policy", middleware.RequirePermission(model.PermissionClientsManage), webauthnController.PasskeyPolicyController)
+}
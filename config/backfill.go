@@ -0,0 +1,57 @@
+package config
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"xops-admin/model"
+)
+
+// BackfillClientMembers membuat keanggotaan untuk data lama: pemilik client (clients.id_user)
+// menjadi owner dan user SSO (users.id_client) menjadi viewer. Aman dijalankan berulang.
+func BackfillClientMembers(db *gorm.DB) error {
+	var clients []model.Client
+	if err := db.Select("id", "id_user").Where("id_user <> ''").Find(&clients).Error; err != nil {
+		return err
+	}
+	for _, client := range clients {
+		if err := backfillMember(db, client.Id, client.IdUser, model.ClientMemberOwner); err != nil {
+			return err
+		}
+	}
+
+	var users []model.User
+	if err := db.Select("id", "id_client").Where("id_client IS NOT NULL AND id_client <> ''").Find(&users).Error; err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := backfillMember(db, user.IdClient, user.Id, model.ClientMemberViewer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func backfillMember(db *gorm.DB, idClient, idUser, role string) error {
+	member := model.ClientMember{}
+	return db.Where(model.ClientMember{IdUser: idUser}).
+		Attrs(model.ClientMember{Id: uuid.New().String(), IdClient: idClient, Role: role}).
+		FirstOrCreate(&member).Error
+}
+
+// BackfillDomainVerification menganggap domain yang sudah ada sebelum fitur verifikasi
+// sebagai terverifikasi supaya dashboard client lama tidak tiba-tiba kosong.
+func BackfillDomainVerification(db *gorm.DB) error {
+	return db.Model(&model.DomainClient{}).
+		Where("verification_status IS NULL OR verification_status = ''").
+		Update("verification_status", model.DomainVerificationVerified).Error
+}
+
+// ClearLegacyRefreshTokens mengosongkan kolom lama users.refresh_token. Refresh token sekarang
+// dilacak di tabel sessions, jadi token lama di kolom ini tidak boleh tersisa di database.
+func ClearLegacyRefreshTokens(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&model.User{}, "refresh_token") {
+		return nil
+	}
+	return db.Exec("UPDATE users SET refresh_token = NULL WHERE refresh_token IS NOT NULL").Error
+}
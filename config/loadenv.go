@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
@@ -26,10 +27,65 @@ type InitConfig struct {
 	RefreshTokenExpiresIn  time.Duration `mapstructure:"REFRESH_TOKEN_EXPIRED_IN"`
 	RefreshTokenMaxAge     int           `mapstructure:"REFRESH_TOKEN_MAXAGE"`
 
+	ImpersonationTokenExpiresIn time.Duration `mapstructure:"IMPERSONATION_TOKEN_EXPIRED_IN"`
+
+	// kunci publik lama (base64 PEM, dipisah koma) yang masih diterima setelah rotasi
+	AccessTokenRetiredPublicKeys  string `mapstructure:"ACCESS_TOKEN_RETIRED_PUBLIC_KEYS"`
+	RefreshTokenRetiredPublicKeys string `mapstructure:"REFRESH_TOKEN_RETIRED_PUBLIC_KEYS"`
+
 	RedisUri      string `mapstructure:"REDIS_URL"`
 	SMTP_HOST     string `mapstructure:"SMTP_HOST"`
 	FromEmailAddr string `mapstructure:"FromEmailAddr"`
 	SMTPpwd       string `mapstructure:"SMTPpwd"`
+	OtpHmacSecret string `mapstructure:"OTP_HMAC_SECRET"`
+
+	FrontendURL       string `mapstructure:"FRONTEND_URL"`
+	LinkSigningSecret string `mapstructure:"LINK_SIGNING_SECRET"`
+	OidcRedirectURL   string `mapstructure:"OIDC_REDIRECT_URL"`
+
+	WebauthnRPID    string `mapstructure:"WEBAUTHN_RP_ID"`
+	WebauthnRPName  string `mapstructure:"WEBAUTHN_RP_NAME"`
+	WebauthnOrigins string `mapstructure:"WEBAUTHN_ORIGINS"`
+
+	PasswordMinLength     int  `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordRequireUpper  bool `mapstructure:"PASSWORD_REQUIRE_UPPER"`
+	PasswordRequireLower  bool `mapstructure:"PASSWORD_REQUIRE_LOWER"`
+	PasswordRequireDigit  bool `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol bool `mapstructure:"PASSWORD_REQUIRE_SYMBOL"`
+
+	RateLimitPolicies  string `mapstructure:"RATE_LIMIT_POLICIES"`
+	RateLimitAllowList string `mapstructure:"RATE_LIMIT_ALLOWLIST"`
+	TrustedProxies     string `mapstructure:"TRUSTED_PROXIES"`
+	ProxyHeader        string `mapstructure:"PROXY_HEADER"`
+
+	// penyimpanan upload: "local" (disk, BLOB_LOCAL_DIR) atau "s3" (S3 / MinIO)
+	BlobBackend        string        `mapstructure:"BLOB_BACKEND"`
+	BlobLocalDir       string        `mapstructure:"BLOB_LOCAL_DIR"`
+	BlobPublicURL      string        `mapstructure:"BLOB_PUBLIC_URL"`
+	BlobURLExpiresIn   time.Duration `mapstructure:"BLOB_URL_EXPIRED_IN"`
+	BlobMaxUploadBytes int64         `mapstructure:"BLOB_MAX_UPLOAD_BYTES"`
+	S3Endpoint         string        `mapstructure:"S3_ENDPOINT"`
+	S3Region           string        `mapstructure:"S3_REGION"`
+	S3Bucket           string        `mapstructure:"S3_BUCKET"`
+	S3AccessKey        string        `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey        string        `mapstructure:"S3_SECRET_KEY"`
+	S3PathStyle        bool          `mapstructure:"S3_PATH_STYLE"`
+}
+
+// AccessTokenKeyring kunci publik aktif diikuti kunci yang sudah dipensiunkan
+func (c InitConfig) AccessTokenKeyring() string {
+	return joinKeyring(c.AccessTokenPublicKey, c.AccessTokenRetiredPublicKeys)
+}
+
+func (c InitConfig) RefreshTokenKeyring() string {
+	return joinKeyring(c.RefreshTokenPublicKey, c.RefreshTokenRetiredPublicKeys)
+}
+
+func joinKeyring(active, retired string) string {
+	if retired == "" {
+		return active
+	}
+	return active + "," + retired
 }
 
 func LoadConfig(path string) (config InitConfig, err error) {
@@ -45,5 +101,16 @@ func LoadConfig(path string) (config InitConfig, err error) {
 	}
 
 	err = viper.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+	// secret HMAC / tanda tangan link tidak punya default, tanpa itu OTP dan link email bisa dipalsukan
+	if config.OtpHmacSecret == "" {
+		err = fmt.Errorf("OTP_HMAC_SECRET must be set")
+		return
+	}
+	if config.LinkSigningSecret == "" {
+		err = fmt.Errorf("LINK_SIGNING_SECRET must be set")
+	}
 	return
 }
@@ -21,13 +21,29 @@ func ConnectionToMPostGresDB(config *InitConfig) *gorm.DB {
 		log.Fatal("Failed to connect to the Database! \n", err.Error())
 		os.Exit(1)
 	}
-	autoMigrate := DB.AutoMigrate(&model.Role{}, &model.User{}, &model.ListVulnerability{}, &model.ListBug{}, &model.ActivityLogPentester{}, &model.Client{}, &model.DomainClient{}, &model.TypeBug{})
+	autoMigrate := DB.AutoMigrate(&model.Role{}, &model.User{}, &model.ListVulnerability{}, &model.ListBug{}, &model.ActivityLogPentester{}, &model.Client{}, &model.DomainClient{}, &model.TypeBug{}, &model.RecoveryCode{}, &model.Session{}, &model.Permission{}, &model.ApiKey{}, &model.WebauthnCredential{}, &model.AuthEvent{}, &model.KnownDevice{}, &model.Impersonation{}, &model.ImpersonationRequest{}, &model.ClientMember{}, &model.ClientInvitation{}, &model.Engagement{}, &model.EngagementDomain{}, &model.EngagementUrlPattern{}, &model.ScopeRule{})
 
 	if autoMigrate != nil {
 		log.Fatal("Migration Failed:  \n", err.Error())
 		os.Exit(1)
 	}
 
+	if err := SeedPermissions(DB); err != nil {
+		log.Fatal("Seeding permissions failed: \n", err.Error())
+	}
+
+	if err := BackfillClientMembers(DB); err != nil {
+		log.Fatal("Backfilling client members failed: \n", err.Error())
+	}
+
+	if err := BackfillDomainVerification(DB); err != nil {
+		log.Fatal("Backfilling domain verification failed: \n", err.Error())
+	}
+
+	if err := ClearLegacyRefreshTokens(DB); err != nil {
+		log.Fatal("Clearing legacy refresh tokens failed: \n", err.Error())
+	}
+
 	log.Println("🚀 Connected Successfully to the Database")
 
 	return DB
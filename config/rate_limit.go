@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RateLimitKeyIP     = "ip"
+	RateLimitKeyUser   = "user"
+	RateLimitKeyApiKey = "api_key"
+)
+
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	KeyBy  string
+}
+
+// policy bawaan, bisa dioverride lewat RATE_LIMIT_POLICIES
+var defaultRateLimitPolicies = map[string]RateLimitPolicy{
+	"login":  {Name: "login", Limit: 5, Window: 15 * time.Minute, KeyBy: RateLimitKeyIP},
+	"otp":    {Name: "otp", Limit: 5, Window: 15 * time.Minute, KeyBy: RateLimitKeyUser},
+	"export": {Name: "export", Limit: 10, Window: time.Minute, KeyBy: RateLimitKeyUser},
+
+	"password_reset": {Name: "password_reset", Limit: 5, Window: 15 * time.Minute, KeyBy: RateLimitKeyIP},
+	"invitation":     {Name: "invitation", Limit: 10, Window: 15 * time.Minute, KeyBy: RateLimitKeyIP},
+}
+
+// ParseRateLimitPolicies membaca format "nama=limit/window:key" dipisah koma,
+// contoh "login=5/15m:ip,otp=3/10m:user". Policy yang tidak disebut memakai default.
+func ParseRateLimitPolicies(raw string) (map[string]RateLimitPolicy, error) {
+	policies := make(map[string]RateLimitPolicy, len(defaultRateLimitPolicies))
+	for name, policy := range defaultRateLimitPolicies {
+		policies[name] = policy
+	}
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, rule, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit policy %q: missing '='", entry)
+		}
+		rule, keyBy, hasKey := strings.Cut(rule, ":")
+		if !hasKey {
+			keyBy = RateLimitKeyIP
+		}
+		limitRaw, windowRaw, ok := strings.Cut(rule, "/")
+		if !ok {
+			return nil, fmt.Errorf("rate limit policy %q: expected limit/window", entry)
+		}
+		limit, err := strconv.Atoi(limitRaw)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("rate limit policy %q: invalid limit", entry)
+		}
+		window, err := time.ParseDuration(windowRaw)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("rate limit policy %q: invalid window", entry)
+		}
+		switch keyBy {
+		case RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyApiKey:
+		default:
+			return nil, fmt.Errorf("rate limit policy %q: unknown key %q", entry, keyBy)
+		}
+		name = strings.TrimSpace(name)
+		policies[name] = RateLimitPolicy{Name: name, Limit: limit, Window: window, KeyBy: keyBy}
+	}
+	return policies, nil
+}
+
+// ParseRateLimitAllowList berisi IP atau api key yang dilewatkan dari rate limit
+func ParseRateLimitAllowList(raw string) map[string]bool {
+	allowList := make(map[string]bool)
+	for _, entry := range strings.Split(raw, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			allowList[entry] = true
+		}
+	}
+	return allowList
+}
+
+// ParseTrustedProxies daftar IP / CIDR reverse proxy dipisah koma
+func ParseTrustedProxies(raw string) []string {
+	var proxies []string
+	for _, entry := range strings.Split(raw, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			proxies = append(proxies, entry)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"gorm.io/gorm"
+
+	"xops-admin/model"
+)
+
+// SeedPermissions memastikan semua permission bawaan ada dan setiap role bawaan memiliki
+// permission default-nya. Permission default yang belum dimiliki role ditambahkan, jadi
+// permission baru ikut masuk saat upgrade; permission tambahan dari admin tidak disentuh.
+func SeedPermissions(db *gorm.DB) error {
+	for _, permission := range model.DefaultPermissions {
+		permission := permission
+		if err := db.Where(model.Permission{Name: permission.Name}).
+			Attrs(model.Permission{Description: permission.Description}).
+			FirstOrCreate(&permission).Error; err != nil {
+			return err
+		}
+	}
+
+	defaults := map[int][]string{
+		model.RoleAdmin: permissionNames(model.DefaultPermissions),
+		// role client mendapat semua permission yang mungkin dimiliki anggota client,
+		// batas sebenarnya ditentukan role keanggotaan (owner / triager / viewer)
+		model.RoleClient: clientMemberPermissionNames(),
+	}
+	for roleID, names := range defaults {
+		var role model.Role
+		if err := db.Preload("Permissions").First(&role, "id = ?", roleID); err.RowsAffected == 0 {
+			continue
+		}
+		owned := make(map[string]bool, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			owned[permission.Name] = true
+		}
+		missing := make([]string, 0, len(names))
+		for _, name := range names {
+			if !owned[name] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		var permissions []model.Permission
+		if err := db.Where("name IN ?", missing).Find(&permissions).Error; err != nil {
+			return err
+		}
+		if err := db.Model(&role).Association("Permissions").Append(permissions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func permissionNames(permissions []model.Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+func clientMemberPermissionNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, role := range model.ClientMemberRoles {
+		for _, name := range model.ClientMemberPermissions[role] {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
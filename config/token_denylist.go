@@ -0,0 +1,54 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// key redis untuk denylist token, sengaja tidak pakai prefix "api:" supaya
+// tidak ikut terhapus saat cache api di-invalidate
+func denylistTokenKey(tokenUuid string) string {
+	return "auth:denylist:" + tokenUuid
+}
+
+func revokedUserKey(userID string) string {
+	return "auth:revoked_user:" + userID
+}
+
+// DenylistToken menandai token uuid sebagai tidak berlaku sampai token itu sendiri expired
+func DenylistToken(tokenUuid string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return RedisClient.Set(context.Background(), denylistTokenKey(tokenUuid), 1, ttl).Err()
+}
+
+func IsTokenDenylisted(tokenUuid string) (bool, error) {
+	n, err := RedisClient.Exists(context.Background(), denylistTokenKey(tokenUuid)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RevokeUserTokens membuat semua token user yang diterbitkan sebelum at tidak berlaku.
+// Waktu disimpan dalam milidetik. ttl cukup sepanjang umur token terlama (refresh token).
+func RevokeUserTokens(userID string, at time.Time, ttl time.Duration) error {
+	return RedisClient.Set(context.Background(), revokedUserKey(userID), at.UnixMilli(), ttl).Err()
+}
+
+// UserTokensRevokedAt mengembalikan unix milidetik revoke terakhir, 0 kalau tidak pernah
+func UserTokensRevokedAt(userID string) (int64, error) {
+	value, err := RedisClient.Get(context.Background(), revokedUserKey(userID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
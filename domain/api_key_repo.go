@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type ApiKeyRepository interface {
+	CreateApiKey(apiKey *model.ApiKey) error
+	FindApiKeyByHash(hash string) (*model.ApiKey, error)
+	FindApiKeyByID(id string) (*model.ApiKey, error)
+	FindApiKeysByUserID(idUser string) ([]model.ApiKey, error)
+	RevokeApiKey(id string) error
+	RevokeAllByUserID(idUser string) error
+	TouchApiKey(id, ip string, at time.Time) error
+}
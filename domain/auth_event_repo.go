@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type AuthEventFilter struct {
+	IdUser     string
+	Email      string
+	IP         string
+	EventTypes []string
+	Outcome    string
+	From       *time.Time
+	To         *time.Time
+	Offset     int
+	Limit      int
+}
+
+type AuthEventRepository interface {
+	CreateAuthEvent(event *model.AuthEvent) error
+	// FindAuthEvents mengembalikan event terbaru dulu sesuai filter
+	FindAuthEvents(filter AuthEventFilter) ([]model.AuthEvent, error)
+}
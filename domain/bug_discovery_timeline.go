@@ -39,27 +39,29 @@ type SearchResponse struct {
 
 type OverviewRepository interface {
 	// Chart 1: Vulnerability Timeline
-	GetVulnerabilityStats(ctx context.Context, days int, domainName, filter string) ([]domain_overview.VulnStat, error)
+	// Data setiap VulnStat sejajar dengan rng.Buckets(), bucket kosong bernilai 0
+	GetVulnerabilityStats(ctx context.Context, rng domain_overview.ChartRange, scope domain_overview.DataScope, filter string) ([]domain_overview.VulnStat, error)
 
 	// Chart 2: Bug Distributions
-	GetBugSeverityDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.SeverityDistribution, error)
-	GetBugStatusDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.StatusDistribution, error)
-	GetBugValidationDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.ValidationDistribution, error)
+	GetBugSeverityDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.SeverityDistribution, error)
+	GetBugStatusDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.StatusDistribution, error)
+	GetBugValidationDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.ValidationDistribution, error)
 
 	// Chart 3: Host Exposure and Pentester Activity
-	GetHostBugsExposure(ctx context.Context, domainName string, period int) ([]domain_overview.HostExposure, error)
-	GetPentestersActivity(ctx context.Context, domainName string, period int) ([]domain_overview.PentesterActivity, error)
+	GetHostBugsExposure(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.HostExposure, error)
+	GetPentestersActivity(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.PentesterActivity, error)
 
 	// Chart 4: Bug Type Frequency
-	GetBugTypeFrequency(ctx context.Context, domainName string, period int) ([]domain_overview.BugTypeFrequency, error)
+	GetBugTypeFrequency(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.BugTypeFrequency, error)
 
 	//
 	GetTotalFindingsWithTrend(
 		ctx context.Context,
-		domainName string,
+		scope domain_overview.DataScope,
+		rng domain_overview.ChartRange,
 	) (*domain_overview.ResponseTotalFindings, error)
 
-	GetPentestersEffectiveness(ctx context.Context, domainName string, period int) ([]domain_overview.PentesterEffectiveness, error)
+	GetPentestersEffectiveness(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.PentesterEffectiveness, error)
 
 	GetLogActivity(ctx context.Context, params domain_overview.LogActivityPaginationParams) (*domain_overview.LogActivityResponse, error)
 }
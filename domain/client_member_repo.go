@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"xops-admin/model"
+)
+
+type ClientMemberRepository interface {
+	CreateMember(member *model.ClientMember) error
+	FindMemberByID(id string) (*model.ClientMember, error)
+	FindMemberByUserID(idUser string) (*model.ClientMember, error)
+	// FindMembersByClientID termasuk data user tiap anggota
+	FindMembersByClientID(idClient string) ([]model.ClientMember, error)
+	CountOwners(idClient string) (int64, error)
+	DeleteMember(id string) error
+	// CreateUserWithMember membuat user baru sekaligus keanggotaannya dalam satu transaksi
+	CreateUserWithMember(user *model.User, member *model.ClientMember) error
+
+	CreateInvitation(invitation *model.ClientInvitation) error
+	FindInvitationByID(id string) (*model.ClientInvitation, error)
+	FindPendingInvitations(idClient string) ([]model.ClientInvitation, error)
+	DeleteInvitation(id string) error
+	// AcceptInvitation membuat user dan keanggotaannya lalu menandai undangan terpakai dalam satu transaksi
+	AcceptInvitation(invitation *model.ClientInvitation, user *model.User, member *model.ClientMember) error
+}
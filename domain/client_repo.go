@@ -2,19 +2,61 @@ package domain
 
 import (
 	"context"
+	"time"
+
 	domain_user "xops-admin/domain/user/client"
 	"xops-admin/model"
 
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
+type ClientFilter struct {
+	Query  string
+	Offset int
+	Limit  int
+}
+
 type ClientRepository interface {
 	CreateClient(client *model.Client) error
 	UpdateClient(client *model.Client) error
+	// UpdateClientWithEmail menyimpan client dan email user pemiliknya dalam satu transaksi,
+	// email kosong berarti email tidak diubah
+	UpdateClientWithEmail(client *model.Client, email string) error
+	UpdateSsoConfig(client *model.Client) error
+	SetRequirePasskey(clientID string, required bool) error
+	// PasskeyRequiredForUser true jika user anggota client yang mewajibkan passkey
+	PasskeyRequiredForUser(user *model.User) (bool, error)
 	GetClientByID(id string) (*model.Client, error)
+	// GetClientByUserID client tempat user menjadi anggota
 	GetClientByUserID(userID string) (*model.Client, error)
 	GetActiveDomainsByClientID(clientID string) ([]model.DomainClient, error)
 	DomainExistsForClient(clientID, domain string) (bool, error)
-	GetDomainByClientID(id string) (*model.DomainClient, error)
-	GetClientWithLastPentest(ctx context.Context, id string, domain string, es *elasticsearch.Client) (*domain_user.ClientPenTestInfo, error)
+	// DomainTaken true jika domain sudah dipakai client lain yang belum dihapus
+	DomainTaken(domain string) (bool, error)
+	// GetActiveDomainsByUserID domain aktif client tempat user menjadi anggota, terbaru dulu
+	GetActiveDomainsByUserID(userID string) ([]model.DomainClient, error)
+	// SearchClients client yang belum dihapus, Query dicocokkan ke nama perusahaan dan domain
+	SearchClients(filter ClientFilter) ([]model.Client, error)
+	AddDomain(domain *model.DomainClient) error
+	SetDomainActive(clientID, domainID string, active bool) error
+	GetDomainByID(clientID, domainID string) (*model.DomainClient, error)
+	// UpdateDomainVerification menyimpan hasil pengecekan kepemilikan beserta status aktifnya
+	UpdateDomainVerification(domainClient *model.DomainClient) error
+	DeleteDomain(clientID, domainID string) error
+	FindScopeRules(clientID string) ([]model.ScopeRule, error)
+	AddScopeRule(rule *model.ScopeRule) error
+	DeleteScopeRule(clientID, ruleID string) error
+	// UpdateContractWindow mengganti tanggal kontrak dan mereset status pengingat
+	UpdateContractWindow(id string, startDate, endDate time.Time) error
+	// FindClientsEndingBetween client yang hari terakhir kontraknya di antara from dan to
+	FindClientsEndingBetween(from, to time.Time) ([]model.Client, error)
+	// MarkContractReminder hanya berhasil jika status pengingat masih previous, supaya
+	// beberapa instance tidak mengirim email yang sama
+	MarkContractReminder(id string, previous, days int) (bool, error)
+	// FindClientsWithLegacyLogo client yang logonya masih tersimpan di folder static lama
+	FindClientsWithLegacyLogo() ([]model.Client, error)
+	UpdateLogo(id, logo string) error
+	// DeleteClient soft delete, data client dan domainnya tetap tersimpan untuk audit
+	DeleteClient(id string) error
+	GetClientWithLastPentest(ctx context.Context, id string, domains []string, es *elasticsearch.Client) (*domain_user.ClientPenTestInfo, error)
 }
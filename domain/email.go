@@ -22,6 +22,31 @@ type EmailData struct {
 	Data      string
 	FirstName string
 	Subject   string
+	// Device detail sign-in untuk email peringatan device baru
+	Device *EmailDevice
+	// Invitation detail undangan anggota client
+	Invitation *EmailInvitation
+	// Contract detail pengingat kontrak yang akan berakhir
+	Contract *EmailContract
+}
+
+type EmailDevice struct {
+	Time    string
+	IP      string
+	Browser string
+}
+
+type EmailContract struct {
+	Company  string
+	EndDate  string
+	DaysLeft int
+}
+
+type EmailInvitation struct {
+	Company   string
+	Role      string
+	InvitedBy string
+	ExpiresAt string
 }
 
 func ParseTemplateDir(dir string) (*template.Template, error) {
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"xops-admin/model"
+)
+
+type EngagementRepository interface {
+	// CreateEngagement menyimpan engagement beserta domain, pola URL dan pentesternya
+	CreateEngagement(engagement *model.Engagement) error
+	// UpdateEngagement mengganti seluruh scope engagement dalam satu transaksi
+	UpdateEngagement(engagement *model.Engagement) error
+	// FindEngagementByID termasuk domain, pola URL dan data pentester
+	FindEngagementByID(id string) (*model.Engagement, error)
+	FindEngagementsByClientID(idClient string) ([]model.Engagement, error)
+	DeleteEngagement(id string) error
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"xops-admin/model"
+)
+
+type ImpersonationFilter struct {
+	IdAdmin string
+	IdUser  string
+	Offset  int
+	Limit   int
+}
+
+type ImpersonationRepository interface {
+	CreateImpersonation(impersonation *model.Impersonation) error
+	// FindImpersonationByID termasuk semua request yang tercatat, urut dari yang paling awal
+	FindImpersonationByID(id string) (*model.Impersonation, error)
+	FindImpersonations(filter ImpersonationFilter) ([]model.Impersonation, error)
+	EndImpersonation(id string, reason string) error
+	EndImpersonationsByAdmin(idAdmin string, reason string) error
+	// CloseExpiredImpersonations mengisi ended_at untuk sesi yang tokennya sudah habis
+	CloseExpiredImpersonations() error
+	CreateImpersonationRequest(request *model.ImpersonationRequest) error
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type KnownDeviceRepository interface {
+	CreateDevice(device *model.KnownDevice) error
+	FindDevice(idUser, fingerprint string) (*model.KnownDevice, error)
+	FindDevicesByUserID(idUser string) ([]model.KnownDevice, error)
+	CountDevicesByUserID(idUser string) (int64, error)
+	TouchDevice(id string, at time.Time) error
+	DeleteDevice(idUser, id string) error
+}
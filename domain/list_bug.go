@@ -6,16 +6,17 @@ import (
 )
 
 type ListBugFilter struct {
-	Search     string `json:"search" query:"search"`
-	Severity   string `json:"severity" query:"severity"`
-	Status     string `json:"status" query:"status"`
-	FlagDomain string `json:"flag_domain" query:"flag_domain"`
-	SortBy     string `json:"sort_by" query:"sort_by"`
-	SortOrder  string `json:"sort_order" query:"sort_order"`
-	Direction  string `json:"direction" query:"direction"`
-	LastID     int    `json:"last_id" query:"last_id"`
-	LastTime   string `json:"last_time" query:"last_time"` // TAMBAHKAN INI
-	Convert    string `json:"convert" query:"convert"`     // NEW: For CSV export
+	Search       string   `json:"search" query:"search"`
+	Severity     string   `json:"severity" query:"severity"`
+	Status       string   `json:"status" query:"status"`
+	FlagDomains  []string `json:"flag_domains"`
+	IdEngagement string   `json:"id_engagement" query:"engagement_id"`
+	SortBy       string   `json:"sort_by" query:"sort_by"`
+	SortOrder    string   `json:"sort_order" query:"sort_order"`
+	Direction    string   `json:"direction" query:"direction"`
+	LastID       int      `json:"last_id" query:"last_id"`
+	LastTime     string   `json:"last_time" query:"last_time"` // TAMBAHKAN INI
+	Convert      string   `json:"convert" query:"convert"`     // NEW: For CSV export
 
 	Limit int `json:"limit" query:"limit"`
 }
@@ -49,6 +50,7 @@ type ListBug struct {
 	Validation     string `json:"validation"`
 	Vulnerability  string `gorm:"type:varchar(255);not null" json:"vulnerability"`
 	FlagDomain     string `gorm:"type:varchar(255)" json:"flag_domain,omitempty"`
+	IdEngagement   string `json:"id_engagement,omitempty"`
 	// Request       string    `gorm:"type:text" json:"request,omitempty"`
 	// Response      string    `gorm:"type:text" json:"response,omitempty"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
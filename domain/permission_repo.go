@@ -0,0 +1,8 @@
+package domain
+
+import "xops-admin/model"
+
+type PermissionRepository interface {
+	FindAllPermissions() ([]model.Permission, error)
+	FindPermissionsByNames(names []string) ([]model.Permission, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "xops-admin/model"
+
+type RecoveryCodeRepository interface {
+	ReplaceRecoveryCodes(idUser string, codes []model.RecoveryCode) error
+	FindUnusedByUserID(idUser string) ([]model.RecoveryCode, error)
+	MarkUsed(id string) error
+	DeleteByUserID(idUser string) error
+}
@@ -9,4 +9,6 @@ type RoleRepository interface {
 	FindAllRoles() ([]*model.Role, error)
 	DeleteRole(id int) error
 	UpdateRole(role *model.Role) error
+	ReplaceRolePermissions(role *model.Role, permissions []model.Permission) error
+	CountUsersByRoleID(id int) (int64, error)
 }
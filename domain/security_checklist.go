@@ -8,9 +8,11 @@ import (
 )
 
 type SecurityChecklistRepository interface {
-	GetTotalFindings(ctx context.Context, domainName string) (*[]domain_overview.SeverityCountTotalFindings, error)
-	GetTotalBugStatusList(ctx context.Context, domain_overviewName string) (*domain_overview.ResponseTotalBugStatusItem, error)
-	GetSecurityChecklistTable(ctx context.Context, domainName string, params domain_overview.PaginationParams) (*domain_overview.SecurityChecklistTableResponse, error)
-	GetSecurityChecklistDetailByESID(ctx context.Context, esID string) (*domain_overview.DetailIdSecurityChecklistItem, error)
-	GetURLList(ctx context.Context, flagDomain string, params domain_overview.URLListParams) (*domain_overview.URLListResponse, error)
+	GetTotalFindings(ctx context.Context, scope domain_overview.DataScope) (*[]domain_overview.SeverityCountTotalFindings, error)
+	GetTotalBugStatusList(ctx context.Context, scope domain_overview.DataScope) (*domain_overview.ResponseTotalBugStatusItem, error)
+	GetSecurityChecklistTable(ctx context.Context, scope domain_overview.DataScope, params domain_overview.PaginationParams) (*domain_overview.SecurityChecklistTableResponse, error)
+	GetSecurityChecklistDetailByESID(ctx context.Context, esID string, scope domain_overview.DataScope) (*domain_overview.DetailIdSecurityChecklistItem, error)
+	GetURLList(ctx context.Context, scope domain_overview.DataScope, params domain_overview.URLListParams) (*domain_overview.URLListResponse, error)
+	// GetOutOfScopeActivity traffic yang terbuang oleh rule scope client
+	GetOutOfScopeActivity(ctx context.Context, scope domain_overview.DataScope) (*domain_overview.OutOfScopeActivityResponse, error)
 }
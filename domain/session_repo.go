@@ -0,0 +1,15 @@
+package domain
+
+import "xops-admin/model"
+
+type SessionRepository interface {
+	CreateSession(session *model.Session) error
+	FindSessionByID(id string) (*model.Session, error)
+	UpdateSession(session *model.Session) error
+	// RotateRefreshToken hanya berhasil jika refresh token session masih previousTokenUuid,
+	// false berarti token sudah dirotasi request lain (reuse)
+	RotateRefreshToken(session *model.Session, previousTokenUuid string) (bool, error)
+	FindActiveByUserID(idUser string) ([]model.Session, error)
+	RevokeSession(id, reason string) error
+	RevokeAllByUserID(idUser, reason string) error
+}
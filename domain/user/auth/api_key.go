@@ -0,0 +1,51 @@
+package domain_user
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type CreateApiKeyRequest struct {
+	Name          string   `json:"name" validate:"required"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+type ApiKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateApiKeyResponse satu-satunya tempat key lengkap ditampilkan
+type CreateApiKeyResponse struct {
+	Key    string         `json:"key"`
+	ApiKey ApiKeyResponse `json:"api_key"`
+}
+
+type ApiKeyUseCase interface {
+	Create(idUser string, req *CreateApiKeyRequest) (*CreateApiKeyResponse, error)
+	List(idUser string) ([]ApiKeyResponse, error)
+	Revoke(idUser, id string) error
+}
+
+func ConvertApiKey(apiKey *model.ApiKey) ApiKeyResponse {
+	return ApiKeyResponse{
+		ID:         apiKey.Id,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.ScopeList(),
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		LastUsedIP: apiKey.LastUsedIP,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package domain_user
+
+import (
+	"xops-admin/model"
+)
+
+// AuthEventInput data yang dicatat dari controller auth
+type AuthEventInput struct {
+	IdUser    string
+	Email     string
+	EventType string
+	Outcome   string
+	Reason    string
+	IP        string
+	UserAgent string
+	SessionID string
+}
+
+type AuthEventSearchRequest struct {
+	IdUser    string `query:"user_id"`
+	Email     string `query:"email"`
+	IP        string `query:"ip"`
+	EventType string `query:"event_type"`
+	Outcome   string `query:"outcome"`
+	From      string `query:"from"`
+	To        string `query:"to"`
+	Page      int    `query:"page"`
+	Limit     int    `query:"limit"`
+}
+
+type AuthEventPagination struct {
+	Page        int  `json:"page"`
+	Size        int  `json:"size"`
+	HasNext     bool `json:"has_next"`
+	HasPrevious bool `json:"has_previous"`
+}
+
+type AuthEventListResponse struct {
+	Data       []model.AuthEvent   `json:"data"`
+	Pagination AuthEventPagination `json:"pagination"`
+}
+
+type AuthEventUseCase interface {
+	// Record tidak pernah gagal ke caller, error hanya di-log supaya alur login tidak terganggu
+	Record(input AuthEventInput)
+	RecentSignIns(idUser string, limit int) ([]model.AuthEvent, error)
+	Search(req *AuthEventSearchRequest) (*AuthEventListResponse, error)
+}
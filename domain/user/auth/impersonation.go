@@ -0,0 +1,37 @@
+package domain_user
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type StartImpersonationRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type StartImpersonationResponse struct {
+	Impersonation model.Impersonation `json:"impersonation"`
+	AccessToken   string              `json:"access_token"`
+	ExpiresAt     time.Time           `json:"expires_at"`
+}
+
+type ImpersonationSearchRequest struct {
+	IdAdmin string `query:"admin_id"`
+	IdUser  string `query:"user_id"`
+	Page    int    `query:"page"`
+	Limit   int    `query:"limit"`
+}
+
+type ImpersonationListResponse struct {
+	Data       []model.Impersonation `json:"data"`
+	Pagination AuthEventPagination   `json:"pagination"`
+}
+
+type ImpersonationUseCase interface {
+	// Start membuat catatan audit dan token akses read-only atas nama idUser
+	Start(idAdmin string, idUser string, req *StartImpersonationRequest, ip string, userAgent string) (*StartImpersonationResponse, error)
+	End(id string, reason string) error
+	Search(req *ImpersonationSearchRequest) (*ImpersonationListResponse, error)
+	Detail(id string) (*model.Impersonation, error)
+}
@@ -0,0 +1,16 @@
+package domain_user
+
+import "xops-admin/model"
+
+type NotMeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type KnownDeviceUseCase interface {
+	// CheckSignIn mengingat device user dan mengirim email peringatan jika device belum dikenal
+	CheckSignIn(user *model.User, ip, userAgent string)
+	ListDevices(idUser string) ([]model.KnownDevice, error)
+	ForgetDevice(idUser, id string) error
+	// ReportNotMe dipanggil dari link "this wasn't me": cabut semua session dan lupakan device tersebut
+	ReportNotMe(token string) error
+}
@@ -0,0 +1,31 @@
+package domain_user
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+const (
+	FailedAttemptPassword = "password"
+	FailedAttemptOTP      = "otp"
+)
+
+type LockoutStatusResponse struct {
+	Locked      bool      `json:"locked"`
+	LockedUntil time.Time `json:"locked_until,omitempty"`
+}
+
+type LockoutUseCase interface {
+	// CheckLocked mengembalikan errorenum.AccountLocked kalau akun sedang dikunci
+	CheckLocked(idUser string) error
+	// RegisterFailure mencatat percobaan gagal, mengembalikan errorenum.AccountLocked
+	// kalau percobaan ini membuat akun terkunci
+	RegisterFailure(user *model.User, kind string) error
+	// ResetFailures dipanggil setelah password / OTP benar
+	ResetFailures(idUser, kind string)
+	// ClearLock dipanggil setelah login lengkap, level backoff kembali ke awal
+	ClearLock(idUser string)
+	Status(idUser string) (*LockoutStatusResponse, error)
+	Unlock(idUser string) error
+}
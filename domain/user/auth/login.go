@@ -10,6 +10,9 @@ import (
 type LoginResponse struct {
 	AccessToken string `json:"access_token"`
 	Is2fa       bool   `json:"is_2fa"`
+	TwoFAMethod string `json:"two_fa_method"`
+	// MustChangePassword true berarti hanya endpoint change-password yang bisa dipakai
+	MustChangePassword bool `json:"must_change_password"`
 }
 
 type LoginRequest struct {
@@ -0,0 +1,8 @@
+package domain_user
+
+type LogoutUseCase interface {
+	// Logout memasukkan access token ke denylist sampai expired dan menutup session device ini
+	Logout(accessTokenUuid string, accessTokenExpires int64, sessionID string) error
+	// RevokeUserTokens mencabut semua access & refresh token yang pernah diterbitkan untuk user
+	RevokeUserTokens(idUser string) error
+}
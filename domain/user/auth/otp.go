@@ -0,0 +1,21 @@
+package domain_user
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type SendOtpResponse struct {
+	ResendAvailableAt  time.Time `json:"resend_available_at"`
+	ResendAfterSeconds int64     `json:"resend_after_seconds"`
+}
+
+type OtpUseCase interface {
+	// Issue membuat OTP baru dan mengirimkannya lewat email. Kalau masih dalam masa
+	// cooldown mengembalikan errorenum.OtpResendCooldown beserta kapan boleh kirim ulang.
+	Issue(user *model.User) (*SendOtpResponse, error)
+	// Verify mencocokkan kode; kode langsung hangus setelah berhasil atau setelah
+	// jumlah percobaan habis
+	Verify(idUser, code string) error
+}
@@ -0,0 +1,33 @@
+package domain_user
+
+import "xops-admin/model"
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+type ActivateAccountRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type PasswordUseCase interface {
+	// ForgotPassword mengirim link reset kalau email terdaftar. Tidak pernah memberi
+	// tahu apakah email ada supaya tidak bisa dipakai untuk enumerasi akun.
+	ForgotPassword(email string) error
+	ResetPassword(token, newPassword string) error
+	// SendActivationLink mengirim link sekali pakai untuk akun baru yang belum punya password
+	SendActivationLink(user *model.User) error
+	ActivateAccount(token, newPassword string) error
+	ChangePassword(idUser, currentPassword, newPassword string) error
+}
@@ -16,5 +16,4 @@ type RefreshTokenUsecase interface {
 	FindUserBYID(id string) (*model.User, error)
 	ValidateToken(token string, publicKey string) (*jwttoken.TokenDetails, error)
 	GenerateTokenJwt(jwtTokenTime time.Duration, userID string, privateKey string) (*jwttoken.TokenDetails, error)
-	SaveRefreshToken(token string, user *model.User) (*model.User, error)
 }
@@ -14,17 +14,20 @@ type CreateUserResponse struct {
 }
 type CreateUserWithClientRequest struct {
 	// User fields
-	Email        string `json:"email"`
-	Password     string `json:"password"`
-	IdRole       int    `json:"id_role"`
-	IsVerified   bool   `json:"is_verified"`
-	IsTwoFA      bool   `json:"is_2fa"`
-	VerifiedCode string `json:"verified_code"`
-	TOTPKey      string `json:"totp_key"`
-	RefreshToken string `json:"refresh_token"`
-	ApiKey       string `json:"api_key"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	IdRole     int    `json:"id_role"`
+	IsVerified bool   `json:"is_verified"`
+	IsTwoFA    bool   `json:"is_2fa"`
+	// MustChangePassword dipakai untuk akun yang dibuat admin, password diisi lewat link aktivasi
+	MustChangePassword bool   `json:"must_change_password"`
+	VerifiedCode       string `json:"verified_code"`
+	TOTPKey            string `json:"totp_key"`
+	RefreshToken       string `json:"refresh_token"`
+	ApiKey             string `json:"api_key"`
 
-	// Client fields
+	// Client fields, WithClient membuat record client walaupun role-nya bukan RoleClient
+	WithClient  bool      `json:"with_client"`
 	LogoCompany string    `json:"logo_company"`
 	CompanyName string    `json:"company_name"`
 	StartDate   time.Time `json:"start_date"`
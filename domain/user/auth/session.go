@@ -0,0 +1,49 @@
+package domain_user
+
+import (
+	"time"
+
+	"xops-admin/model"
+	jwttoken "xops-admin/util/token_jwt"
+)
+
+const (
+	SessionRevokedByUser        = "revoked_by_user"
+	SessionRevokedSignOut       = "sign_out_everywhere"
+	SessionRevokedReuse         = "refresh_token_reuse"
+	SessionRevokedPassword      = "password_changed"
+	SessionRevokedLogout        = "logout"
+	SessionRevokedByAdmin       = "revoked_by_admin"
+	SessionRevokedNotMe         = "reported_not_me"
+	SessionRevokedRemoved       = "member_removed"
+	SessionRevokedClientDeleted = "client_deleted"
+)
+
+type SessionMeta struct {
+	DeviceLabel string
+	UserAgent   string
+	IP          string
+}
+
+type SessionResponse struct {
+	ID          string    `json:"id"`
+	DeviceLabel string    `json:"device_label"`
+	UserAgent   string    `json:"user_agent"`
+	IP          string    `json:"ip"`
+	Current     bool      `json:"current"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type SessionUseCase interface {
+	// StartSession membuat session baru untuk device dan menerbitkan refresh token pertamanya
+	StartSession(user *model.User, meta SessionMeta) (*model.Session, *jwttoken.TokenDetails, error)
+	// RotateRefreshToken menukar refresh token lama dengan yang baru. Token yang sudah
+	// pernah dirotasi dianggap reuse dan seluruh session langsung dicabut.
+	RotateRefreshToken(refreshToken string, meta SessionMeta) (*model.User, *model.Session, *jwttoken.TokenDetails, error)
+	ValidateSession(sessionID, tokenUuid string) (*model.Session, error)
+	ListActiveSessions(idUser, currentSessionID string) ([]SessionResponse, error)
+	RevokeSession(idUser, sessionID string) error
+	RevokeAllSessions(idUser, reason string) error
+}
@@ -0,0 +1,31 @@
+package domain_user
+
+import (
+	"context"
+
+	"xops-admin/model"
+)
+
+// SsoState disimpan di Redis selama user berada di halaman login IdP
+type SsoState struct {
+	ClientID string `json:"client_id"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+type SsoConfigRequest struct {
+	Enabled        bool     `json:"enabled"`
+	Issuer         string   `json:"issuer"`
+	ClientID       string   `json:"client_id"`
+	ClientSecret   string   `json:"client_secret"`
+	AllowedDomains []string `json:"allowed_domains"`
+}
+
+type SsoUseCase interface {
+	// BeginLogin menyiapkan state + PKCE dan mengembalikan URL authorize milik IdP client
+	BeginLogin(ctx context.Context, clientID string) (string, error)
+	// CompleteLogin menukar code dari IdP, memverifikasi id_token lalu mencari atau
+	// membuat (just in time) user yang terhubung dengan client tersebut
+	CompleteLogin(ctx context.Context, state, code string) (*model.User, error)
+	UpdateSsoConfig(clientID string, req *SsoConfigRequest) error
+}
@@ -0,0 +1,46 @@
+package domain_user
+
+import (
+	"xops-admin/model"
+)
+
+const (
+	TwoFAMethodEmail = "email"
+	TwoFAMethodTOTP  = "totp"
+)
+
+type TOTPEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURL string `json:"otpauth_url"`
+	QRCode     string `json:"qr_code"`
+}
+
+type TOTPConfirmRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TOTPConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TOTPDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorStatusResponse struct {
+	Method                 string `json:"method"`
+	IsTwoFA                bool   `json:"is_2fa"`
+	PendingEnrollment      bool   `json:"pending_enrollment"`
+	RemainingRecoveryCodes int    `json:"remaining_recovery_codes"`
+}
+
+type TwoFactorUseCase interface {
+	FindUserBYID(id string) (*model.User, error)
+	Status(user *model.User) (*TwoFactorStatusResponse, error)
+	EnrollTOTP(user *model.User) (*TOTPEnrollResponse, error)
+	ConfirmTOTP(user *model.User, code string) (*TOTPConfirmResponse, error)
+	DisableTOTP(user *model.User, password, code string) error
+	// VerifySecondFactor menerima kode TOTP atau recovery code untuk user yang enroll TOTP
+	VerifySecondFactor(user *model.User, code string) error
+}
@@ -15,13 +15,18 @@ type Verified2faResponse struct {
 	Role         string `json:"role"`
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	// MustRegisterPasskey frontend harus langsung ke halaman registrasi passkey
+	MustRegisterPasskey bool `json:"must_register_passkey"`
 	// RefreshToken string    `json:"refresh_token"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Verified2faRequest struct {
-	Code string `json:"code" validate:"required"`
+	Code string `json:"code" validate:"required_without=Webauthn"`
+	// Webauthn assertion passkey, bisa dipakai sebagai pengganti Code
+	Webauthn    *WebauthnAssertion `json:"webauthn"`
+	DeviceLabel string             `json:"device_label"`
 }
 type SendOtpRequest struct {
 	Email string `json:"email" validate:"required"`
@@ -30,15 +35,15 @@ type SendOtpRequest struct {
 type Verified2faCase interface {
 	FindUserBYID(id string) (*model.User, error)
 	ValidateRegister2FA(user *model.User) error
-	UpdateUser(user *model.User, token string) error
+	UpdateUser(user *model.User) error
 	ValidateToken(token string, publicKey string) (*jwttoken.TokenDetails, error)
 	ConvertVerified2faResponse(user *model.User, AccessTokentoken, RefreshToken, RoleName string) Verified2faResponse
-	VerifyTOTP(code, key string) bool
+	VerifyTOTP(user *model.User, code string) (bool, error)
 	GenerateTokenJwt(jwtTokenTime time.Duration, userID string, privateKey string) (*jwttoken.TokenDetails, error)
 	FindRoleName(user *model.User) string
 
 	//v3
-	UserVerifyOtp(idUser, code string, duration int64) (*model.User, error)
-	SendOtpVerifedCode(user *model.User)
+	UserVerifyOtp(idUser, code string) (*model.User, error)
+	SendOtpVerifedCode(user *model.User) (*SendOtpResponse, error)
 	FindEmail(email string) (*model.User, error)
 }
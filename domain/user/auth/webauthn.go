@@ -0,0 +1,120 @@
+package domain_user
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type WebauthnRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type WebauthnUserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type WebauthnCredentialParam struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+type WebauthnCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+type WebauthnAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// WebauthnCreationOptions dikirim apa adanya ke navigator.credentials.create (field biner dalam base64url)
+type WebauthnCreationOptions struct {
+	Challenge              string                         `json:"challenge"`
+	RP                     WebauthnRelyingParty           `json:"rp"`
+	User                   WebauthnUserEntity             `json:"user"`
+	PubKeyCredParams       []WebauthnCredentialParam      `json:"pubKeyCredParams"`
+	Timeout                int                            `json:"timeout"`
+	Attestation            string                         `json:"attestation"`
+	ExcludeCredentials     []WebauthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection WebauthnAuthenticatorSelection `json:"authenticatorSelection"`
+}
+
+// WebauthnRequestOptions dikirim ke navigator.credentials.get
+type WebauthnRequestOptions struct {
+	Challenge        string                         `json:"challenge"`
+	RPID             string                         `json:"rpId"`
+	Timeout          int                            `json:"timeout"`
+	AllowCredentials []WebauthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+type WebauthnAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" validate:"required"`
+	AttestationObject string   `json:"attestationObject" validate:"required"`
+	Transports        []string `json:"transports"`
+}
+
+type WebauthnRegisterRequest struct {
+	Name     string                      `json:"name" validate:"required,max=100"`
+	ID       string                      `json:"id" validate:"required"`
+	Type     string                      `json:"type" validate:"required"`
+	Response WebauthnAttestationResponse `json:"response"`
+}
+
+type WebauthnAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" validate:"required"`
+	AuthenticatorData string `json:"authenticatorData" validate:"required"`
+	Signature         string `json:"signature" validate:"required"`
+	UserHandle        string `json:"userHandle"`
+}
+
+type WebauthnAssertion struct {
+	ID       string                    `json:"id" validate:"required"`
+	Type     string                    `json:"type" validate:"required"`
+	Response WebauthnAssertionResponse `json:"response"`
+}
+
+type WebauthnRenameRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type PasskeyPolicyRequest struct {
+	Required bool `json:"required"`
+}
+
+type WebauthnCredentialResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type WebauthnUseCase interface {
+	BeginRegistration(idUser string) (*WebauthnCreationOptions, error)
+	FinishRegistration(idUser string, req *WebauthnRegisterRequest) (*WebauthnCredentialResponse, error)
+	BeginAssertion(user *model.User) (*WebauthnRequestOptions, error)
+	// VerifyAssertion memverifikasi assertion terhadap challenge terakhir milik user
+	VerifyAssertion(user *model.User, assertion *WebauthnAssertion) error
+	ListCredentials(idUser string) ([]WebauthnCredentialResponse, error)
+	RenameCredential(idUser, id, name string) error
+	DeleteCredential(idUser, id string) error
+	// PasskeyRequired true jika client user mewajibkan passkey
+	PasskeyRequired(user *model.User) bool
+	HasCredentials(idUser string) bool
+	SetPasskeyPolicy(clientID string, required bool) error
+}
+
+func ConvertWebauthnCredential(credential *model.WebauthnCredential) WebauthnCredentialResponse {
+	return WebauthnCredentialResponse{
+		ID:         credential.Id,
+		Name:       credential.Name,
+		LastUsedAt: credential.LastUsedAt,
+		CreatedAt:  credential.CreatedAt,
+	}
+}
@@ -0,0 +1,40 @@
+package domain_user
+
+import (
+	"xops-admin/model"
+)
+
+// EngagementRequest format tanggal YYYY-MM-DD dan harus berada di dalam kontrak client.
+// Domains harus domain milik client, Pentesters berisi id user.
+type EngagementRequest struct {
+	Name        string   `json:"name" validate:"required,max=255"`
+	StartDate   string   `json:"start_date" validate:"required"`
+	EndDate     string   `json:"end_date" validate:"required"`
+	Domains     []string `json:"domains" validate:"required,min=1"`
+	UrlPatterns []string `json:"url_patterns"`
+	Pentesters  []string `json:"pentesters"`
+}
+
+type EngagementPentester struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type EngagementResponse struct {
+	model.Engagement
+	Pentesters []EngagementPentester `json:"pentesters"`
+}
+
+type EngagementUseCase interface {
+	// ListClientEngagements engagement organisasi user, dipakai sebagai pilihan "engagement_id" di dashboard
+	ListClientEngagements(idUser string) ([]EngagementResponse, error)
+
+	// endpoint admin untuk mengelola engagement client
+	ListEngagements(idClient string) ([]EngagementResponse, error)
+	GetEngagement(id string) (*EngagementResponse, error)
+	CreateEngagement(idClient string, req *EngagementRequest) (*EngagementResponse, error)
+	// UpdateEngagement mengganti seluruh scope engagement, temuan yang sudah tercatat tidak dipindah
+	UpdateEngagement(id string, req *EngagementRequest) (*EngagementResponse, error)
+	DeleteEngagement(id string) error
+}
@@ -0,0 +1,52 @@
+package domain_user
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type InviteMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Password string `json:"password" validate:"required"`
+}
+
+// InvitationPreview data yang ditampilkan di halaman terima undangan
+type InvitationPreview struct {
+	Email     string    `json:"email"`
+	Company   string    `json:"company"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type MemberResponse struct {
+	Id        string    `json:"id"`
+	IdUser    string    `json:"id_user"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MemberListResponse struct {
+	Members     []MemberResponse         `json:"members"`
+	Invitations []model.ClientInvitation `json:"invitations"`
+}
+
+type ClientMemberUseCase interface {
+	// ListMembers anggota dan undangan yang masih berlaku di organisasi user
+	ListMembers(idUser string) (*MemberListResponse, error)
+	// Invite hanya untuk owner, link undangan dikirim ke email
+	Invite(idUser string, req *InviteMemberRequest) (*model.ClientInvitation, error)
+	RevokeInvitation(idUser, idInvitation string) error
+	// RemoveMember hanya untuk owner dan organisasi harus tetap punya minimal satu owner
+	RemoveMember(idUser, idMember string) error
+	PreviewInvitation(token string) (*InvitationPreview, error)
+	AcceptInvitation(req *AcceptInvitationRequest) error
+}
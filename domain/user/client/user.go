@@ -1,7 +1,9 @@
 package domain_user
 
 import (
+	"context"
 	"mime/multipart"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
 
@@ -11,8 +13,102 @@ import (
 type ClientUseCase interface {
 	CreateUserClient(req *CreateClientRequest) (*ClientResponse, error)
 	UpdateUserClient(id string, req *UpdateClientRequest) error
-	GetClientWithLastPentest(clientID, domain string, es *elasticsearch.Client) (*ClientPenTestInfo, error)
-	GetDomainByClientID(id string) (*model.DomainClient, error)
+	GetClientWithLastPentest(clientID string, domains []string, es *elasticsearch.Client) (*ClientPenTestInfo, error)
+	// ListDomains domain aktif yang bisa dipilih user di dashboard
+	ListDomains(idUser string) (*SelectableDomainsResponse, error)
+
+	// endpoint admin untuk mengelola client dan domainnya
+	SearchClients(req *ClientSearchRequest) (*ClientListResponse, error)
+	GetClient(id string) (*model.Client, error)
+	AddDomain(clientID string, req *AddDomainRequest) (*model.DomainClient, error)
+	// SetDomainActive hanya bisa mengaktifkan domain yang sudah terverifikasi
+	SetDomainActive(clientID, domainID string, active bool) error
+	DomainVerification(clientID, domainID string) (*DomainVerificationResponse, error)
+	// VerifyDomain cek ulang record TXT / file well-known dan mengaktifkan domain jika lolos
+	VerifyDomain(ctx context.Context, clientID, domainID string) (*DomainVerificationResponse, error)
+	RemoveDomain(clientID, domainID string) error
+	ListScopeRules(clientID string) ([]model.ScopeRule, error)
+	// AddScopeRule rule include / exclude traffic proxy, berlaku di semua dashboard client
+	AddScopeRule(clientID string, req *ScopeRuleRequest) (*model.ScopeRule, error)
+	RemoveScopeRule(clientID, ruleID string) error
+	// UpdateContractWindow memperpanjang / memperbarui kontrak client
+	UpdateContractWindow(id string, req *ContractWindowRequest) (*model.Client, error)
+	// SendContractReminders mengirim pengingat 14 dan 3 hari sebelum kontrak berakhir
+	SendContractReminders(now time.Time) error
+	// RunContractReminders menjalankan SendContractReminders secara berkala sampai ctx selesai
+	RunContractReminders(ctx context.Context)
+	// DeleteClient soft delete dan mencabut session semua anggotanya
+	DeleteClient(id string) error
+	// MigrateLegacyLogos memindahkan logo dari folder static publik ke blob store
+	MigrateLegacyLogos(ctx context.Context) error
+}
+
+type ClientSearchRequest struct {
+	Query string `query:"q"`
+	Page  int    `query:"page"`
+	Limit int    `query:"limit"`
+}
+
+type ClientPagination struct {
+	Page        int  `json:"page"`
+	Size        int  `json:"size"`
+	HasNext     bool `json:"has_next"`
+	HasPrevious bool `json:"has_previous"`
+}
+
+type ClientListResponse struct {
+	Data       []model.Client   `json:"data"`
+	Pagination ClientPagination `json:"pagination"`
+}
+
+type AddDomainRequest struct {
+	Domain string `json:"domain" validate:"required,max=253"`
+}
+
+// DomainVerificationResponse status verifikasi beserta instruksi yang harus dipasang client
+type DomainVerificationResponse struct {
+	Id             string     `json:"id"`
+	Domain         string     `json:"domain"`
+	Active         bool       `json:"active"`
+	Status         string     `json:"status"`
+	Method         string     `json:"method"`
+	VerifiedAt     *time.Time `json:"verified_at"`
+	LastCheckedAt  *time.Time `json:"last_checked_at"`
+	LastCheckError string     `json:"last_check_error"`
+	TxtRecordName  string     `json:"txt_record_name"`
+	TxtRecordValue string     `json:"txt_record_value"`
+	FileURL        string     `json:"file_url"`
+	FileContent    string     `json:"file_content"`
+}
+
+// ContractWindowRequest format tanggal YYYY-MM-DD, EndDate berlaku sampai akhir hari
+type ContractWindowRequest struct {
+	StartDate string `json:"start_date" validate:"required"`
+	EndDate   string `json:"end_date" validate:"required"`
+}
+
+// ScopeRuleRequest Type host (glob hostname), path (awalan path) atau regex (regexp Lucene untuk seluruh URL)
+type ScopeRuleRequest struct {
+	Action      string `json:"action" validate:"required,oneof=include exclude"`
+	Type        string `json:"type" validate:"required,oneof=host path regex"`
+	Pattern     string `json:"pattern" validate:"required,max=500"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+type SetDomainActiveRequest struct {
+	Active *bool `json:"active" validate:"required"`
+}
+
+type SelectableDomain struct {
+	Id        string `json:"id"`
+	Domain    string `json:"domain"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// SelectableDomainsResponse pilihan untuk query param "domain", All dipakai untuk semua domain
+type SelectableDomainsResponse struct {
+	Domains []SelectableDomain `json:"domains"`
+	All     string             `json:"all"`
 }
 
 type ClientPenTestInfo struct {
@@ -30,19 +126,19 @@ type CreateClientRequest struct {
 	Logo        *multipart.FileHeader `json:"logo"`
 	IsVerified  bool                  `json:"is_verified"`
 	IsTwoFA     bool                  `json:"is_two_fa"`
+	IdRole      int                   `json:"id_role"`
 }
 
+// UpdateClientRequest domain diubah lewat endpoint domain tersendiri
 type UpdateClientRequest struct {
 	CompanyName string                `json:"company_name"`
 	Email       string                `json:"email"`
-	Domains     []string              `json:"domains"`
 	StartDate   string                `json:"start_date"`
 	EndDate     string                `json:"end_date"`
 	Logo        *multipart.FileHeader `json:"logo"`
 }
 
 type ClientResponse struct {
-	User     *model.User   `json:"user"`
-	Client   *model.Client `json:"client"`
-	Password string        `json:"password"`
+	User   *model.User   `json:"user"`
+	Client *model.Client `json:"client"`
 }
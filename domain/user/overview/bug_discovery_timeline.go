@@ -3,8 +3,6 @@ package domain_overview
 import (
 	"context"
 	"time"
-
-	"xops-admin/model"
 )
 
 type VulnStat struct {
@@ -12,6 +10,15 @@ type VulnStat struct {
 	Data []int64 `json:"data" bson:"data"`
 }
 
+// VulnerabilityChartResponse series chart timeline beserta label sumbu x setiap bucket
+type VulnerabilityChartResponse struct {
+	Interval string      `json:"interval"`
+	From     time.Time   `json:"from"`
+	To       time.Time   `json:"to"`
+	Labels   []string    `json:"labels"`
+	Series   []ChartData `json:"series"`
+}
+
 type Label struct {
 	Show     bool   `json:"show"`
 	Position string `json:"position"`
@@ -92,8 +99,8 @@ type PentesterActivity struct {
 	Value               int64  `json:"value"`
 	Color               string `json:"color"`
 	PerDayWorkingHours  string `json:"perDayWorkingHours"`
-	PerWeekWorkingHours string `json:"perWeekWorkingHours"`
-	UniqueDays          int64  `json:"uniqueDays"` // Number of unique days worked
+	PerWeekWorkingHours string `json:"perWeekWorkingHours"` // rata-rata jam kerja per minggu di range chart
+	UniqueDays          int64  `json:"uniqueDays"`          // Number of unique days worked
 }
 
 // Chart 4 structs - Bug Type Frequency
@@ -140,10 +147,9 @@ type LogActivityResponse struct {
 	Pagination PaginationInfo `json:"pagination"`
 }
 type LogActivityPaginationParams struct {
-	Search    string `json:"search" query:"search"`         // Search untuk name dan IP
-	StartDate string `json:"start_date" query:"start_date"` // Format: YYYY-MM-DD
-	EndDate   string `json:"end_date" query:"end_date"`     // Format: YYYY-MM-DD
-	Domain    string `json:"domain" query:"domain"`
+	Search string     `json:"search" query:"search"` // Search untuk name dan IP
+	Range  ChartRange `json:"-"`                     // dari from / to, start_date / end_date lama masih diterima
+	Scope  DataScope  `json:"-"`
 }
 type ResponseLogActivity struct {
 	Success bool   `json:"success"`
@@ -156,27 +162,27 @@ type ResponseLogActivity struct {
 
 type BugDiscoveryTimelineUseCase interface {
 	//1
-	GetVulnerabilityChart(ctx context.Context, period int, domainName, filter string) ([]ChartData, error)
+	GetVulnerabilityChart(ctx context.Context, rng ChartRange, scope DataScope, filter string) (*VulnerabilityChartResponse, error)
 	//2
-	GetBugSeverityDistribution(ctx context.Context, domainName string, period int, status string) ([]SeverityDistribution, error)
+	GetBugSeverityDistribution(ctx context.Context, scope DataScope, rng ChartRange, status string) ([]SeverityDistribution, error)
 	//
-	GetBugStatusDistribution(ctx context.Context, domainName string, period int, status string) ([]StatusDistribution, error)
+	GetBugStatusDistribution(ctx context.Context, scope DataScope, rng ChartRange, status string) ([]StatusDistribution, error)
 	//
-	GetBugValidationDistribution(ctx context.Context, domainName string, period int, status string) ([]ValidationDistribution, error)
+	GetBugValidationDistribution(ctx context.Context, scope DataScope, rng ChartRange, status string) ([]ValidationDistribution, error)
 
-	GetHostBugsExposure(ctx context.Context, domainName string, period int) ([]HostExposure, error)
+	GetHostBugsExposure(ctx context.Context, scope DataScope, rng ChartRange) ([]HostExposure, error)
 
-	GetPentestersActivityStats(ctx context.Context, domainName string, periode int) ([]PentesterActivity, error)
+	GetPentestersActivityStats(ctx context.Context, scope DataScope, rng ChartRange) ([]PentesterActivity, error)
 
-	GetBugTypeFrequency(ctx context.Context, domainName string, period int) ([]BugTypeFrequency, error)
+	GetBugTypeFrequency(ctx context.Context, scope DataScope, rng ChartRange) ([]BugTypeFrequency, error)
 
 	GetTotalFindingsWithTrend(
 		ctx context.Context,
-		domainName string,
+		scope DataScope,
+		rng ChartRange,
 	) (*ResponseTotalFindings, error)
 
-	GetRealTimePentesterStatus(ctx context.Context, domainName string) ([]PentesterEffectiveness, error)
+	GetRealTimePentesterStatus(ctx context.Context, scope DataScope, rng ChartRange) ([]PentesterEffectiveness, error)
 
 	GetLogActivity(ctx context.Context, params LogActivityPaginationParams) (*LogActivityResponse, error)
-	GetDomainByClientID(id string) (*model.DomainClient, error)
 }
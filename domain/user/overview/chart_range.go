@@ -0,0 +1,176 @@
+package domain_overview
+
+import (
+	"strings"
+	"time"
+
+	"xops-admin/helper/errorenum"
+)
+
+const (
+	BucketHour  = "hour"
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+
+	// ChartTimeZone zona waktu batas bucket, sama dengan zona yang dipakai query aktivitas pentester
+	ChartTimeZone = "Asia/Jakarta"
+
+	// batas jumlah bucket supaya range panjang dengan interval jam tidak membebani ES
+	maxChartBuckets = 1000
+)
+
+// minChartFrom batas bawah from, data pentest tidak ada yang lebih tua dari ini
+var minChartFrom = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ChartRange rentang waktu chart dari query param from / to, To eksklusif.
+// From kosong berarti tidak dibatasi (semua waktu), Interval hanya dipakai chart timeline.
+type ChartRange struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+}
+
+// ChartLocation zona ChartTimeZone, juga dipakai untuk menghitung hari kalender kontrak client
+func ChartLocation() *time.Location {
+	loc, err := time.LoadLocation(ChartTimeZone)
+	if err != nil {
+		loc = time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// ParseChartRange membaca from / to (RFC3339 atau YYYY-MM-DD, tanggal "to" ikut dihitung sampai
+// akhir hari). Jika from kosong dipakai period hari terakhir, lalu defaultDays; 0 berarti semua waktu.
+// Interval kosong dipilih otomatis sesuai panjang range.
+func ParseChartRange(from, to, interval string, period, defaultDays int, now time.Time) (ChartRange, error) {
+	loc := ChartLocation()
+	rng := ChartRange{To: now}
+
+	if to != "" {
+		parsed, dateOnly, err := parseChartTime(to, loc)
+		if err != nil {
+			return ChartRange{}, errorenum.InvalidChartRange
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		rng.To = parsed
+	}
+	switch {
+	case from != "":
+		parsed, _, err := parseChartTime(from, loc)
+		if err != nil {
+			return ChartRange{}, errorenum.InvalidChartRange
+		}
+		rng.From = parsed
+	case period > 0:
+		rng.From = rng.To.AddDate(0, 0, -period)
+	case defaultDays > 0:
+		rng.From = rng.To.AddDate(0, 0, -defaultDays)
+	}
+	if !rng.From.IsZero() && (rng.From.Before(minChartFrom) || !rng.From.Before(rng.To)) {
+		return ChartRange{}, errorenum.InvalidChartRange
+	}
+
+	rng.Interval = strings.ToLower(interval)
+	switch rng.Interval {
+	case "":
+		rng.Interval = autoInterval(rng.To.Sub(rng.From))
+	case BucketHour, BucketDay, BucketWeek, BucketMonth:
+	default:
+		return ChartRange{}, errorenum.InvalidBucketInterval
+	}
+	if !rng.From.IsZero() && rng.bucketCount(maxChartBuckets+1) > maxChartBuckets {
+		return ChartRange{}, errorenum.InvalidChartRange
+	}
+	return rng, nil
+}
+
+func parseChartTime(value string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+func autoInterval(span time.Duration) string {
+	switch {
+	case span <= 2*24*time.Hour:
+		return BucketHour
+	case span <= 90*24*time.Hour:
+		return BucketDay
+	case span <= 366*24*time.Hour:
+		return BucketWeek
+	}
+	return BucketMonth
+}
+
+// bucketStart awal bucket kalender yang memuat t, minggu dimulai hari Senin seperti di ES
+func (r ChartRange) bucketStart(t time.Time) time.Time {
+	t = t.In(ChartLocation())
+	switch r.Interval {
+	case BucketHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case BucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (r ChartRange) nextBucket(t time.Time) time.Time {
+	switch r.Interval {
+	case BucketHour:
+		return t.Add(time.Hour)
+	case BucketWeek:
+		return t.AddDate(0, 0, 7)
+	case BucketMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// Buckets awal setiap bucket dari From sampai sebelum To, kosong jika From tidak diisi
+func (r ChartRange) Buckets() []time.Time {
+	if r.From.IsZero() {
+		return nil
+	}
+	var buckets []time.Time
+	for t := r.bucketStart(r.From); t.Before(r.To); t = r.nextBucket(t) {
+		buckets = append(buckets, t)
+	}
+	return buckets
+}
+
+// bucketCount jumlah bucket, berhenti menghitung begitu mencapai limit
+func (r ChartRange) bucketCount(limit int) int {
+	n := 0
+	for t := r.bucketStart(r.From); t.Before(r.To) && n < limit; t = r.nextBucket(t) {
+		n++
+	}
+	return n
+}
+
+// Label teks sumbu x untuk bucket yang dimulai pada t
+func (r ChartRange) Label(t time.Time) string {
+	switch r.Interval {
+	case BucketHour:
+		return t.Format("2006-01-02 15:00")
+	case BucketMonth:
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
+}
+
+func (r ChartRange) Labels() []string {
+	buckets := r.Buckets()
+	labels := make([]string, 0, len(buckets))
+	for _, b := range buckets {
+		labels = append(labels, r.Label(b))
+	}
+	return labels
+}
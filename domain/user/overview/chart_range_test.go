@@ -0,0 +1,142 @@
+package domain_overview
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"xops-admin/helper/errorenum"
+)
+
+var testNow = time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+
+func mustParseChartRange(t *testing.T, from, to, interval string) ChartRange {
+	t.Helper()
+	rng, err := ParseChartRange(from, to, interval, 0, 0, testNow)
+	if err != nil {
+		t.Fatalf("ParseChartRange(%q, %q, %q): %v", from, to, interval, err)
+	}
+	return rng
+}
+
+func dayLabels(from time.Time, days int) []string {
+	labels := make([]string, 0, days)
+	for i := 0; i < days; i++ {
+		labels = append(labels, from.AddDate(0, 0, i).Format("2006-01-02"))
+	}
+	return labels
+}
+
+func TestChartRangeLabels(t *testing.T) {
+	cases := []struct {
+		name, from, to, interval string
+		want                     []string
+	}{
+		// 31 Januari ke Februari tidak boleh melompat ke Maret seperti AddDate(0, 1, 0) dari tanggal 31
+		{"month boundary", "2024-01-31", "2024-03-02", BucketMonth, []string{"2024-01", "2024-02", "2024-03"}},
+		// 2 hari Januari + 29 hari Februari + 1 Maret
+		{"day across month and leap day", "2024-01-30", "2024-03-01", BucketDay, dayLabels(time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC), 32)},
+		{"week starts monday", "2024-01-03", "2024-01-15", BucketWeek, []string{"2024-01-01", "2024-01-08", "2024-01-15"}},
+		{"year end", "2023-12-31", "2024-01-01", BucketDay, []string{"2023-12-31", "2024-01-01"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rng := mustParseChartRange(t, tc.from, tc.to, tc.interval)
+			if labels := rng.Labels(); !reflect.DeepEqual(labels, tc.want) {
+				t.Fatalf("labels = %v, want %v", labels, tc.want)
+			}
+		})
+	}
+}
+
+// batas bucket selalu di WIB meskipun input memakai offset zona lain yang sedang berganti DST
+func TestChartRangeAlignsToChartTimeZone(t *testing.T) {
+	// 10 Maret 2024 New York maju dari -05:00 ke -04:00
+	rng := mustParseChartRange(t, "2024-03-10T01:30:00-05:00", "2024-03-10T04:30:00-04:00", BucketHour)
+	wantKeys := []time.Time{
+		time.Date(2024, 3, 10, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC),
+	}
+	buckets := rng.Buckets()
+	if len(buckets) != len(wantKeys) {
+		t.Fatalf("buckets = %v", buckets)
+	}
+	for i, b := range buckets {
+		// key date_histogram ES adalah epoch millis awal bucket
+		if b.UnixMilli() != wantKeys[i].UnixMilli() {
+			t.Errorf("bucket %d = %s, want %s", i, b.UTC(), wantKeys[i])
+		}
+	}
+	wantLabels := []string{"2024-03-10 13:00", "2024-03-10 14:00", "2024-03-10 15:00"}
+	if labels := rng.Labels(); !reflect.DeepEqual(labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", labels, wantLabels)
+	}
+
+	// 3 November 2024 New York mundur ke -05:00, 20:00Z sudah 4 November di WIB
+	rng = mustParseChartRange(t, "2024-11-03T01:30:00-05:00", "2024-11-03T20:00:00Z", BucketDay)
+	buckets = rng.Buckets()
+	if len(buckets) != 2 || !buckets[0].Equal(time.Date(2024, 11, 2, 17, 0, 0, 0, time.UTC)) || !buckets[1].Equal(time.Date(2024, 11, 3, 17, 0, 0, 0, time.UTC)) {
+		t.Fatalf("buckets = %v", buckets)
+	}
+	if labels := rng.Labels(); !reflect.DeepEqual(labels, []string{"2024-11-03", "2024-11-04"}) {
+		t.Errorf("labels = %v", labels)
+	}
+}
+
+func TestParseChartRangeBucketLimit(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	exact := from.Add(maxChartBuckets * time.Hour).Format(time.RFC3339)
+	over := from.Add((maxChartBuckets + 1) * time.Hour).Format(time.RFC3339)
+
+	if _, err := ParseChartRange(from.Format(time.RFC3339), exact, BucketHour, 0, 0, testNow); err != nil {
+		t.Errorf("%d hour buckets rejected: %v", maxChartBuckets, err)
+	}
+	if _, err := ParseChartRange(from.Format(time.RFC3339), over, BucketHour, 0, 0, testNow); !errors.Is(err, errorenum.InvalidChartRange) {
+		t.Errorf("%d hour buckets: err = %v", maxChartBuckets+1, err)
+	}
+}
+
+func TestParseChartRangeRejects(t *testing.T) {
+	cases := []struct {
+		name, from, to, interval string
+		period                   int
+		want                     error
+	}{
+		{"from before floor", "0001-01-01", "", BucketHour, 0, errorenum.InvalidChartRange},
+		{"from just before floor", "1999-12-31", "", BucketMonth, 0, errorenum.InvalidChartRange},
+		{"huge period", "", "", BucketDay, 10000000, errorenum.InvalidChartRange},
+		{"far future to", "2000-01-01", "9999-12-31", BucketHour, 0, errorenum.InvalidChartRange},
+		{"to before from", "2024-02-01", "2024-01-01", BucketDay, 0, errorenum.InvalidChartRange},
+		{"bad date", "2024-13-01", "", BucketDay, 0, errorenum.InvalidChartRange},
+		{"bad interval", "2024-01-01", "", "minute", 0, errorenum.InvalidBucketInterval},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			done := make(chan error, 1)
+			go func() {
+				_, err := ParseChartRange(tc.from, tc.to, tc.interval, tc.period, 0, testNow)
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				if !errors.Is(err, tc.want) {
+					t.Fatalf("err = %v, want %v", err, tc.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("ParseChartRange did not return within a second")
+			}
+		})
+	}
+}
+
+func TestParseChartRangeAllTime(t *testing.T) {
+	rng, err := ParseChartRange("", "", "", 0, 0, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rng.From.IsZero() || rng.Buckets() != nil || rng.Interval != BucketMonth {
+		t.Errorf("rng = %+v", rng)
+	}
+}
@@ -0,0 +1,34 @@
+package domain_overview
+
+import "time"
+
+// AllDomains nilai query param "domain" untuk menggabungkan semua domain aktif client
+const AllDomains = "all"
+
+// ScopeRule salinan model.ScopeRule yang dibutuhkan query ES
+type ScopeRule struct {
+	Action  string
+	Type    string
+	Pattern string
+}
+
+// DataScope batas data yang boleh di-query: domain terpilih dan jendela kontrak client.
+// From / To kosong berarti tidak dibatasi waktu.
+type DataScope struct {
+	Domains []string
+	From    time.Time
+	To      time.Time
+	// Rules aturan include / exclude traffic milik client
+	Rules []ScopeRule
+	// IdEngagement terisi jika scope dipersempit ke satu engagement lewat query param "engagement_id"
+	IdEngagement string
+}
+
+// ScopeUseCase menerjemahkan query param "domain" dan "engagement_id" menjadi batas data
+// yang boleh di-query dashboard milik user
+type ScopeUseCase interface {
+	// ResolveDomains domain yang dipakai query sesuai query param "domain"
+	ResolveDomains(idUser string, selected string) ([]string, error)
+	// ResolveScope ResolveDomains ditambah jendela kontrak client, rule scope dan engagement
+	ResolveScope(idUser string, selected string, engagementID string) (DataScope, error)
+}
@@ -2,8 +2,7 @@ package domain_overview
 
 import (
 	"context"
-
-	"xops-admin/model"
+	"time"
 )
 
 type SeverityCountTotalFindings struct {
@@ -152,13 +151,32 @@ type BulkUpdateSecurityChecklistResponse struct {
 	InsertedCount int    `json:"inserted_count"`
 }
 
+// OutOfScopeActivityResponse laporan traffic yang cocok dengan rule out-of-scope client
+type OutOfScopeActivityResponse struct {
+	// Total semua traffic out-of-scope, Hosts hanya berisi host teratas
+	Total int64            `json:"total"`
+	Hosts []OutOfScopeHost `json:"hosts"`
+}
+
+type OutOfScopeHost struct {
+	Host       string                `json:"host"`
+	Total      int64                 `json:"total"`
+	Pentesters []OutOfScopePentester `json:"pentesters"`
+}
+
+type OutOfScopePentester struct {
+	Name     string     `json:"name"`
+	Total    int64      `json:"total"`
+	LastSeen *time.Time `json:"last_seen"`
+}
+
 type SecurityCheklistUseCase interface {
-	GetTotalFindings(ctx context.Context, domainName string) (*[]SeverityCountTotalFindings, error)
-	GetDomainByClientID(id string) (*model.DomainClient, error)
-	GetTotalBugStatusList(ctx context.Context, domain_overviewName string) (*ResponseTotalBugStatusItem, error)
-	GetSecurityChecklistTable(ctx context.Context, domainName string, params PaginationParams) (*SecurityChecklistTableResponse, error)
-	GetSecurityChecklistDetailByESID(ctx context.Context, esID string) (*DetailIdSecurityChecklistItem, error)
-	GetURLList(ctx context.Context, flagDomain string, params URLListParams) (*URLListResponse, error)
+	GetTotalFindings(ctx context.Context, scope DataScope) (*[]SeverityCountTotalFindings, error)
+	GetTotalBugStatusList(ctx context.Context, scope DataScope) (*ResponseTotalBugStatusItem, error)
+	GetSecurityChecklistTable(ctx context.Context, scope DataScope, params PaginationParams) (*SecurityChecklistTableResponse, error)
+	GetSecurityChecklistDetailByESID(ctx context.Context, esID string, scope DataScope) (*DetailIdSecurityChecklistItem, error)
+	GetURLList(ctx context.Context, scope DataScope, params URLListParams) (*URLListResponse, error)
+	GetOutOfScopeActivity(ctx context.Context, scope DataScope) (*OutOfScopeActivityResponse, error)
 	ListVulnerabilityNames(ctx context.Context, search string, page, limit int) ([]VulnerabilityItem, int64, error)
 	BulkUpdateSecurityChecklist(ctx context.Context, req BulkUpdateSecurityChecklistRequest) (*BulkUpdateSecurityChecklistResponse, error)
 }
@@ -0,0 +1,22 @@
+package domain_role
+
+import "xops-admin/model"
+
+type RoleRequest struct {
+	NameRole    string   `json:"name_role" validate:"required"`
+	Permissions []string `json:"permissions"`
+}
+
+type RolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+type RoleUseCase interface {
+	List() ([]*model.Role, error)
+	GetByID(id int) (*model.Role, error)
+	Create(req *RoleRequest) (*model.Role, error)
+	Update(id int, req *RoleRequest) (*model.Role, error)
+	Delete(id int) error
+	SetPermissions(id int, permissions []string) (*model.Role, error)
+	ListPermissions() ([]model.Permission, error)
+}
@@ -7,10 +7,12 @@ import (
 
 type UserRepository interface {
 	CreateUser(req *domain_user_auth.CreateUserWithClientRequest) error
+	InsertUser(user *model.User) error
 	FindUserBYID(id string) (*model.User, error)
 	FindUserBYEmail(email string) (*model.User, error)
 	UpdateUser(user *model.User) error
 	DeleteUser(id string) error
 	FindUserBYName(name string) (*model.User, error)
-	UserVerifyEmail(idUser, code string, duration int64) (*model.User, error)
+	// AdvanceTOTPStep menyimpan step TOTP hanya jika lebih baru dari yang tersimpan, false berarti kode dipakai ulang
+	AdvanceTOTPStep(id string, step int64) (bool, error)
 }
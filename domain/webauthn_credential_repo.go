@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"xops-admin/model"
+)
+
+type WebauthnCredentialRepository interface {
+	CreateCredential(credential *model.WebauthnCredential) error
+	FindCredentialsByUserID(idUser string) ([]model.WebauthnCredential, error)
+	FindCredentialByCredentialID(credentialID string) (*model.WebauthnCredential, error)
+	CountCredentialsByUserID(idUser string) (int64, error)
+	RenameCredential(idUser, id, name string) error
+	DeleteCredential(idUser, id string) error
+	TouchCredential(id string, signCount uint32, at time.Time) error
+}
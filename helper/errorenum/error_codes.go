@@ -5,35 +5,137 @@ import (
 )
 
 const (
-	MaxLengthExceeded                        = "The input value exceeds the allowed character limit"
-	SomethingError        apperror.ErrorType = "Something error!"
-	DataNotFound          apperror.ErrorType = "Data not Found"
-	Unauthorized          apperror.ErrorType = "Unauthorized"
-	OKSuccess             apperror.ErrorType = "OK"
-	Forbidden             apperror.ErrorType = "Forbidden"
-	InvalidRoutes         apperror.ErrorType = "Invalid routes!"
-	DuplicateEmail        apperror.ErrorType = "Email already exists"
-	FailedLogin           apperror.ErrorType = "Can't verify email or password. Please try again."
-	SuccessLogin          apperror.ErrorType = "You're almost there, please enter your code to continue."
-	AccountLocked         apperror.ErrorType = "Account temporarily locked due to multiple failed attempts."
-	CodeVerifiedNull      apperror.ErrorType = "Please enter verification code."
-	CodeVerifiedIsExpired apperror.ErrorType = "Code is expired. Please try a new one."
-	CodeVerifiedSuccess   apperror.ErrorType = "A new OTP has been sent. Please check your email."
-	CodeVerifiedFailed    apperror.ErrorType = "Can't verify the code you entered. Please check and retry."
-	InvalidName           apperror.ErrorType = "Name is required"
-	InvalidEmail          apperror.ErrorType = "Email is required"
-	InvalidPassword       apperror.ErrorType = "Password is required"
-	LogoCompanyRequired   apperror.ErrorType = "Logo company is required for client role"
-	CompanyNameRequired   apperror.ErrorType = "Company name is required for client role"
-	StartDateRequired     apperror.ErrorType = "Start date is required for client role"
-	EndDateRequired       apperror.ErrorType = "End date is required for client role"
-	InvalidDateRange      apperror.ErrorType = "start date must be before end date"
-	DomainsRequired       apperror.ErrorType = "At least one domain is required for client role"
-	InvalidDomainFormat   apperror.ErrorType = "Invalid domain format"
-	RateLimit             apperror.ErrorType = "Account temporarily locked due to multiple failed attempts."
-	FailedOtp             apperror.ErrorType = "Can't verify the code you entered. Please check and retry"
-	ExpiredOtp            apperror.ErrorType = "Link expired. Please resend the code"
-	CodeTidakValid        apperror.ErrorType = "Code tidak valid!"
-	SendOtp               apperror.ErrorType = "A new OTP has been sent. Please check again."
-	SuccessOtp            apperror.ErrorType = "Sign-in confirmed. You are now ready to use Dashboard"
+	MaxLengthExceeded                             = "The input value exceeds the allowed character limit"
+	SomethingError             apperror.ErrorType = "Something error!"
+	DataNotFound               apperror.ErrorType = "Data not Found"
+	Unauthorized               apperror.ErrorType = "Unauthorized"
+	OKSuccess                  apperror.ErrorType = "OK"
+	Forbidden                  apperror.ErrorType = "Forbidden"
+	InvalidRoutes              apperror.ErrorType = "Invalid routes!"
+	DuplicateEmail             apperror.ErrorType = "Email already exists"
+	FailedLogin                apperror.ErrorType = "Can't verify email or password. Please try again."
+	SuccessLogin               apperror.ErrorType = "You're almost there, please enter your code to continue."
+	AccountLocked              apperror.ErrorType = "Account temporarily locked due to multiple failed attempts."
+	CodeVerifiedNull           apperror.ErrorType = "Please enter verification code."
+	CodeVerifiedIsExpired      apperror.ErrorType = "Code is expired. Please try a new one."
+	CodeVerifiedSuccess        apperror.ErrorType = "A new OTP has been sent. Please check your email."
+	CodeVerifiedFailed         apperror.ErrorType = "Can't verify the code you entered. Please check and retry."
+	InvalidName                apperror.ErrorType = "Name is required"
+	InvalidEmail               apperror.ErrorType = "Email is required"
+	InvalidPassword            apperror.ErrorType = "Password is required"
+	LogoCompanyRequired        apperror.ErrorType = "Logo company is required for client role"
+	CompanyNameRequired        apperror.ErrorType = "Company name is required for client role"
+	StartDateRequired          apperror.ErrorType = "Start date is required for client role"
+	EndDateRequired            apperror.ErrorType = "End date is required for client role"
+	InvalidDateRange           apperror.ErrorType = "start date must be before end date"
+	DomainsRequired            apperror.ErrorType = "At least one domain is required for client role"
+	InvalidDomainFormat        apperror.ErrorType = "Invalid domain format"
+	RateLimit                  apperror.ErrorType = "Too many requests. Please try again later."
+	FailedOtp                  apperror.ErrorType = "Can't verify the code you entered. Please check and retry"
+	ExpiredOtp                 apperror.ErrorType = "Link expired. Please resend the code"
+	CodeTidakValid             apperror.ErrorType = "Code tidak valid!"
+	SendOtp                    apperror.ErrorType = "A new OTP has been sent. Please check again."
+	SuccessOtp                 apperror.ErrorType = "Sign-in confirmed. You are now ready to use Dashboard"
+	TOTPAlreadyEnabled         apperror.ErrorType = "Authenticator app is already enabled for this account."
+	TOTPNotEnrolled            apperror.ErrorType = "No authenticator enrollment in progress. Please start enrollment first."
+	TOTPNotEnabled             apperror.ErrorType = "Authenticator app is not enabled for this account."
+	TOTPEnabled                apperror.ErrorType = "Authenticator app enabled. Keep your recovery codes somewhere safe."
+	TOTPDisabled               apperror.ErrorType = "Authenticator app disabled. Sign-in codes will be sent by email."
+	SessionExpired             apperror.ErrorType = "Your session has ended. Please sign in again."
+	SessionRevoked             apperror.ErrorType = "Session revoked"
+	SessionsRevoked            apperror.ErrorType = "Signed out from all devices"
+	TokenRevoked               apperror.ErrorType = "Token has been revoked. Please sign in again."
+	LogoutSuccess              apperror.ErrorType = "You have been signed out."
+	UserTokensRevoked          apperror.ErrorType = "All tokens for this user have been revoked."
+	OtpResendCooldown          apperror.ErrorType = "Please wait before requesting a new code."
+	OtpAttemptsExceeded        apperror.ErrorType = "Too many incorrect codes. Please request a new code."
+	ResetLinkSent              apperror.ErrorType = "If the email is registered, a reset link has been sent."
+	InvalidResetLink           apperror.ErrorType = "Reset link is invalid or has expired. Please request a new one."
+	WeakPassword               apperror.ErrorType = "Password does not meet the password policy"
+	InvalidActivationLink      apperror.ErrorType = "Activation link is invalid or has expired. Please contact your administrator."
+	WrongCurrentPassword       apperror.ErrorType = "Current password is incorrect"
+	SamePassword               apperror.ErrorType = "New password must be different from the current password"
+	PasswordChangeNeeded       apperror.ErrorType = "You must change your password before continuing."
+	AccountActivated           apperror.ErrorType = "Account activated. Please sign in with your new password."
+	PasswordChanged            apperror.ErrorType = "Password changed. Please sign in again."
+	InvalidApiKey              apperror.ErrorType = "Invalid or expired API key"
+	InvalidApiKeyScope         apperror.ErrorType = "Unknown API key scope"
+	InvalidApiKeyExpiry        apperror.ErrorType = "API key expiry must be between 1 and 365 days"
+	ApiKeyCreated              apperror.ErrorType = "API key created. Copy it now, it will not be shown again."
+	ApiKeyRevoked              apperror.ErrorType = "API key revoked"
+	ApiKeyNotAllowed           apperror.ErrorType = "This endpoint cannot be used with an API key"
+	SsoNotEnabled              apperror.ErrorType = "Single sign-on is not enabled for this organisation"
+	SsoFailed                  apperror.ErrorType = "Single sign-on failed. Please try again."
+	SsoEmailNotAllowed         apperror.ErrorType = "Your email is not allowed to sign in to this organisation"
+	SsoAccountConflict         apperror.ErrorType = "This email already belongs to another account"
+	InvalidSsoConfig           apperror.ErrorType = "Issuer, client ID and allowed domains are required to enable single sign-on"
+	SsoConfigUpdated           apperror.ErrorType = "Single sign-on configuration updated"
+	InvalidPasskey             apperror.ErrorType = "Passkey verification failed"
+	PasskeyRequired            apperror.ErrorType = "Your organisation requires a passkey to sign in"
+	PasskeyEnrollmentNeeded    apperror.ErrorType = "You must register a passkey before continuing."
+	DuplicatePasskey           apperror.ErrorType = "This passkey is already registered"
+	PasskeyRegistered          apperror.ErrorType = "Passkey registered"
+	LastPasskeyRequired        apperror.ErrorType = "Your organisation requires at least one passkey"
+	PasskeyRemoved             apperror.ErrorType = "Passkey removed"
+	PasskeyPolicyUpdated       apperror.ErrorType = "Passkey policy updated"
+	InvalidDateFormat          apperror.ErrorType = "Invalid date format. Use RFC3339, e.g. 2024-01-31T00:00:00Z"
+	InvalidNotMeLink           apperror.ErrorType = "This link is invalid or has expired."
+	NotMeReported              apperror.ErrorType = "All sessions have been signed out. Please reset your password."
+	DeviceForgotten            apperror.ErrorType = "Device removed from your known devices"
+	ImpersonationNotAllowed    apperror.ErrorType = "Only verified client users can be impersonated"
+	ImpersonationReadOnly      apperror.ErrorType = "Read-only impersonation: changes are not allowed"
+	ImpersonationForbidden     apperror.ErrorType = "This endpoint is not available during impersonation"
+	ImpersonationEnded         apperror.ErrorType = "Impersonation has ended. Please start a new one."
+	NotImpersonating           apperror.ErrorType = "This request is not made under impersonation"
+	ImpersonationStarted       apperror.ErrorType = "Impersonation started"
+	ImpersonationStopped       apperror.ErrorType = "Impersonation ended"
+	NoActiveDomain             apperror.ErrorType = "No active domain is assigned to this account"
+	DomainNotAllowed           apperror.ErrorType = "Domain is not available for this account"
+	NotClientMember            apperror.ErrorType = "This account is not a member of any client organisation"
+	NotClientOwner             apperror.ErrorType = "Only organisation owners can manage members"
+	InvalidMemberRole          apperror.ErrorType = "Member role must be owner, triager or viewer"
+	AlreadyMember              apperror.ErrorType = "This user is already a member of a client organisation"
+	LastOwnerRequired          apperror.ErrorType = "An organisation must keep at least one owner"
+	InvalidInvitation          apperror.ErrorType = "This invitation is invalid or has expired."
+	InvitationEmailTaken       apperror.ErrorType = "This email already has an account"
+	InvitationSent             apperror.ErrorType = "Invitation sent"
+	InvitationRevoked          apperror.ErrorType = "Invitation revoked"
+	InvitationAccepted         apperror.ErrorType = "Invitation accepted. You can now sign in."
+	MemberRemoved              apperror.ErrorType = "Member removed"
+	InvalidDomain              apperror.ErrorType = "Invalid domain name"
+	DuplicateDomain            apperror.ErrorType = "Domain is already registered to a client"
+	DomainAdded                apperror.ErrorType = "Domain added"
+	DomainUpdated              apperror.ErrorType = "Domain updated"
+	DomainRemoved              apperror.ErrorType = "Domain removed"
+	ClientUpdated              apperror.ErrorType = "Client updated"
+	ClientDeleted              apperror.ErrorType = "Client deleted"
+	DomainNotVerified          apperror.ErrorType = "Domain ownership must be verified before it can be activated"
+	DomainVerified             apperror.ErrorType = "Domain ownership verified"
+	DomainVerificationFailed   apperror.ErrorType = "Domain ownership could not be verified yet"
+	InvalidContractWindow      apperror.ErrorType = "Contract dates must use YYYY-MM-DD and end on or after the start date"
+	ContractEnded              apperror.ErrorType = "Your contract has ended. The account is read-only."
+	ContractUpdated            apperror.ErrorType = "Contract window updated"
+	EngagementNotFound         apperror.ErrorType = "Engagement not found"
+	InvalidEngagement          apperror.ErrorType = "Engagement dates must use YYYY-MM-DD, end on or after the start date and fall inside the contract"
+	EngagementDomainNotAllowed apperror.ErrorType = "Engagement domains must belong to the client"
+	InvalidUrlPattern          apperror.ErrorType = "URL patterns must be absolute http(s) URLs, use * as wildcard"
+	InvalidPentester           apperror.ErrorType = "Pentester not found"
+	EngagementCreated          apperror.ErrorType = "Engagement created"
+	EngagementUpdated          apperror.ErrorType = "Engagement updated"
+	EngagementDeleted          apperror.ErrorType = "Engagement deleted"
+	InvalidScopeRule           apperror.ErrorType = "Invalid scope rule: host must be a hostname glob, path must start with / and regex must be a valid pattern without anchors"
+	ScopeRuleAdded             apperror.ErrorType = "Scope rule added"
+	ScopeRuleRemoved           apperror.ErrorType = "Scope rule removed"
+	InvalidLogo                apperror.ErrorType = "Logo must be a PNG, JPEG or GIF image"
+	LogoTooLarge               apperror.ErrorType = "Logo file or dimensions are too large"
+	InvalidBlobLink            apperror.ErrorType = "File link is invalid or has expired"
+	InvalidChartRange          apperror.ErrorType = "from and to must be RFC3339 timestamps or YYYY-MM-DD dates, with from not before 2000-01-01, to after from and at most 1000 buckets in between"
+	InvalidBucketInterval      apperror.ErrorType = "interval must be one of hour, day, week or month"
+	AccountUnlocked            apperror.ErrorType = "Account unlocked"
+	DuplicateRole              apperror.ErrorType = "Role name already exists"
+	RoleInUse                  apperror.ErrorType = "Role is still assigned to users"
+	RoleBuiltIn                apperror.ErrorType = "Built-in role cannot be removed or locked out"
+	InvalidPermission          apperror.ErrorType = "Unknown permission"
+	InvalidRole                apperror.ErrorType = "Role not found"
+	RoleNotClientScoped        apperror.ErrorType = "Client users can only be given a role limited to findings permissions"
 )
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/gofiber/fiber/v2"
@@ -13,7 +17,9 @@ import (
 	"gorm.io/gorm"
 
 	"xops-admin/api/routes"
+	routes_user "xops-admin/api/routes/user"
 	"xops-admin/config"
+	util_blobstore "xops-admin/util/blob_store"
 )
 
 func main() {
@@ -26,12 +32,34 @@ func main() {
 	postgresDB := config.ConnectionToMPostGresDB(&loadConfig)
 	elastic := config.ConnectionToElastic()
 	config.ConnectRedis(&loadConfig)
-	SetUpServer(postgresDB, elastic, ":8006")
+	blobStore, err := util_blobstore.NewFromConfig(&loadConfig)
+	if err != nil {
+		log.Fatalln("Failed to init blob store:", err)
+	}
+
+	// ctx dibatalkan saat SIGINT / SIGTERM supaya job latar belakang dan server berhenti bersama
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// job latar belakang client: migrasi logo sekali jalan sebelum server menerima request,
+	// lalu pengingat kontrak berkala
+	clientUsecase := routes_user.NewClientUseCase(postgresDB, blobStore)
+	if err := clientUsecase.MigrateLegacyLogos(ctx); err != nil {
+		log.Println("Failed to migrate legacy logos:", err)
+	}
+	go clientUsecase.RunContractReminders(ctx)
+	SetUpServer(ctx, &loadConfig, postgresDB, elastic, blobStore, ":8006")
 
 }
 
-func SetUpServer(postgresDB *gorm.DB, elastic *elasticsearch.Client, port string) {
-	app := fiber.New()
+func SetUpServer(ctx context.Context, loadConfig *config.InitConfig, postgresDB *gorm.DB, elastic *elasticsearch.Client, blobStore util_blobstore.BlobStore, port string) {
+	// header IP dari proxy hanya dipercaya jika koneksi datang dari TRUSTED_PROXIES,
+	// supaya rate limit tidak bisa dilewati dengan mengganti X-Forwarded-For
+	app := fiber.New(fiber.Config{
+		ProxyHeader:             loadConfig.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          config.ParseTrustedProxies(loadConfig.TrustedProxies),
+		EnableIPValidation:      true,
+	})
 	app.Use(logger.New())
 	//konfigurasi security
 	app.Use(helmet.New(helmet.Config{
@@ -74,7 +102,11 @@ func SetUpServer(postgresDB *gorm.DB, elastic *elasticsearch.Client, port string
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusOK).SendString("Hello World Server Running V3.3.2 🚀")
 	})
-	routes.SetUpRoutes(app, postgresDB, elastic)
+	routes.SetUpRoutes(app, postgresDB, elastic, blobStore)
+	go func() {
+		<-ctx.Done()
+		app.Shutdown()
+	}()
 	if err := app.Listen(port); err != nil {
 		panic(err)
 	}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// scope yang bisa diberikan ke API key. findings:read dipetakan ke permission di middleware,
+// export wajib untuk mengunduh file report (?convert=csv). Belum ada endpoint ingest di
+// service ini, jadi scope ingest belum disediakan.
+const (
+	ApiKeyScopeFindingsRead = "findings:read"
+	ApiKeyScopeExport       = "export"
+)
+
+var ApiKeyScopes = []string{ApiKeyScopeFindingsRead, ApiKeyScopeExport}
+
+// ApiKey hanya menyimpan hash key. Prefix disimpan apa adanya supaya user bisa
+// mengenali key-nya di daftar.
+type ApiKey struct {
+	Id         string     `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdUser     string     `gorm:"type:varchar(100);not null;index" json:"id_user"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(20);not null;index" json:"prefix"`
+	KeyHash    string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"type:text;not null" json:"-"`
+	ExpiresAt  *time.Time `gorm:"type:timestamp" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"type:timestamp" json:"last_used_at"`
+	LastUsedIP string     `gorm:"type:varchar(45)" json:"last_used_ip"`
+	RevokedAt  *time.Time `gorm:"type:timestamp" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"not null;default:now()" json:"created_at"`
+}
+
+func (k *ApiKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k *ApiKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *ApiKey) IsActive() bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt))
+}
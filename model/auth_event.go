@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	AuthEventLogin         = "login"
+	AuthEventOtpSend       = "otp_send"
+	AuthEventOtpVerify     = "otp_verify"
+	AuthEventPasskeyVerify = "passkey_verify"
+	AuthEventSsoLogin      = "sso_login"
+	AuthEventTokenRefresh  = "token_refresh"
+	AuthEventLogout        = "logout"
+
+	AuthOutcomeSuccess = "success"
+	AuthOutcomeFailure = "failure"
+)
+
+// SignInEvents event yang dianggap sebagai percobaan masuk ke akun
+var SignInEvents = []string{AuthEventLogin, AuthEventOtpVerify, AuthEventPasskeyVerify, AuthEventSsoLogin}
+
+// AuthEvent jejak autentikasi untuk investigasi insiden. IdUser kosong jika user tidak dikenali.
+type AuthEvent struct {
+	Id        string    `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdUser    string    `gorm:"type:varchar(100);index" json:"id_user"`
+	Email     string    `gorm:"type:varchar(100);index" json:"email"`
+	EventType string    `gorm:"type:varchar(50);not null;index" json:"event_type"`
+	Outcome   string    `gorm:"type:varchar(20);not null" json:"outcome"`
+	Reason    string    `gorm:"type:varchar(255)" json:"reason"`
+	IP        string    `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent string    `gorm:"type:text" json:"user_agent"`
+	SessionID string    `gorm:"type:varchar(100)" json:"session_id"`
+	CreatedAt time.Time `gorm:"not null;default:now();index" json:"created_at"`
+}
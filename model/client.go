@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Client struct {
 	Id           string         `gorm:"type:varchar(100);primary_key;not null" json:"id"`
@@ -8,6 +12,39 @@ type Client struct {
 	LogoCompany  string         `gorm:"type:varchar(100);not null" json:"logo_company" `
 	CompanyName  string         `gorm:"type:text;not null" json:"company_name" `
 	DomainClient []DomainClient `gorm:"foreignKey:IdClient;constraint:OnDelete:CASCADE"`
+	Engagements  []Engagement   `gorm:"foreignKey:IdClient;constraint:OnDelete:CASCADE" json:"engagements,omitempty"`
+	ScopeRules   []ScopeRule    `gorm:"foreignKey:IdClient;constraint:OnDelete:CASCADE" json:"scope_rules,omitempty"`
 	StartDate    time.Time      `gorm:"not null"`
 	EndDate      time.Time      `gorm:"not null"`
+	// konfigurasi SSO OpenID Connect per client
+	OidcEnabled        bool   `gorm:"not null;default:false" json:"oidc_enabled"`
+	OidcIssuer         string `gorm:"type:varchar(255)" json:"oidc_issuer"`
+	OidcClientID       string `gorm:"type:varchar(255)" json:"oidc_client_id"`
+	OidcClientSecret   string `gorm:"type:text" json:"-"`
+	OidcAllowedDomains string `gorm:"type:text" json:"oidc_allowed_domains"`
+	// RequirePasskey mewajibkan user client ini memakai WebAuthn sebagai faktor kedua
+	RequirePasskey bool `gorm:"not null;default:false" json:"require_passkey"`
+	// ContractReminderDays pengingat terakhir yang sudah dikirim (14 / 3 hari), 0 = belum ada
+	ContractReminderDays int `gorm:"not null;default:0" json:"-"`
+	// DeletedAt soft delete, client yang dihapus tidak lagi bisa memakai domainnya
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ContractStart awal jendela kontrak, StartDate berlaku sejak awal hari
+func (c *Client) ContractStart() time.Time {
+	return c.StartDate
+}
+
+// ContractEnd batas akhir (eksklusif) jendela kontrak, EndDate berlaku sampai akhir hari
+func (c *Client) ContractEnd() time.Time {
+	if c.EndDate.IsZero() {
+		return time.Time{}
+	}
+	return c.EndDate.AddDate(0, 0, 1)
+}
+
+// ContractExpired true setelah hari terakhir kontrak lewat, akun client menjadi read-only
+func (c *Client) ContractExpired(now time.Time) bool {
+	end := c.ContractEnd()
+	return !end.IsZero() && !now.Before(end)
 }
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// role anggota di dalam satu organisasi client
+const (
+	ClientMemberOwner   = "owner"
+	ClientMemberTriager = "triager"
+	ClientMemberViewer  = "viewer"
+)
+
+var ClientMemberRoles = []string{ClientMemberOwner, ClientMemberTriager, ClientMemberViewer}
+
+// ClientMemberPermissions batas atas permission anggota client. Permission efektif adalah
+// irisan permission role RBAC user dengan daftar ini, jadi viewer tidak pernah bisa triage
+// walau role RBAC-nya mengizinkan.
+var ClientMemberPermissions = map[string][]string{
+	ClientMemberOwner:   {PermissionFindingsRead, PermissionFindingsTriage},
+	ClientMemberTriager: {PermissionFindingsRead, PermissionFindingsTriage},
+	ClientMemberViewer:  {PermissionFindingsRead},
+}
+
+// IsClientScopedRole true jika semua permission role masih dalam batas permission anggota client,
+// hanya role seperti ini yang boleh diberikan ke user client
+func IsClientScopedRole(role *Role) bool {
+	for _, permission := range role.Permissions {
+		allowed := false
+		for _, names := range ClientMemberPermissions {
+			for _, name := range names {
+				if name == permission.Name {
+					allowed = true
+				}
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+func IsValidClientMemberRole(role string) bool {
+	_, ok := ClientMemberPermissions[role]
+	return ok
+}
+
+// ClientMember keanggotaan user di organisasi client. Satu user hanya anggota satu client.
+type ClientMember struct {
+	Id        string    `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdClient  string    `gorm:"type:varchar(100);not null;index" json:"id_client"`
+	IdUser    string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"id_user"`
+	Role      string    `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"created_at"`
+	User      *User     `gorm:"foreignKey:IdUser" json:"-"`
+}
+
+// ClientInvitation undangan anggota baru. Nonce disimpan supaya link hanya bisa dipakai sekali.
+type ClientInvitation struct {
+	Id         string     `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdClient   string     `gorm:"type:varchar(100);not null;index" json:"id_client"`
+	Email      string     `gorm:"type:varchar(100);not null;index" json:"email"`
+	Role       string     `gorm:"type:varchar(20);not null" json:"role"`
+	Nonce      string     `gorm:"type:varchar(64);not null" json:"-"`
+	InvitedBy  string     `gorm:"type:varchar(100);not null" json:"invited_by"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt *time.Time `gorm:"type:timestamp" json:"accepted_at"`
+	CreatedAt  time.Time  `gorm:"not null;default:now()" json:"created_at"`
+}
+
+func (i *ClientInvitation) IsPending() bool {
+	return i.AcceptedAt == nil && time.Now().Before(i.ExpiresAt)
+}
@@ -2,6 +2,13 @@ package model
 
 import "time"
 
+// status verifikasi kepemilikan domain, domain hanya bisa aktif jika verified
+const (
+	DomainVerificationPending  = "pending"
+	DomainVerificationVerified = "verified"
+	DomainVerificationFailed   = "failed"
+)
+
 type DomainClient struct {
 	Id        string    `gorm:"type:varchar(100);primary_key;not null" json:"id"`
 	IdClient  string    `gorm:"type:varchar(100);primary_key;not null" json:"id_client"`
@@ -9,4 +16,15 @@ type DomainClient struct {
 	Active    bool      `gorm:"type:bool;"`
 	CreatedAt time.Time `gorm:"not null;default:now()"`
 	UpdatedAt time.Time `gorm:"not null;default:now()"`
+	// VerificationToken dipasang client di record TXT atau file /.well-known/
+	VerificationToken  string     `gorm:"type:varchar(100)" json:"-"`
+	VerificationStatus string     `gorm:"type:varchar(20)" json:"verification_status"`
+	VerificationMethod string     `gorm:"type:varchar(10)" json:"verification_method"`
+	VerifiedAt         *time.Time `gorm:"type:timestamp" json:"verified_at"`
+	LastCheckedAt      *time.Time `gorm:"type:timestamp" json:"last_checked_at"`
+	LastCheckError     string     `gorm:"type:text" json:"last_check_error"`
+}
+
+func (d *DomainClient) IsVerified() bool {
+	return d.VerificationStatus == DomainVerificationVerified
 }
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Engagement satu periode pentest untuk client dengan domain, pola URL dan pentester sendiri
+type Engagement struct {
+	Id          string                 `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdClient    string                 `gorm:"type:varchar(100);not null;index" json:"id_client"`
+	Name        string                 `gorm:"type:varchar(255);not null" json:"name"`
+	StartDate   time.Time              `gorm:"not null" json:"start_date"`
+	EndDate     time.Time              `gorm:"not null" json:"end_date"`
+	Domains     []EngagementDomain     `gorm:"foreignKey:IdEngagement;constraint:OnDelete:CASCADE" json:"domains"`
+	UrlPatterns []EngagementUrlPattern `gorm:"foreignKey:IdEngagement;constraint:OnDelete:CASCADE" json:"url_patterns"`
+	Pentesters  []User                 `gorm:"many2many:engagement_pentesters;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt   time.Time              `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt   time.Time              `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+type EngagementDomain struct {
+	Id           string `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdEngagement string `gorm:"type:varchar(100);not null;index" json:"id_engagement"`
+	Domain       string `gorm:"type:text;not null" json:"domain"`
+}
+
+// EngagementUrlPattern pola URL in-scope, "*" cocok dengan karakter apa saja
+type EngagementUrlPattern struct {
+	Id           string `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdEngagement string `gorm:"type:varchar(100);not null;index" json:"id_engagement"`
+	Pattern      string `gorm:"type:text;not null" json:"pattern"`
+}
+
+// WindowStart awal engagement, StartDate berlaku sejak awal hari
+func (e *Engagement) WindowStart() time.Time {
+	return e.StartDate
+}
+
+// WindowEnd batas akhir (eksklusif) engagement, EndDate berlaku sampai akhir hari
+func (e *Engagement) WindowEnd() time.Time {
+	return e.EndDate.AddDate(0, 0, 1)
+}
+
+func (e *Engagement) DomainNames() []string {
+	names := make([]string, 0, len(e.Domains))
+	for _, d := range e.Domains {
+		names = append(names, d.Domain)
+	}
+	return names
+}
+
+// Covers true jika temuan pada domain, URL dan waktu tersebut masuk scope engagement.
+// Engagement tanpa pola URL mencakup semua URL di domainnya.
+func (e *Engagement) Covers(domain, url string, at time.Time) bool {
+	if at.Before(e.WindowStart()) || !at.Before(e.WindowEnd()) {
+		return false
+	}
+	inDomain := false
+	for _, d := range e.Domains {
+		if strings.EqualFold(d.Domain, domain) {
+			inDomain = true
+			break
+		}
+	}
+	if !inDomain {
+		return false
+	}
+	if len(e.UrlPatterns) == 0 {
+		return true
+	}
+	for _, p := range e.UrlPatterns {
+		if MatchUrlPattern(p.Pattern, url) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchUrlPattern mencocokkan URL dengan pola wildcard, tidak peka huruf besar/kecil
+func MatchUrlPattern(pattern, url string) bool {
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, `.*`)
+	re, err := regexp.Compile("(?i)^" + expr + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(url)
+}
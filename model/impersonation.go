@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+const (
+	ImpersonationEndedByAdmin    = "ended_by_admin"
+	ImpersonationEndedByOperator = "ended_by_operator"
+	ImpersonationEndedExpired    = "expired"
+	ImpersonationEndedReplaced   = "replaced"
+)
+
+// Impersonation satu sesi admin melihat dashboard sebagai user client. Selama sesi
+// ini semua request hanya boleh membaca dan setiap path dicatat di ImpersonationRequest.
+type Impersonation struct {
+	Id          string                 `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdAdmin     string                 `gorm:"type:varchar(100);not null;index" json:"id_admin"`
+	IdUser      string                 `gorm:"type:varchar(100);not null;index" json:"id_user"`
+	Reason      string                 `gorm:"type:varchar(255);not null" json:"reason"`
+	IP          string                 `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent   string                 `gorm:"type:text" json:"user_agent"`
+	StartedAt   time.Time              `gorm:"not null;default:now();index" json:"started_at"`
+	ExpiresAt   time.Time              `gorm:"not null" json:"expires_at"`
+	EndedAt     *time.Time             `gorm:"type:timestamp" json:"ended_at"`
+	EndedReason string                 `gorm:"type:varchar(50)" json:"ended_reason"`
+	Requests    []ImpersonationRequest `gorm:"foreignKey:IdImpersonation" json:"requests,omitempty"`
+}
+
+func (i *Impersonation) IsActive() bool {
+	return i.EndedAt == nil && time.Now().Before(i.ExpiresAt)
+}
+
+// ImpersonationRequest satu request yang dilakukan dengan token impersonation,
+// termasuk request tulis yang ditolak
+type ImpersonationRequest struct {
+	Id              string    `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdImpersonation string    `gorm:"type:varchar(100);not null;index" json:"id_impersonation"`
+	Method          string    `gorm:"type:varchar(10);not null" json:"method"`
+	Path            string    `gorm:"type:text;not null" json:"path"`
+	Status          int       `gorm:"not null" json:"status"`
+	Blocked         bool      `gorm:"not null;default:false" json:"blocked"`
+	CreatedAt       time.Time `gorm:"not null;default:now();index" json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// KnownDevice kombinasi IP + user agent yang pernah dipakai user untuk sign-in.
+// Fingerprint = sha256(ip|user agent), dipakai untuk deteksi sign-in dari device baru.
+type KnownDevice struct {
+	Id          string    `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdUser      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_known_device_user_fingerprint" json:"id_user"`
+	Fingerprint string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_known_device_user_fingerprint" json:"-"`
+	IP          string    `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent   string    `gorm:"type:text" json:"user_agent"`
+	Browser     string    `gorm:"type:varchar(100)" json:"browser"`
+	FirstSeenAt time.Time `gorm:"not null;default:now()" json:"first_seen_at"`
+	LastSeenAt  time.Time `gorm:"not null;default:now()" json:"last_seen_at"`
+}
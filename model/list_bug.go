@@ -17,6 +17,7 @@ type ListBug struct {
 	Validation          string    `gorm:"type:varchar(50);default:pending" json:"validation"`
 	Vulnerability       string    `gorm:"type:varchar(255);not null" json:"vulnerability"`
 	FlagDomain          string    `gorm:"type:varchar(255)" json:"flag_domain,omitempty"`
+	IdEngagement        string    `gorm:"type:varchar(100);index" json:"id_engagement,omitempty"` // kosong jika di luar scope engagement mana pun
 	Request             string    `gorm:"type:text" json:"request,omitempty"`
 	Response            string    `gorm:"type:text" json:"response,omitempty"`
 	CreatedAt           time.Time `gorm:"autoCreateTime" json:"created_at"`
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// nama permission yang dicek oleh middleware.RequirePermission
+const (
+	PermissionFindingsRead     = "findings:read"
+	PermissionFindingsTriage   = "findings:triage"
+	PermissionClientsManage    = "clients:manage"
+	PermissionCatalogEdit      = "catalog:edit"
+	PermissionRolesManage      = "roles:manage"
+	PermissionUsersManage      = "users:manage"
+	PermissionUsersImpersonate = "users:impersonate"
+)
+
+// role bawaan yang sudah dipakai sebelum ada tabel permission
+const (
+	RoleAdmin  = 1
+	RoleClient = 3
+)
+
+type Permission struct {
+	Id          int       `gorm:"primary_key;autoIncrement" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	Description string    `gorm:"type:varchar(255)" json:"description"`
+	CreatedAt   time.Time `gorm:"not null;default:now()" json:"created_at"`
+}
+
+// DefaultPermissions dipakai untuk seeding tabel permissions
+var DefaultPermissions = []Permission{
+	{Name: PermissionFindingsRead, Description: "View findings, overview and security checklist"},
+	{Name: PermissionFindingsTriage, Description: "Update finding status and security checklist"},
+	{Name: PermissionClientsManage, Description: "Create and update clients"},
+	{Name: PermissionCatalogEdit, Description: "Edit bug types and vulnerability catalog"},
+	{Name: PermissionRolesManage, Description: "Manage roles and their permissions"},
+	{Name: PermissionUsersManage, Description: "Manage users and revoke their tokens"},
+	{Name: PermissionUsersImpersonate, Description: "View the dashboard as a client user (read-only)"},
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type RecoveryCode struct {
+	Id        string     `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdUser    string     `gorm:"type:varchar(100);not null;index" json:"id_user"`
+	CodeHash  string     `gorm:"type:varchar(100);not null" json:"-"`
+	UsedAt    *time.Time `gorm:"type:timestamp" json:"used_at"`
+	CreatedAt time.Time  `gorm:"not null;default:now()" json:"created_at"`
+}
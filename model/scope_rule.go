@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// aksi rule scope: include mempersempit traffic yang dihitung, exclude membuang traffic
+const (
+	ScopeRuleInclude = "include"
+	ScopeRuleExclude = "exclude"
+)
+
+// jenis pola rule scope
+const (
+	ScopeRuleHost  = "host"  // glob hostname, contoh *.cloudfront.net
+	ScopeRulePath  = "path"  // awalan path URL, contoh /oauth2/
+	ScopeRuleRegex = "regex" // regex Lucene terhadap seluruh URL
+)
+
+// ScopeRule aturan in-scope / out-of-scope traffic proxy per client
+type ScopeRule struct {
+	Id          string    `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdClient    string    `gorm:"type:varchar(100);not null;index" json:"id_client"`
+	Action      string    `gorm:"type:varchar(10);not null" json:"action"`
+	Type        string    `gorm:"type:varchar(10);not null" json:"type"`
+	Pattern     string    `gorm:"type:text;not null" json:"pattern"`
+	Description string    `gorm:"type:varchar(255)" json:"description"`
+	CreatedAt   time.Time `gorm:"not null;default:now()" json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Session adalah satu login per device. Semua refresh token hasil rotasi dari
+// login yang sama berbagi satu Session (token family).
+type Session struct {
+	Id               string     `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdUser           string     `gorm:"type:varchar(100);not null;index" json:"id_user"`
+	DeviceLabel      string     `gorm:"type:varchar(255)" json:"device_label"`
+	UserAgent        string     `gorm:"type:text" json:"user_agent"`
+	IP               string     `gorm:"type:varchar(45)" json:"ip"`
+	RefreshTokenUuid string     `gorm:"type:varchar(100);not null" json:"-"`
+	CreatedAt        time.Time  `gorm:"not null;default:now()" json:"created_at"`
+	LastSeenAt       time.Time  `gorm:"not null;default:now()" json:"last_seen_at"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt        *time.Time `gorm:"type:timestamp" json:"revoked_at"`
+	RevokedReason    string     `gorm:"type:varchar(100)" json:"revoked_reason"`
+}
+
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
@@ -8,31 +8,46 @@ import (
 )
 
 type User struct {
-	Id                   string                 `gorm:"type:varchar(100);primary_key;not null" json:"id"`
-	Name                 string                 `gorm:"type:varchar(100);not null;"`
-	Email                string                 `gorm:"type:varchar(100);not null;uniqueIndex;" json:"email" `
-	Password             string                 `gorm:"type:varchar(100);not null" json:"password"`
-	IdRole               int                    `gorm:"type:varchar(50);not null"`
-	IsVerified           bool                   `gorm:"not null;default:true"`
-	IsTwoFA              bool                   `gorm:"not null; default:false" json:"is_2fa"`
-	VerifiedCode         string                 `gorm:"type:varchar(100);not null" json:"verified_code"`
-	TOTPKey              string                 `gorm:"type:varchar(255)" json:"totp_key"`
-	RefreshToken         string                 `gorm:"type:text" json:"token"`
-	ApiKey               string                 `gorm:"type:text" json:"api_key"`
+	Id                 string `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	Name               string `gorm:"type:varchar(100);not null;"`
+	Email              string `gorm:"type:varchar(100);not null;uniqueIndex;" json:"email" `
+	Password           string `gorm:"type:varchar(100);not null" json:"-"`
+	MustChangePassword bool   `gorm:"not null;default:false" json:"must_change_password"`
+	// MustRegisterPasskey aktif saat client mewajibkan passkey tapi user belum punya
+	MustRegisterPasskey bool   `gorm:"not null;default:false" json:"must_register_passkey"`
+	IdRole              int    `gorm:"type:varchar(50);not null"`
+	IsVerified          bool   `gorm:"not null;default:true"`
+	IsTwoFA             bool   `gorm:"not null; default:false" json:"is_2fa"`
+	VerifiedCode        string `gorm:"type:varchar(100);not null" json:"-"`
+	TOTPKey             string `gorm:"type:varchar(255)" json:"-"`
+	TOTPPendingKey      string `gorm:"type:varchar(255)" json:"-"`
+	TwoFAMethod         string `gorm:"type:varchar(20);not null;default:email" json:"two_fa_method"`
+	// TOTPLastStep time-step TOTP terakhir yang diterima, kode di step yang sama atau lebih lama ditolak
+	TOTPLastStep int64 `gorm:"not null;default:0" json:"-"`
+	// IdClient kolom lama untuk user SSO, tidak dipakai lagi sejak ada tabel client_members
+	IdClient string `gorm:"type:varchar(100);index" json:"id_client"`
+	// ApiKey kolom lama (plaintext), tidak dipakai lagi sejak ada tabel api_keys
+	ApiKey               string                 `gorm:"type:text" json:"-"`
 	ActivityLogPentester []ActivityLogPentester `gorm:"foreignKey:IdUser;constraint:OnDelete:CASCADE"`
 	Client               []Client               `gorm:"foreignKey:IdUser;constraint:OnDelete:CASCADE"`
+	RecoveryCode         []RecoveryCode         `gorm:"foreignKey:IdUser;constraint:OnDelete:CASCADE"`
+	Session              []Session              `gorm:"foreignKey:IdUser;constraint:OnDelete:CASCADE"`
+	ApiKeys              []ApiKey               `gorm:"foreignKey:IdUser;constraint:OnDelete:CASCADE"`
+	WebauthnCredentials  []WebauthnCredential   `gorm:"foreignKey:IdUser;constraint:OnDelete:CASCADE"`
 	CreatedAt            time.Time              `gorm:"not null;default:now()"`
 	UpdatedAt            time.Time              `gorm:"not null;defauslt:now()"`
 }
 
 var validate = validator.New()
 var customMessages = map[string]string{
-	"Code.required": "Verification code is required. Please enter it to continue",
+	"Code.required":         "Verification code is required. Please enter it to continue",
+	"Code.required_without": "Verification code or passkey is required. Please enter it to continue",
 }
 
 type UserResponse struct {
 	ID          string    `json:"id"`
 	Email       string    `json:"email"`
+	IdRole      int       `json:"id_role"`
 	Username    string    `json:"username"`
 	Verified    bool      `json:"verified"`
 	IconProfile string    `json:"icon_profile"`
@@ -45,6 +60,7 @@ func ConvertUser(user *User) UserResponse {
 	return UserResponse{
 		ID:        user.Id,
 		Email:     user.Email,
+		IdRole:    user.IdRole,
 		Verified:  user.IsVerified,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// WebauthnCredential passkey / security key milik user. PublicKey disimpan dalam format PKIX DER.
+type WebauthnCredential struct {
+	Id           string     `gorm:"type:varchar(100);primary_key;not null" json:"id"`
+	IdUser       string     `gorm:"type:varchar(100);not null;index" json:"id_user"`
+	CredentialID string     `gorm:"type:varchar(1024);not null;uniqueIndex" json:"-"`
+	PublicKey    []byte     `gorm:"type:bytea;not null" json:"-"`
+	Algorithm    int        `gorm:"not null" json:"-"`
+	SignCount    uint32     `gorm:"not null;default:0" json:"-"`
+	Name         string     `gorm:"type:varchar(100);not null" json:"name"`
+	LastUsedAt   *time.Time `gorm:"type:timestamp" json:"last_used_at"`
+	CreatedAt    time.Time  `gorm:"not null;default:now()" json:"created_at"`
+}
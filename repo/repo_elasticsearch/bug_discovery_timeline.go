@@ -29,29 +29,11 @@ func NewBugDiscoveryTimelineRepo(client *elasticsearch.Client) domain.OverviewRe
 }
 
 func (r *BugDiscoveryTimelineRepo) GetLogActivity(ctx context.Context, params domain_overview.LogActivityPaginationParams) (*domain_overview.LogActivityResponse, error) {
-	var startDate, endDate time.Time
-	var err error
-
-	if params.StartDate != "" {
-		startDate, err = time.Parse("2006-01-02", params.StartDate)
-		if err != nil {
-			return nil, fmt.Errorf("invalid start_date format: %w", err)
-		}
-	}
-
-	if params.EndDate != "" {
-		endDate, err = time.Parse("2006-01-02", params.EndDate)
-		if err != nil {
-			return nil, fmt.Errorf("invalid end_date format: %w", err)
-		}
-	} else {
-		endDate = time.Now()
-	}
-
 	// Default domain
-	domainName := params.Domain
+	domainNames := params.Scope.Domains
 
-	query := r.buildLogActivityQueryWithPagination(domainName, startDate, endDate, params)
+	query := r.buildLogActivityQueryWithPagination(domainNames, params)
+	query = withDataScope(query, params.Scope)
 
 	// DEBUG: Print the query being sent
 	fmt.Printf("=== QUERY DEBUG ===\n")
@@ -66,36 +48,23 @@ func (r *BugDiscoveryTimelineRepo) GetLogActivity(ctx context.Context, params do
 
 	return r.parseLogActivityWithPagination(response, params)
 }
-func (r *BugDiscoveryTimelineRepo) buildLogActivityQueryWithPagination(domainName string, startDate, endDate time.Time, params domain_overview.LogActivityPaginationParams) map[string]interface{} {
-	// Default range 1 bulan terakhir (kalau StartDate/EndDate kosong)
-	startOfDay := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
-	endOfDay := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, endDate.Location())
-
-	startDateStr := startOfDay.Format("02/01/06 15:04")
-	endDateStr := endOfDay.Format("02/01/06 15:04")
-
+func (r *BugDiscoveryTimelineRepo) buildLogActivityQueryWithPagination(domainNames []string, params domain_overview.LogActivityPaginationParams) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
 				"field": "pentester_name.keyword",
 			},
 		},
-		{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte":    startDateStr,
-					"lte":    endDateStr,
-					"format": "dd/MM/yy HH:mm",
-				},
-			},
-		},
+	}
+	if timeFilter := chartRangeFilter(params.Range); timeFilter != nil {
+		mustClauses = append(mustClauses, timeFilter)
 	}
 
 	// Filter domain
-	if params.Domain != "" && params.Domain != "all" {
+	if len(domainNames) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": params.Domain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": domainNames,
 			},
 		})
 	}
@@ -347,8 +316,9 @@ func (r *BugDiscoveryTimelineRepo) formatToIndonesianShort(dateStr string) strin
 		wibTime.Minute())
 }
 
-func (r *BugDiscoveryTimelineRepo) GetPentestersEffectiveness(ctx context.Context, domainName string, period int) ([]domain_overview.PentesterEffectiveness, error) {
+func (r *BugDiscoveryTimelineRepo) GetPentestersEffectiveness(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.PentesterEffectiveness, error) {
 	// Build query untuk mendapatkan data pentester dengan aktivitas terakhir
+	domainNames := scope.Domains
 
 	query := map[string]interface{}{
 		"size": 0,
@@ -390,15 +360,20 @@ func (r *BugDiscoveryTimelineRepo) GetPentestersEffectiveness(ctx context.Contex
 	}
 
 	// Add domain filter if specified
-	if domainName != "" && domainName != "all" {
+	if len(domainNames) > 0 {
 		mustQueries := query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"].([]map[string]interface{})
 		domainQuery := map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain": domainName,
+			"terms": map[string]interface{}{
+				"flag_domain": domainNames,
 			},
 		}
 		query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"] = append(mustQueries, domainQuery)
 	}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
+		mustQueries := query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"].([]map[string]interface{})
+		query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"] = append(mustQueries, timeFilter)
+	}
+	query = withDataScope(query, scope)
 
 	queryBytes, err := json.Marshal(query)
 	if err != nil {
@@ -509,29 +484,33 @@ func (r *BugDiscoveryTimelineRepo) GetPentestersEffectiveness(ctx context.Contex
 }
 
 // GetPentestersActivity implements domain.ProxyTrafficRepository.
-func (r *BugDiscoveryTimelineRepo) GetPentestersActivity(ctx context.Context, domainName string, period int) ([]domain_overview.PentesterActivity, error) {
-	query := r.buildPentesterActivityQuery(domainName, period)
+func (r *BugDiscoveryTimelineRepo) GetPentestersActivity(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.PentesterActivity, error) {
+	query := withDataScope(r.buildPentesterActivityQuery(scope.Domains, rng), scope)
 	response, err := r.executeQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute pentester activity query: %w", err)
 	}
-	return r.parsePentesterActivity(response, period)
+	return r.parsePentesterActivity(response, rng)
 }
 
 // Existing function - Chart 1
-func (r *BugDiscoveryTimelineRepo) GetVulnerabilityStats(ctx context.Context, days int, domainName, filter string) ([]domain_overview.VulnStat, error) {
-	query := r.buildVulnerabilityStatsQuery(days, domainName, filter)
+func (r *BugDiscoveryTimelineRepo) GetVulnerabilityStats(ctx context.Context, rng domain_overview.ChartRange, scope domain_overview.DataScope, filter string) ([]domain_overview.VulnStat, error) {
+	// timeline butuh awal range, tanpa itu extended_bounds akan dimulai dari epoch
+	if rng.From.IsZero() {
+		return nil, fmt.Errorf("vulnerability timeline requires a start time")
+	}
+	query := withDataScope(r.buildVulnerabilityStatsQuery(rng, scope.Domains, filter), scope)
 
 	response, err := r.executeQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
-	return r.parseVulnerabilityStats(response, days)
+	return r.parseVulnerabilityStats(response, rng)
 }
 
 // NEW: Chart 2 - Bug Severity Distribution
-func (r *BugDiscoveryTimelineRepo) GetBugSeverityDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.SeverityDistribution, error) {
-	query := r.buildSeverityDistributionQuery(domainName, period, status)
+func (r *BugDiscoveryTimelineRepo) GetBugSeverityDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.SeverityDistribution, error) {
+	query := withDataScope(r.buildSeverityDistributionQuery(scope.Domains, rng, status), scope)
 	response, err := r.executeQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute severity distribution query: %w", err)
@@ -540,8 +519,8 @@ func (r *BugDiscoveryTimelineRepo) GetBugSeverityDistribution(ctx context.Contex
 }
 
 // NEW: Chart 2 - Bug Status Distribution
-func (r *BugDiscoveryTimelineRepo) GetBugStatusDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.StatusDistribution, error) {
-	query := r.buildStatusDistributionQuery(domainName, period, status)
+func (r *BugDiscoveryTimelineRepo) GetBugStatusDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.StatusDistribution, error) {
+	query := withDataScope(r.buildStatusDistributionQuery(scope.Domains, rng, status), scope)
 
 	response, err := r.executeQuery(ctx, query)
 	if err != nil {
@@ -552,8 +531,8 @@ func (r *BugDiscoveryTimelineRepo) GetBugStatusDistribution(ctx context.Context,
 }
 
 // NEW: Chart 2 - Bug Validation Distribution
-func (r *BugDiscoveryTimelineRepo) GetBugValidationDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.ValidationDistribution, error) {
-	query := r.buildValidationDistributionQuery(domainName, period, status)
+func (r *BugDiscoveryTimelineRepo) GetBugValidationDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.ValidationDistribution, error) {
+	query := withDataScope(r.buildValidationDistributionQuery(scope.Domains, rng, status), scope)
 
 	response, err := r.executeQuery(ctx, query)
 	if err != nil {
@@ -564,8 +543,8 @@ func (r *BugDiscoveryTimelineRepo) GetBugValidationDistribution(ctx context.Cont
 }
 
 // NEW: Chart 3 - Host/Domain Bugs Exposure
-func (r *BugDiscoveryTimelineRepo) GetHostBugsExposure(ctx context.Context, domainName string, period int) ([]domain_overview.HostExposure, error) {
-	query := r.buildHostExposureQuery(domainName, period)
+func (r *BugDiscoveryTimelineRepo) GetHostBugsExposure(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.HostExposure, error) {
+	query := withDataScope(r.buildHostExposureQuery(scope.Domains, rng), scope)
 
 	response, err := r.executeQuery(ctx, query)
 	if err != nil {
@@ -576,8 +555,8 @@ func (r *BugDiscoveryTimelineRepo) GetHostBugsExposure(ctx context.Context, doma
 }
 
 // NEW: Chart 4 - Bug Type Frequency
-func (r *BugDiscoveryTimelineRepo) GetBugTypeFrequency(ctx context.Context, domainName string, period int) ([]domain_overview.BugTypeFrequency, error) {
-	query := r.buildBugTypeFrequencyQuery(domainName, period)
+func (r *BugDiscoveryTimelineRepo) GetBugTypeFrequency(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.BugTypeFrequency, error) {
+	query := withDataScope(r.buildBugTypeFrequencyQuery(scope.Domains, rng), scope)
 
 	response, err := r.executeQuery(ctx, query)
 	if err != nil {
@@ -589,7 +568,7 @@ func (r *BugDiscoveryTimelineRepo) GetBugTypeFrequency(ctx context.Context, doma
 
 // ========= QUERY BUILDERS =========
 
-func (r *BugDiscoveryTimelineRepo) buildSeverityDistributionQuery(flagDomain string, period int, status string) map[string]interface{} {
+func (r *BugDiscoveryTimelineRepo) buildSeverityDistributionQuery(flagDomains []string, rng domain_overview.ChartRange, status string) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -604,10 +583,10 @@ func (r *BugDiscoveryTimelineRepo) buildSeverityDistributionQuery(flagDomain str
 	}
 
 	// Add domain filter if provided
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -624,16 +603,7 @@ func (r *BugDiscoveryTimelineRepo) buildSeverityDistributionQuery(flagDomain str
 		mustClauses = append(mustClauses, statusFilter)
 	}
 
-	// Add time period filter if specified (period > 0 means filter, 0 or empty means all time)
-	if period > 0 {
-		timeFilter := map[string]interface{}{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte": fmt.Sprintf("now-%dd/d", period), // Added /d for start of day
-					"lte": "now/d",                          // Added /d for end of day
-				},
-			},
-		}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
 		mustClauses = append(mustClauses, timeFilter)
 	}
 
@@ -675,7 +645,7 @@ func (r *BugDiscoveryTimelineRepo) buildSeverityDistributionQuery(flagDomain str
 		},
 	}
 }
-func (r *BugDiscoveryTimelineRepo) buildStatusDistributionQuery(flagDomain string, period int, status string) map[string]interface{} {
+func (r *BugDiscoveryTimelineRepo) buildStatusDistributionQuery(flagDomains []string, rng domain_overview.ChartRange, status string) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -689,10 +659,10 @@ func (r *BugDiscoveryTimelineRepo) buildStatusDistributionQuery(flagDomain strin
 		},
 	}
 
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -706,16 +676,7 @@ func (r *BugDiscoveryTimelineRepo) buildStatusDistributionQuery(flagDomain strin
 		mustClauses = append(mustClauses, statusFilter)
 	}
 
-	// Add time period filter if specified (period > 0 means filter, 0 means all time)
-	if period > 0 {
-		timeFilter := map[string]interface{}{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte": fmt.Sprintf("now-%dd", period),
-					"lte": "now",
-				},
-			},
-		}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
 		mustClauses = append(mustClauses, timeFilter)
 	}
 
@@ -751,7 +712,7 @@ func (r *BugDiscoveryTimelineRepo) buildStatusDistributionQuery(flagDomain strin
 	}
 }
 
-func (r *BugDiscoveryTimelineRepo) buildValidationDistributionQuery(flagDomain string, period int, status string) map[string]interface{} {
+func (r *BugDiscoveryTimelineRepo) buildValidationDistributionQuery(flagDomains []string, rng domain_overview.ChartRange, status string) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -765,10 +726,10 @@ func (r *BugDiscoveryTimelineRepo) buildValidationDistributionQuery(flagDomain s
 		},
 	}
 
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -783,16 +744,7 @@ func (r *BugDiscoveryTimelineRepo) buildValidationDistributionQuery(flagDomain s
 		mustClauses = append(mustClauses, statusFilter)
 	}
 
-	// Add time period filter if specified (period > 0 means filter, 0 means all time)
-	if period > 0 {
-		timeFilter := map[string]interface{}{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte": fmt.Sprintf("now-%dd", period),
-					"lte": "now",
-				},
-			},
-		}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
 		mustClauses = append(mustClauses, timeFilter)
 	}
 
@@ -828,7 +780,7 @@ func (r *BugDiscoveryTimelineRepo) buildValidationDistributionQuery(flagDomain s
 	}
 }
 
-func (r *BugDiscoveryTimelineRepo) buildHostExposureQuery(flagDomain string, period int) map[string]interface{} {
+func (r *BugDiscoveryTimelineRepo) buildHostExposureQuery(flagDomains []string, rng domain_overview.ChartRange) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -842,23 +794,14 @@ func (r *BugDiscoveryTimelineRepo) buildHostExposureQuery(flagDomain string, per
 		},
 	}
 
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
-	// Add time period filter if specified (period > 0 means filter, 0 means all time)
-	if period > 0 {
-		timeFilter := map[string]interface{}{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte": fmt.Sprintf("now-%dd", period),
-					"lte": "now",
-				},
-			},
-		}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
 		mustClauses = append(mustClauses, timeFilter)
 	}
 
@@ -889,7 +832,7 @@ func (r *BugDiscoveryTimelineRepo) buildHostExposureQuery(flagDomain string, per
 }
 
 // Simplified working hours calculation with session-based approach
-func (r *BugDiscoveryTimelineRepo) buildPentesterActivityQuery(flagDomain string, period int) map[string]interface{} {
+func (r *BugDiscoveryTimelineRepo) buildPentesterActivityQuery(flagDomains []string, rng domain_overview.ChartRange) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -899,24 +842,16 @@ func (r *BugDiscoveryTimelineRepo) buildPentesterActivityQuery(flagDomain string
 	}
 
 	// Add domain filter if provided
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
 
-	// Add time period filter if specified (0 = all time, 7 = last 7 days, 30 = last 30 days)
-	if period > 0 {
-		mustClauses = append(mustClauses, map[string]interface{}{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte": fmt.Sprintf("now-%dd/d", period),
-					"lte": "now/d",
-				},
-			},
-		})
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
+		mustClauses = append(mustClauses, timeFilter)
 	}
 
 	mustNotClauses := []map[string]interface{}{
@@ -924,17 +859,6 @@ func (r *BugDiscoveryTimelineRepo) buildPentesterActivityQuery(flagDomain string
 		{"term": map[string]interface{}{"pentester_name.keyword": ""}},
 	}
 
-	// Determine aggregation name based on period
-	var workingHoursAggName string
-	switch period {
-	case 7:
-		workingHoursAggName = "weekly_working_hours"
-	case 30:
-		workingHoursAggName = "monthly_working_hours"
-	default: // 0 or any other value = all time
-		workingHoursAggName = "total_working_hours"
-	}
-
 	// Use Indonesia timezone
 	indonesiaTimezone := "Asia/Jakarta"
 
@@ -959,7 +883,12 @@ func (r *BugDiscoveryTimelineRepo) buildPentesterActivityQuery(flagDomain string
 						},
 					},
 
-					workingHoursAggName: map[string]interface{}{
+					// awal minggu pertama jika range tidak punya from (semua waktu)
+					"first_activity": map[string]interface{}{
+						"min": map[string]interface{}{"field": "time"},
+					},
+
+					"total_working_hours": map[string]interface{}{
 						"scripted_metric": map[string]interface{}{
 							"init_script": "state.dailyActivities = new HashMap()",
 
@@ -1176,7 +1105,17 @@ func formatWorkingHours(totalMinutes float64) string {
 	return fmt.Sprintf("%d hrs %d mins", wholeHours, minutes)
 }
 
-func (r *BugDiscoveryTimelineRepo) parsePentesterActivity(response *domain.SearchResponse, period int) ([]domain_overview.PentesterActivity, error) {
+// weeksInRange jumlah minggu dari start sampai to, minimal satu supaya range pendek
+// tidak menghasilkan rata-rata per minggu yang lebih besar dari totalnya
+func weeksInRange(start, to time.Time) float64 {
+	weeks := to.Sub(start).Hours() / (24 * 7)
+	if weeks < 1 {
+		return 1
+	}
+	return weeks
+}
+
+func (r *BugDiscoveryTimelineRepo) parsePentesterActivity(response *domain.SearchResponse, rng domain_overview.ChartRange) ([]domain_overview.PentesterActivity, error) {
 	var result []domain_overview.PentesterActivity
 	if response.Aggregations == nil {
 		return result, nil
@@ -1199,17 +1138,6 @@ func (r *BugDiscoveryTimelineRepo) parsePentesterActivity(response *domain.Searc
 
 	colors := []string{"#10B981", "#3B82F6", "#F59E0B", "#EF4444", "#8B5CF6", "#06B6D4", "#84CC16", "#F97316"}
 
-	// Tentukan key aggregation sesuai period
-	var workingAggKey string
-	switch period {
-	case 7:
-		workingAggKey = "weekly_working_hours"
-	case 30:
-		workingAggKey = "monthly_working_hours"
-	default:
-		workingAggKey = "total_working_hours"
-	}
-
 	for i, bucket := range buckets {
 		b, ok := bucket.(map[string]interface{})
 		if !ok {
@@ -1237,12 +1165,24 @@ func (r *BugDiscoveryTimelineRepo) parsePentesterActivity(response *domain.Searc
 			}
 		}
 
-		weeklyMinutes := 0.0
-		if workingAgg, ok := b[workingAggKey].(map[string]interface{}); ok {
+		// total jam kerja selama range chart, dibagi jumlah minggu di range. Tanpa from,
+		// minggu dihitung dari aktivitas pertama pentester
+		rangeMinutes := 0.0
+		if workingAgg, ok := b["total_working_hours"].(map[string]interface{}); ok {
 			if val, ok := workingAgg["value"].(float64); ok {
-				weeklyMinutes = val
+				rangeMinutes = val
+			}
+		}
+		start := rng.From
+		if start.IsZero() {
+			start = rng.To
+			if firstAgg, ok := b["first_activity"].(map[string]interface{}); ok {
+				if val, ok := firstAgg["value"].(float64); ok {
+					start = time.UnixMilli(int64(val))
+				}
 			}
 		}
+		perWeekMinutes := rangeMinutes / weeksInRange(start, rng.To)
 
 		color := colors[i%len(colors)]
 
@@ -1251,7 +1191,7 @@ func (r *BugDiscoveryTimelineRepo) parsePentesterActivity(response *domain.Searc
 			Value:               totalBugs,
 			Color:               color,
 			PerDayWorkingHours:  formatWorkingHours(avgDailyMinutes),
-			PerWeekWorkingHours: formatWorkingHours(weeklyMinutes),
+			PerWeekWorkingHours: formatWorkingHours(perWeekMinutes),
 			UniqueDays:          uniqueDays,
 		})
 	}
@@ -1259,7 +1199,7 @@ func (r *BugDiscoveryTimelineRepo) parsePentesterActivity(response *domain.Searc
 	return result, nil
 }
 
-func (r *BugDiscoveryTimelineRepo) buildBugTypeFrequencyQuery(flagDomain string, period int) map[string]interface{} {
+func (r *BugDiscoveryTimelineRepo) buildBugTypeFrequencyQuery(flagDomains []string, rng domain_overview.ChartRange) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -1273,22 +1213,14 @@ func (r *BugDiscoveryTimelineRepo) buildBugTypeFrequencyQuery(flagDomain string,
 		},
 	}
 
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
-	if period > 0 {
-		timeFilter := map[string]interface{}{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte": fmt.Sprintf("now-%dd", period),
-					"lte": "now",
-				},
-			},
-		}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
 		mustClauses = append(mustClauses, timeFilter)
 	}
 
@@ -1319,7 +1251,7 @@ func (r *BugDiscoveryTimelineRepo) buildBugTypeFrequencyQuery(flagDomain string,
 	}
 }
 
-func (r *BugDiscoveryTimelineRepo) buildVulnerabilityStatsQuery(period int, flagDomain, filter string) map[string]interface{} {
+func (r *BugDiscoveryTimelineRepo) buildVulnerabilityStatsQuery(rng domain_overview.ChartRange, flagDomains []string, filter string) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -1334,10 +1266,10 @@ func (r *BugDiscoveryTimelineRepo) buildVulnerabilityStatsQuery(period int, flag
 	}
 
 	// Add domain filter if specified
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -1350,16 +1282,7 @@ func (r *BugDiscoveryTimelineRepo) buildVulnerabilityStatsQuery(period int, flag
 		})
 	}
 
-	// Add time range filter
-	if period > 0 {
-		timeFilter := map[string]interface{}{
-			"range": map[string]interface{}{
-				"time": map[string]interface{}{
-					"gte": fmt.Sprintf("now-%dd/d", period), // /d untuk start of day
-					"lte": "now/d",
-				},
-			},
-		}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
 		mustClauses = append(mustClauses, timeFilter)
 	}
 
@@ -1386,18 +1309,7 @@ func (r *BugDiscoveryTimelineRepo) buildVulnerabilityStatsQuery(period int, flag
 					},
 				},
 				"aggs": map[string]interface{}{
-					"daily_counts": map[string]interface{}{
-						"date_histogram": map[string]interface{}{
-							"field":             "time",
-							"calendar_interval": "1d", // Changed from "interval": "day"
-							"format":            "yyyy-MM-dd",
-							"min_doc_count":     0,
-							"extended_bounds": map[string]interface{}{
-								"min": fmt.Sprintf("now-%dd/d", period),
-								"max": "now/d",
-							},
-						},
-					},
+					"timeline": chartDateHistogram(rng),
 				},
 			},
 		},
@@ -1646,7 +1558,7 @@ func (r *BugDiscoveryTimelineRepo) executeQuery(ctx context.Context, query map[s
 	return &response, nil
 }
 
-func (r *BugDiscoveryTimelineRepo) parseVulnerabilityStats(response *domain.SearchResponse, days int) ([]domain_overview.VulnStat, error) {
+func (r *BugDiscoveryTimelineRepo) parseVulnerabilityStats(response *domain.SearchResponse, rng domain_overview.ChartRange) ([]domain_overview.VulnStat, error) {
 	var result []domain_overview.VulnStat
 
 	// Check if the aggregation exists
@@ -1667,17 +1579,9 @@ func (r *BugDiscoveryTimelineRepo) parseVulnerabilityStats(response *domain.Sear
 		return result, fmt.Errorf("buckets not found in aggregation")
 	}
 
-	// Create date mapping for timeline
-	endDate := time.Now()
-	startDate := endDate.AddDate(0, 0, -days+1) // Adjust to include today
-	dateToIndexMap := make(map[string]int)
-
-	current := startDate
-	for i := 0; i < days; i++ {
-		dateKey := current.Format("2006-01-02") // Format matches ES response (yyyy-MM-dd)
-		dateToIndexMap[dateKey] = i
-		current = current.AddDate(0, 0, 1)
-	}
+	// Posisi setiap bucket di sumbu x, sama dengan urutan rng.Labels()
+	bucketIndex := chartBucketIndex(rng)
+	size := len(bucketIndex)
 
 	// Process each vulnerability type
 	for _, bucket := range buckets {
@@ -1692,118 +1596,77 @@ func (r *BugDiscoveryTimelineRepo) parseVulnerabilityStats(response *domain.Sear
 			continue
 		}
 
-		// Initialize daily data array with zeros
-		dailyData := make([]int64, days)
+		// Bucket tanpa temuan tetap bernilai 0
+		data := make([]int64, size)
 
-		// Get daily counts
-		dailyCounts, ok := bucketData["daily_counts"].(map[string]interface{})
+		timeline, ok := bucketData["timeline"].(map[string]interface{})
 		if !ok {
 			continue
 		}
 
-		dailyBuckets, ok := dailyCounts["buckets"].([]interface{})
+		timeBuckets, ok := timeline["buckets"].([]interface{})
 		if !ok {
 			continue
 		}
 
-		// Process daily buckets
-		for _, dailyBucket := range dailyBuckets {
-			dailyBucketData, ok := dailyBucket.(map[string]interface{})
+		for _, timeBucket := range timeBuckets {
+			timeBucketData, ok := timeBucket.(map[string]interface{})
 			if !ok {
 				continue
 			}
 
-			// Get date and count
-			dateStr, ok := dailyBucketData["key_as_string"].(string)
+			// key berisi epoch millis awal bucket
+			key, ok := timeBucketData["key"].(float64)
 			if !ok {
 				continue
 			}
 
-			count, ok := dailyBucketData["doc_count"].(float64)
+			count, ok := timeBucketData["doc_count"].(float64)
 			if !ok {
 				continue
 			}
 
-			// Map to timeline index
-			if dayIndex, exists := dateToIndexMap[dateStr]; exists {
-				if dayIndex >= 0 && dayIndex < days {
-					dailyData[dayIndex] = int64(count)
-				}
+			if idx, exists := bucketIndex[int64(key)]; exists {
+				data[idx] = int64(count)
 			}
 		}
 
-		// Sample data to get exactly 7 data points
-		sampledData := r.sampleDataTo7Days(dailyData, days)
-
-		// Add to result
 		result = append(result, domain_overview.VulnStat{
 			Name: vulnType,
-			Data: sampledData, // Now contains 7 sampled data points
+			Data: data,
 		})
 	}
 
 	return result, nil
 }
 
-// Helper function to sample data into exactly 7 data points
-func (r *BugDiscoveryTimelineRepo) sampleDataTo7Days(dailyData []int64, originalDays int) []int64 {
-	targetDays := 7
-	sampledData := make([]int64, targetDays)
-
-	if originalDays <= targetDays {
-		// If we have 7 or fewer days, just pad with zeros if needed
-		copy(sampledData, dailyData)
-		return sampledData
-	}
-
-	// Group consecutive days and sum them
-	groupSize := originalDays / targetDays // Base group size
-	remainder := originalDays % targetDays // Extra days to distribute
-
-	startIndex := 0
-	for i := 0; i < targetDays; i++ {
-		// Calculate how many days in this group
-		daysInGroup := groupSize
-		if i < remainder {
-			daysInGroup++ // Add extra day to first 'remainder' groups
-		}
-
-		// Sum all days in this group
-		sum := int64(0)
-		for j := 0; j < daysInGroup && startIndex < originalDays; j++ {
-			sum += dailyData[startIndex]
-			startIndex++
-		}
-
-		sampledData[i] = sum
-	}
-
-	return sampledData
-}
 func (r *BugDiscoveryTimelineRepo) GetTotalFindingsWithTrend(
 	ctx context.Context,
-	domainName string,
+	scope domain_overview.DataScope,
+	rng domain_overview.ChartRange,
 ) (*domain_overview.ResponseTotalFindings, error) {
-	now := time.Now()
+	domainNames := scope.Domains
+	// trend membandingkan 7 hari terakhir range dengan 7 hari sebelumnya
+	now := rng.To
 	weekAgo := now.AddDate(0, 0, -7)
 	twoWeeksAgo := now.AddDate(0, 0, -14)
 
-	// === Query all time (untuk total) ===
-	allTimeQuery := buildAllTimeFindingsQuery(domainName)
+	// === Query seluruh range (untuk total) ===
+	allTimeQuery := withDataScope(buildAllTimeFindingsQuery(domainNames, rng), scope)
 	allTimeData, err := r.executeFindingsQuery(ctx, allTimeQuery, "all_time")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching all time data: %w", err)
 	}
 
 	// === Query minggu ini ===
-	currentWeekQuery := buildFindingsQuery(domainName, weekAgo, now)
+	currentWeekQuery := withDataScope(buildFindingsQuery(domainNames, weekAgo, now), scope)
 	currentWeekData, err := r.executeFindingsQuery(ctx, currentWeekQuery, "current_week")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching current week data: %w", err)
 	}
 
 	// === Query minggu lalu ===
-	lastWeekQuery := buildFindingsQuery(domainName, twoWeeksAgo, weekAgo)
+	lastWeekQuery := withDataScope(buildFindingsQuery(domainNames, twoWeeksAgo, weekAgo), scope)
 	lastWeekData, err := r.executeFindingsQuery(ctx, lastWeekQuery, "last_week")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching last week data: %w", err)
@@ -1824,8 +1687,8 @@ func (r *BugDiscoveryTimelineRepo) GetTotalFindingsWithTrend(
 	}, nil
 }
 
-func buildAllTimeFindingsQuery(domainName string) map[string]interface{} {
-	return map[string]interface{}{
+func buildAllTimeFindingsQuery(domainNames []string, rng domain_overview.ChartRange) map[string]interface{} {
+	query := map[string]interface{}{
 		"size": 0,
 		"query": map[string]interface{}{
 			"bool": map[string]interface{}{
@@ -1836,8 +1699,8 @@ func buildAllTimeFindingsQuery(domainName string) map[string]interface{} {
 						},
 					},
 					map[string]interface{}{
-						"term": map[string]interface{}{
-							"flag_domain.keyword": domainName,
+						"terms": map[string]interface{}{
+							"flag_domain.keyword": domainNames,
 						},
 					},
 				},
@@ -1853,10 +1716,15 @@ func buildAllTimeFindingsQuery(domainName string) map[string]interface{} {
 			},
 		},
 	}
+	if timeFilter := chartRangeFilter(rng); timeFilter != nil {
+		boolQuery := query["query"].(map[string]interface{})["bool"].(map[string]interface{})
+		boolQuery["must"] = append(boolQuery["must"].([]interface{}), timeFilter)
+	}
+	return query
 }
 
 // buildFindingsQuery membangun Elasticsearch query dengan perbaikan
-func buildFindingsQuery(domainName string, startTime, endTime time.Time) map[string]interface{} {
+func buildFindingsQuery(domainNames []string, startTime, endTime time.Time) map[string]interface{} {
 	// Gunakan format yang sama persis dengan data
 	startTimeStr := startTime.Format("02/01/06 15:04")
 	endTimeStr := endTime.Format("02/01/06 15:04")
@@ -1881,8 +1749,8 @@ func buildFindingsQuery(domainName string, startTime, endTime time.Time) map[str
 						},
 					},
 					map[string]interface{}{
-						"term": map[string]interface{}{
-							"flag_domain.keyword": domainName,
+						"terms": map[string]interface{}{
+							"flag_domain.keyword": domainNames,
 						},
 					},
 				},
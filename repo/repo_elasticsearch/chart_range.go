@@ -0,0 +1,51 @@
+package repo_elasticsearch
+
+import (
+	domain_overview "xops-admin/domain/user/overview"
+)
+
+// chartRangeFilter filter waktu chart. Range tanpa from (semua waktu) tetap dibatasi to,
+// nil hanya jika range kosong sama sekali
+func chartRangeFilter(rng domain_overview.ChartRange) map[string]interface{} {
+	if rng.From.IsZero() && rng.To.IsZero() {
+		return nil
+	}
+	bounds := map[string]interface{}{
+		"lt":     rng.To.UnixMilli(),
+		"format": "epoch_millis",
+	}
+	if !rng.From.IsZero() {
+		bounds["gte"] = rng.From.UnixMilli()
+	}
+	return map[string]interface{}{
+		"range": map[string]interface{}{
+			"time": bounds,
+		},
+	}
+}
+
+// chartDateHistogram bucket kalender sesuai interval. extended_bounds membuat bucket tanpa
+// dokumen tetap dikembalikan dengan doc_count 0, sehingga jumlah bucket selalu sama dengan rng.Buckets().
+func chartDateHistogram(rng domain_overview.ChartRange) map[string]interface{} {
+	return map[string]interface{}{
+		"date_histogram": map[string]interface{}{
+			"field":             "time",
+			"calendar_interval": rng.Interval,
+			"time_zone":         domain_overview.ChartTimeZone,
+			"min_doc_count":     0,
+			"extended_bounds": map[string]interface{}{
+				"min": rng.From.UnixMilli(),
+				"max": rng.To.UnixMilli() - 1,
+			},
+		},
+	}
+}
+
+// chartBucketIndex posisi bucket berdasarkan key (epoch millis awal bucket) dari date_histogram
+func chartBucketIndex(rng domain_overview.ChartRange) map[int64]int {
+	index := make(map[int64]int)
+	for i, b := range rng.Buckets() {
+		index[b.UnixMilli()] = i
+	}
+	return index
+}
@@ -0,0 +1,104 @@
+package repo_elasticsearch
+
+import (
+	"strings"
+
+	domain_overview "xops-admin/domain/user/overview"
+	"xops-admin/model"
+)
+
+// karakter regexp Lucene yang harus di-escape agar awalan path dicocokkan apa adanya
+var luceneRegexpEscaper = strings.NewReplacer(
+	`\`, `\\`, `.`, `\.`, `?`, `\?`, `+`, `\+`, `*`, `\*`, `|`, `\|`, `{`, `\{`, `}`, `\}`,
+	`[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`, `"`, `\"`, `#`, `\#`, `@`, `\@`, `&`, `\&`,
+	`<`, `\<`, `>`, `\>`, `~`, `\~`,
+)
+
+// withDataScope membungkus query sehingga hanya dokumen di dalam jendela kontrak client
+// yang cocok dan traffic out-of-scope menurut rule client dibuang lewat must_not.
+// Scope tanpa From / To tidak dibatasi waktu.
+func withDataScope(query map[string]interface{}, scope domain_overview.DataScope) map[string]interface{} {
+	var filters []map[string]interface{}
+	if !scope.From.IsZero() || !scope.To.IsZero() {
+		window := map[string]interface{}{
+			"format": "epoch_millis",
+		}
+		if !scope.From.IsZero() {
+			window["gte"] = scope.From.UnixMilli()
+		}
+		if !scope.To.IsZero() {
+			window["lt"] = scope.To.UnixMilli()
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"time": window}})
+	}
+	outOfScope := outOfScopeClauses(scope.Rules)
+	if len(filters) == 0 && len(outOfScope) == 0 {
+		return query
+	}
+
+	inner, ok := query["query"]
+	if !ok {
+		inner = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	wrapped := map[string]interface{}{
+		"must": []interface{}{inner},
+	}
+	if len(filters) > 0 {
+		wrapped["filter"] = filters
+	}
+	if len(outOfScope) > 0 {
+		wrapped["must_not"] = outOfScope
+	}
+	query["query"] = map[string]interface{}{"bool": wrapped}
+	return query
+}
+
+// outOfScopeClauses clause yang cocok dengan traffic out-of-scope: setiap rule exclude, dan
+// jika ada rule include, traffic yang tidak cocok dengan rule include mana pun
+func outOfScopeClauses(rules []domain_overview.ScopeRule) []map[string]interface{} {
+	var clauses, includes []map[string]interface{}
+	for _, rule := range rules {
+		clause := scopeRuleClause(rule)
+		if clause == nil {
+			continue
+		}
+		if rule.Action == model.ScopeRuleInclude {
+			includes = append(includes, clause)
+		} else {
+			clauses = append(clauses, clause)
+		}
+	}
+	if len(includes) > 0 {
+		clauses = append(clauses, map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter":   []map[string]interface{}{{"exists": map[string]interface{}{"field": "host"}}},
+				"must_not": includes,
+			},
+		})
+	}
+	return clauses
+}
+
+func scopeRuleClause(rule domain_overview.ScopeRule) map[string]interface{} {
+	switch rule.Type {
+	case model.ScopeRuleHost:
+		return map[string]interface{}{
+			"wildcard": map[string]interface{}{
+				"host.keyword": map[string]interface{}{"value": rule.Pattern, "case_insensitive": true},
+			},
+		}
+	case model.ScopeRulePath:
+		return map[string]interface{}{
+			"regexp": map[string]interface{}{
+				"url.keyword": map[string]interface{}{"value": "https?://[^/]+" + luceneRegexpEscaper.Replace(rule.Pattern) + ".*"},
+			},
+		}
+	case model.ScopeRuleRegex:
+		return map[string]interface{}{
+			"regexp": map[string]interface{}{
+				"url.keyword": map[string]interface{}{"value": rule.Pattern},
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repo_elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"xops-admin/domain"
+	domain_overview "xops-admin/domain/user/overview"
+)
+
+const (
+	outOfScopeHostLimit      = 50
+	outOfScopePentesterLimit = 20
+)
+
+// GetOutOfScopeActivity traffic di domain client yang cocok dengan rule exclude (atau tidak
+// cocok dengan rule include), dikelompokkan per host lalu per pentester
+func (s *SecurityCheklistRepo) GetOutOfScopeActivity(ctx context.Context, scope domain_overview.DataScope) (*domain_overview.OutOfScopeActivityResponse, error) {
+	result := &domain_overview.OutOfScopeActivityResponse{Hosts: []domain_overview.OutOfScopeHost{}}
+	outOfScope := outOfScopeClauses(scope.Rules)
+	if len(outOfScope) == 0 {
+		return result, nil
+	}
+
+	query := map[string]interface{}{
+		"size":             0,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"terms": map[string]interface{}{"flag_domain.keyword": scope.Domains}},
+				},
+				"should":               outOfScope,
+				"minimum_should_match": 1,
+			},
+		},
+		"aggs": map[string]interface{}{
+			"hosts": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "host.keyword",
+					"size":  outOfScopeHostLimit,
+				},
+				"aggs": map[string]interface{}{
+					"pentesters": map[string]interface{}{
+						"terms": map[string]interface{}{
+							"field": "pentester_name.keyword",
+							"size":  outOfScopePentesterLimit,
+						},
+						"aggs": map[string]interface{}{
+							"last_seen": map[string]interface{}{
+								"max": map[string]interface{}{"field": "time"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	// rule sudah dipakai sebagai should, yang dibatasi di sini hanya jendela waktunya
+	window := scope
+	window.Rules = nil
+	query = withDataScope(query, window)
+
+	response, err := s.executeQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute out-of-scope query: %w", err)
+	}
+	// total diambil dari hits, bukan jumlah bucket, supaya host di luar daftar teratas tetap terhitung
+	result.Total = response.Hits.Total.Value
+	result.Hosts = parseOutOfScopeActivity(response)
+	return result, nil
+}
+
+func parseOutOfScopeActivity(response *domain.SearchResponse) []domain_overview.OutOfScopeHost {
+	hosts := []domain_overview.OutOfScopeHost{}
+	aggData, ok := response.Aggregations["hosts"].(map[string]interface{})
+	if !ok {
+		return hosts
+	}
+	buckets, _ := aggData["buckets"].([]interface{})
+	for _, bucket := range buckets {
+		hostBucket, ok := bucket.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hostName, _ := hostBucket["key"].(string)
+		hostCount, _ := hostBucket["doc_count"].(float64)
+		host := domain_overview.OutOfScopeHost{
+			Host:       hostName,
+			Total:      int64(hostCount),
+			Pentesters: []domain_overview.OutOfScopePentester{},
+		}
+
+		pentesterAgg, _ := hostBucket["pentesters"].(map[string]interface{})
+		pentesterBuckets, _ := pentesterAgg["buckets"].([]interface{})
+		for _, pb := range pentesterBuckets {
+			pentesterBucket, ok := pb.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := pentesterBucket["key"].(string)
+			count, _ := pentesterBucket["doc_count"].(float64)
+			pentester := domain_overview.OutOfScopePentester{
+				Name:  name,
+				Total: int64(count),
+			}
+			if lastSeen, ok := pentesterBucket["last_seen"].(map[string]interface{}); ok {
+				if value, ok := lastSeen["value"].(float64); ok {
+					at := time.UnixMilli(int64(value))
+					pentester.LastSeen = &at
+				}
+			}
+			host.Pentesters = append(host.Pentesters, pentester)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
@@ -22,8 +22,8 @@ type SecurityCheklistRepo struct {
 }
 
 // GetTotalFindings implements domain_overview.SecurityChecklistRepository.
-func (s *SecurityCheklistRepo) GetTotalFindings(ctx context.Context, domain_overviewName string) (*[]domain_overview.SeverityCountTotalFindings, error) {
-	query := s.buildTotalFindingsQuery(domain_overviewName)
+func (s *SecurityCheklistRepo) GetTotalFindings(ctx context.Context, scope domain_overview.DataScope) (*[]domain_overview.SeverityCountTotalFindings, error) {
+	query := withDataScope(s.buildTotalFindingsQuery(scope.Domains), scope)
 	response, err := s.executeQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute total findings query: %w", err)
@@ -32,8 +32,8 @@ func (s *SecurityCheklistRepo) GetTotalFindings(ctx context.Context, domain_over
 }
 
 // GetTotalBugStatusList with pagination and sorting
-func (s *SecurityCheklistRepo) GetTotalBugStatusList(ctx context.Context, domain_overviewName string) (*domain_overview.ResponseTotalBugStatusItem, error) {
-	query := s.buildTotalBugStatusQuery(domain_overviewName)
+func (s *SecurityCheklistRepo) GetTotalBugStatusList(ctx context.Context, scope domain_overview.DataScope) (*domain_overview.ResponseTotalBugStatusItem, error) {
+	query := withDataScope(s.buildTotalBugStatusQuery(scope.Domains), scope)
 	response, err := s.executeQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute total bug status query: %w", err)
@@ -42,8 +42,8 @@ func (s *SecurityCheklistRepo) GetTotalBugStatusList(ctx context.Context, domain
 }
 
 // GetSecurityChecklistTable with pagination and sorting
-func (s *SecurityCheklistRepo) GetSecurityChecklistTable(ctx context.Context, domain_overviewName string, params domain_overview.PaginationParams) (*domain_overview.SecurityChecklistTableResponse, error) {
-	query := s.buildSecurityChecklistTableQuery(domain_overviewName, params)
+func (s *SecurityCheklistRepo) GetSecurityChecklistTable(ctx context.Context, scope domain_overview.DataScope, params domain_overview.PaginationParams) (*domain_overview.SecurityChecklistTableResponse, error) {
+	query := withDataScope(s.buildSecurityChecklistTableQuery(scope.Domains, params), scope)
 	response, err := s.executeQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute security checklist table query: %w", err)
@@ -51,7 +51,7 @@ func (s *SecurityCheklistRepo) GetSecurityChecklistTable(ctx context.Context, do
 	return s.parseSecurityChecklistTable(response, params)
 }
 
-func (s *SecurityCheklistRepo) buildTotalFindingsQuery(flagDomain string) map[string]interface{} {
+func (s *SecurityCheklistRepo) buildTotalFindingsQuery(flagDomains []string) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -66,10 +66,10 @@ func (s *SecurityCheklistRepo) buildTotalFindingsQuery(flagDomain string) map[st
 	}
 
 	// Add domain_overview filter if provided
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -99,7 +99,7 @@ func (s *SecurityCheklistRepo) buildTotalFindingsQuery(flagDomain string) map[st
 	}
 }
 
-func (s *SecurityCheklistRepo) buildSecurityChecklistTableQuery(flagDomain string, params domain_overview.PaginationParams) map[string]interface{} {
+func (s *SecurityCheklistRepo) buildSecurityChecklistTableQuery(flagDomains []string, params domain_overview.PaginationParams) map[string]interface{} {
 	mustClauses := []map[string]interface{}{}
 
 	if params.Search != "" {
@@ -155,10 +155,10 @@ func (s *SecurityCheklistRepo) buildSecurityChecklistTableQuery(flagDomain strin
 		mustClauses = append(mustClauses, searchQuery)
 	}
 
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -502,7 +502,7 @@ func (s *SecurityCheklistRepo) parseTotalFindings(response *domain.SearchRespons
 	return &results, nil
 }
 
-func (s *SecurityCheklistRepo) buildTotalBugStatusQuery(flagDomain string) map[string]interface{} {
+func (s *SecurityCheklistRepo) buildTotalBugStatusQuery(flagDomains []string) map[string]interface{} {
 	mustClauses := []map[string]interface{}{
 		{
 			"exists": map[string]interface{}{
@@ -517,10 +517,10 @@ func (s *SecurityCheklistRepo) buildTotalBugStatusQuery(flagDomain string) map[s
 	}
 
 	// Add domain filter if provided
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -685,15 +685,28 @@ func (s *SecurityCheklistRepo) parseSecurityChecklistDetail(response *domain.Sea
 }
 
 // Alternative method if you want to search by document ID in Elasticsearch
-func (s *SecurityCheklistRepo) GetSecurityChecklistDetailByESID(ctx context.Context, esID string) (*domain_overview.DetailIdSecurityChecklistItem, error) {
+func (s *SecurityCheklistRepo) GetSecurityChecklistDetailByESID(ctx context.Context, esID string, scope domain_overview.DataScope) (*domain_overview.DetailIdSecurityChecklistItem, error) {
+	// dokumen milik domain lain dianggap tidak ada
 	query := map[string]interface{}{
 		"size": 1,
 		"query": map[string]interface{}{
-			"ids": map[string]interface{}{
-				"values": []string{esID},
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{
+						"ids": map[string]interface{}{
+							"values": []string{esID},
+						},
+					},
+					{
+						"terms": map[string]interface{}{
+							"flag_domain.keyword": scope.Domains,
+						},
+					},
+				},
 			},
 		},
 	}
+	query = withDataScope(query, scope)
 
 	response, err := s.executeQuery(ctx, query)
 	if err != nil {
@@ -703,7 +716,7 @@ func (s *SecurityCheklistRepo) GetSecurityChecklistDetailByESID(ctx context.Cont
 	return s.parseSecurityChecklistDetail(response)
 }
 
-func (s *SecurityCheklistRepo) GetURLList(ctx context.Context, flagDomain string, params domain_overview.URLListParams) (*domain_overview.URLListResponse, error) {
+func (s *SecurityCheklistRepo) GetURLList(ctx context.Context, scope domain_overview.DataScope, params domain_overview.URLListParams) (*domain_overview.URLListResponse, error) {
 	// Set default page if not provided
 	if params.Page < 1 {
 		params.Page = 1
@@ -714,7 +727,7 @@ func (s *SecurityCheklistRepo) GetURLList(ctx context.Context, flagDomain string
 	}
 
 	// Build the aggregation query
-	query := s.buildURLListQuery(flagDomain, params)
+	query := withDataScope(s.buildURLListQuery(scope.Domains, params), scope)
 
 	// Execute the query
 	response, err := s.executeQuery(ctx, query)
@@ -727,16 +740,16 @@ func (s *SecurityCheklistRepo) GetURLList(ctx context.Context, flagDomain string
 }
 
 // buildURLListQuery creates the Elasticsearch query for getting URL list
-func (s *SecurityCheklistRepo) buildURLListQuery(flagDomain string, params domain_overview.URLListParams) map[string]interface{} {
+func (s *SecurityCheklistRepo) buildURLListQuery(flagDomains []string, params domain_overview.URLListParams) map[string]interface{} {
 	const pageSize = 5 // Fixed page size for infinite scroll
 
 	mustClauses := []map[string]interface{}{}
 
 	// Filter by flag domain if provided
-	if flagDomain != "" {
+	if len(flagDomains) > 0 {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": flagDomain,
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": flagDomains,
 			},
 		})
 	}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type ApiKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewApiKeyRepo(db *gorm.DB) domain.ApiKeyRepository {
+	return &ApiKeyRepo{
+		db: db,
+	}
+}
+
+func (r *ApiKeyRepo) CreateApiKey(apiKey *model.ApiKey) error {
+	if err := r.db.Create(apiKey).Error; err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (r *ApiKeyRepo) FindApiKeyByHash(hash string) (*model.ApiKey, error) {
+	var apiKey model.ApiKey
+	if err := r.db.First(&apiKey, "key_hash = ?", hash); err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &apiKey, nil
+}
+
+func (r *ApiKeyRepo) FindApiKeyByID(id string) (*model.ApiKey, error) {
+	var apiKey model.ApiKey
+	if err := r.db.First(&apiKey, "id = ?", id); err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &apiKey, nil
+}
+
+func (r *ApiKeyRepo) FindApiKeysByUserID(idUser string) ([]model.ApiKey, error) {
+	var apiKeys []model.ApiKey
+	if err := r.db.Where("id_user = ?", idUser).Order("created_at DESC").Find(&apiKeys).Error; err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return apiKeys, nil
+}
+
+func (r *ApiKeyRepo) RevokeApiKey(id string) error {
+	now := time.Now()
+	if err := r.db.Model(&model.ApiKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", &now).Error; err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+// RevokeAllByUserID mencabut permanen semua key user, dipanggil bersama pencabutan session
+// supaya key lama tidak hidup lagi setelah penanda revoke di redis kedaluwarsa
+func (r *ApiKeyRepo) RevokeAllByUserID(idUser string) error {
+	now := time.Now()
+	if err := r.db.Model(&model.ApiKey{}).Where("id_user = ? AND revoked_at IS NULL", idUser).Update("revoked_at", &now).Error; err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (r *ApiKeyRepo) TouchApiKey(id, ip string, at time.Time) error {
+	return r.db.Model(&model.ApiKey{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
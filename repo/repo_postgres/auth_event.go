@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/model"
+)
+
+type AuthEventRepo struct {
+	db *gorm.DB
+}
+
+func NewAuthEventRepo(db *gorm.DB) domain.AuthEventRepository {
+	return &AuthEventRepo{
+		db: db,
+	}
+}
+
+func (r *AuthEventRepo) CreateAuthEvent(event *model.AuthEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *AuthEventRepo) FindAuthEvents(filter domain.AuthEventFilter) ([]model.AuthEvent, error) {
+	query := r.db.Model(&model.AuthEvent{})
+	if filter.IdUser != "" {
+		query = query.Where("id_user = ?", filter.IdUser)
+	}
+	if filter.Email != "" {
+		query = query.Where("email ILIKE ?", "%"+filter.Email+"%")
+	}
+	if filter.IP != "" {
+		query = query.Where("ip = ?", filter.IP)
+	}
+	if len(filter.EventTypes) > 0 {
+		query = query.Where("event_type IN ?", filter.EventTypes)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var events []model.AuthEvent
+	err := query.Order("created_at DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&events).Error
+	return events, err
+}
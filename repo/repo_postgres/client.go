@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
@@ -12,6 +14,7 @@ import (
 
 	"xops-admin/domain"
 	domain_user "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
 	"xops-admin/model"
 )
 
@@ -24,11 +27,11 @@ func NewClientRepo(db *gorm.DB) domain.ClientRepository {
 		db: db,
 	}
 }
-func (r *ClientRepo) GetClientWithLastPentest(ctx context.Context, id string, domain string, es *elasticsearch.Client) (*domain_user.ClientPenTestInfo, error) {
+func (r *ClientRepo) GetClientWithLastPentest(ctx context.Context, id string, domains []string, es *elasticsearch.Client) (*domain_user.ClientPenTestInfo, error) {
 	// 1. Ambil data client dulud
 	var client model.Client
 	if err := r.db.
-		Where("id_user = ?", id).
+		Where("id IN (SELECT id_client FROM client_members WHERE id_user = ?)", id).
 		First(&client).Error; err != nil {
 		return nil, err
 	}
@@ -37,8 +40,8 @@ func (r *ClientRepo) GetClientWithLastPentest(ctx context.Context, id string, do
 	query := map[string]interface{}{
 		"size": 0,
 		"query": map[string]interface{}{
-			"term": map[string]interface{}{
-				"flag_domain.keyword": domain, // asumsinya ada field domain di client
+			"terms": map[string]interface{}{
+				"flag_domain.keyword": domains, // asumsinya ada field domain di client
 			},
 		},
 		"aggs": map[string]interface{}{
@@ -85,18 +88,17 @@ func (r *ClientRepo) GetClientWithLastPentest(ctx context.Context, id string, do
 			}
 		}
 	}
-	host := "https://xops-api.sector.co.id/static/" + client.LogoCompany
-	// 3. Return gabungan data client + last pentest
+	// 3. Return gabungan data client + last pentest, LogoCompany masih berupa key blob
 	return &domain_user.ClientPenTestInfo{
 		CompanyName: client.CompanyName,
-		LogoCompany: host,
+		LogoCompany: client.LogoCompany,
 		LastTest:    lastDate,
 	}, nil
 }
 
 func (r *ClientRepo) GetClientByUserID(userID string) (*model.Client, error) {
 	var client model.Client
-	err := r.db.Preload("DomainClient").Where("id_user = ?", userID).First(&client).Error
+	err := r.db.Preload("DomainClient").Where("id IN (SELECT id_client FROM client_members WHERE id_user = ?)", userID).First(&client).Error
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +113,50 @@ func (r *ClientRepo) UpdateClient(client *model.Client) error {
 	return r.db.Session(&gorm.Session{FullSaveAssociations: true}).Updates(client).Error
 }
 
+func (r *ClientRepo) UpdateClientWithEmail(client *model.Client, email string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if email != "" {
+			if err := tx.Model(&model.User{}).Where("id = ?", client.IdUser).Update("email", email).Error; err != nil {
+				if strings.Contains(err.Error(), "duplicate key value violates unique") {
+					return errorenum.DuplicateEmail
+				}
+				return errorenum.SomethingError
+			}
+		}
+		if err := tx.Session(&gorm.Session{FullSaveAssociations: true}).Updates(client).Error; err != nil {
+			return errorenum.SomethingError
+		}
+		return nil
+	})
+}
+
+// UpdateSsoConfig hanya menyimpan kolom SSO, termasuk oidc_enabled = false
+func (r *ClientRepo) UpdateSsoConfig(client *model.Client) error {
+	return r.db.Model(&model.Client{}).
+		Where("id = ?", client.Id).
+		Select("oidc_enabled", "oidc_issuer", "oidc_client_id", "oidc_client_secret", "oidc_allowed_domains").
+		Updates(client).Error
+}
+
+func (r *ClientRepo) SetRequirePasskey(clientID string, required bool) error {
+	result := r.db.Model(&model.Client{}).Where("id = ?", clientID).Update("require_passkey", required)
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *ClientRepo) PasskeyRequiredForUser(user *model.User) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Client{}).
+		Where("require_passkey = ? AND id IN (SELECT id_client FROM client_members WHERE id_user = ?)", true, user.Id).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *ClientRepo) GetClientByID(id string) (*model.Client, error) {
 	var client model.Client
 	err := r.db.Preload("DomainClient").Where("id = ?", id).First(&client).Error
@@ -119,24 +165,15 @@ func (r *ClientRepo) GetClientByID(id string) (*model.Client, error) {
 	}
 	return &client, nil
 }
-func (r *ClientRepo) GetDomainByClientID(id string) (*model.DomainClient, error) {
-	var client model.Client
-	// cari client dulu
-	if err := r.db.
-		Where("id_user = ?", id).
-		First(&client).Error; err != nil {
-		return nil, err
-	}
-
-	var domain model.DomainClient
-	// lalu cari domain dari client_id
-	if err := r.db.
-		Where("id_client = ?", client.Id).
-		Order("created_at DESC").
-		First(&domain).Error; err != nil {
-		return nil, err
-	}
-	return &domain, nil
+func (r *ClientRepo) GetActiveDomainsByUserID(userID string) ([]model.DomainClient, error) {
+	var domains []model.DomainClient
+	err := r.db.
+		Joins("JOIN client_members ON client_members.id_client = domain_clients.id_client").
+		Joins("JOIN clients ON clients.id = domain_clients.id_client AND clients.deleted_at IS NULL").
+		Where("domain_clients.active = ? AND client_members.id_user = ?", true, userID).
+		Order("domain_clients.created_at DESC").
+		Find(&domains).Error
+	return domains, err
 }
 
 // Additional helper method to get active domains for a client
@@ -154,3 +191,151 @@ func (r *ClientRepo) DomainExistsForClient(clientID, domain string) (bool, error
 		Count(&count).Error
 	return count > 0, err
 }
+
+func (r *ClientRepo) DomainTaken(domain string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.DomainClient{}).
+		Joins("JOIN clients ON clients.id = domain_clients.id_client AND clients.deleted_at IS NULL").
+		Where("LOWER(domain_clients.domain) = LOWER(?)", domain).
+		Count(&count).Error
+	return count > 0, err
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *ClientRepo) SearchClients(filter domain.ClientFilter) ([]model.Client, error) {
+	query := r.db.Model(&model.Client{}).Preload("DomainClient")
+	if filter.Query != "" {
+		// wildcard dari input user di-escape supaya "%" atau "_" tidak mencocokkan semua client
+		like := "%" + likeEscaper.Replace(filter.Query) + "%"
+		query = query.Where(`company_name ILIKE ? ESCAPE '\' OR id IN (SELECT id_client FROM domain_clients WHERE domain ILIKE ? ESCAPE '\')`, like, like)
+	}
+
+	var clients []model.Client
+	err := query.Order("company_name ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&clients).Error
+	return clients, err
+}
+
+func (r *ClientRepo) AddDomain(domainClient *model.DomainClient) error {
+	return r.db.Create(domainClient).Error
+}
+
+func (r *ClientRepo) SetDomainActive(clientID, domainID string, active bool) error {
+	result := r.db.Model(&model.DomainClient{}).
+		Where("id = ? AND id_client = ?", domainID, clientID).
+		Updates(map[string]interface{}{"active": active, "updated_at": time.Now()})
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *ClientRepo) GetDomainByID(clientID, domainID string) (*model.DomainClient, error) {
+	var domainClient model.DomainClient
+	if err := r.db.First(&domainClient, "id = ? AND id_client = ?", domainID, clientID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errorenum.DataNotFound
+		}
+		return nil, errorenum.SomethingError
+	}
+	return &domainClient, nil
+}
+
+func (r *ClientRepo) UpdateDomainVerification(domainClient *model.DomainClient) error {
+	domainClient.UpdatedAt = time.Now()
+	return r.db.Model(&model.DomainClient{}).
+		Where("id = ? AND id_client = ?", domainClient.Id, domainClient.IdClient).
+		Select("active", "verification_status", "verification_method", "verified_at", "last_checked_at", "last_check_error", "updated_at").
+		Updates(domainClient).Error
+}
+
+func (r *ClientRepo) DeleteDomain(clientID, domainID string) error {
+	result := r.db.Delete(&model.DomainClient{}, "id = ? AND id_client = ?", domainID, clientID)
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *ClientRepo) FindScopeRules(clientID string) ([]model.ScopeRule, error) {
+	var rules []model.ScopeRule
+	err := r.db.Where("id_client = ?", clientID).Order("created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *ClientRepo) AddScopeRule(rule *model.ScopeRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *ClientRepo) DeleteScopeRule(clientID, ruleID string) error {
+	result := r.db.Delete(&model.ScopeRule{}, "id = ? AND id_client = ?", ruleID, clientID)
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *ClientRepo) UpdateContractWindow(id string, startDate, endDate time.Time) error {
+	result := r.db.Model(&model.Client{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"start_date": startDate, "end_date": endDate, "contract_reminder_days": 0})
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *ClientRepo) FindClientsEndingBetween(from, to time.Time) ([]model.Client, error) {
+	var clients []model.Client
+	err := r.db.Where("end_date >= ? AND end_date < ?", from, to).Find(&clients).Error
+	return clients, err
+}
+
+func (r *ClientRepo) MarkContractReminder(id string, previous, days int) (bool, error) {
+	result := r.db.Model(&model.Client{}).
+		Where("id = ? AND contract_reminder_days = ?", id, previous).
+		Update("contract_reminder_days", days)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ClientRepo) FindClientsWithLegacyLogo() ([]model.Client, error) {
+	var clients []model.Client
+	err := r.db.Unscoped().
+		Where("logo_company <> '' AND logo_company NOT LIKE ?", "logos/%").
+		Find(&clients).Error
+	return clients, err
+}
+
+func (r *ClientRepo) UpdateLogo(id, logo string) error {
+	result := r.db.Unscoped().Model(&model.Client{}).Where("id = ?", id).Update("logo_company", logo)
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *ClientRepo) DeleteClient(id string) error {
+	result := r.db.Delete(&model.Client{}, "id = ?", id)
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
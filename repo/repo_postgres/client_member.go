@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type ClientMemberRepo struct {
+	db *gorm.DB
+}
+
+func NewClientMemberRepo(db *gorm.DB) domain.ClientMemberRepository {
+	return &ClientMemberRepo{
+		db: db,
+	}
+}
+
+func (r *ClientMemberRepo) CreateMember(member *model.ClientMember) error {
+	if err := r.db.Create(member).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique") {
+			return errorenum.AlreadyMember
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *ClientMemberRepo) FindMemberByID(id string) (*model.ClientMember, error) {
+	var member model.ClientMember
+	if err := r.db.First(&member, "id = ?", id); err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &member, nil
+}
+
+func (r *ClientMemberRepo) FindMemberByUserID(idUser string) (*model.ClientMember, error) {
+	var member model.ClientMember
+	if err := r.db.First(&member, "id_user = ?", idUser); err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &member, nil
+}
+
+func (r *ClientMemberRepo) FindMembersByClientID(idClient string) ([]model.ClientMember, error) {
+	var members []model.ClientMember
+	err := r.db.Preload("User").Where("id_client = ?", idClient).Order("created_at ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *ClientMemberRepo) CountOwners(idClient string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ClientMember{}).
+		Where("id_client = ? AND role = ?", idClient, model.ClientMemberOwner).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ClientMemberRepo) DeleteMember(id string) error {
+	result := r.db.Delete(&model.ClientMember{}, "id = ?", id)
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return result.Error
+}
+
+func (r *ClientMemberRepo) CreateUserWithMember(user *model.User, member *model.ClientMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createUserWithMember(tx, user, member)
+	})
+}
+
+func createUserWithMember(tx *gorm.DB, user *model.User, member *model.ClientMember) error {
+	if err := tx.Create(user).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique") {
+			return errorenum.DuplicateEmail
+		}
+		return errorenum.SomethingError
+	}
+	if err := tx.Create(member).Error; err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (r *ClientMemberRepo) CreateInvitation(invitation *model.ClientInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *ClientMemberRepo) FindInvitationByID(id string) (*model.ClientInvitation, error) {
+	var invitation model.ClientInvitation
+	if err := r.db.First(&invitation, "id = ?", id); err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &invitation, nil
+}
+
+func (r *ClientMemberRepo) FindPendingInvitations(idClient string) ([]model.ClientInvitation, error) {
+	var invitations []model.ClientInvitation
+	err := r.db.Where("id_client = ? AND accepted_at IS NULL AND expires_at > ?", idClient, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *ClientMemberRepo) DeleteInvitation(id string) error {
+	result := r.db.Delete(&model.ClientInvitation{}, "id = ? AND accepted_at IS NULL", id)
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return result.Error
+}
+
+func (r *ClientMemberRepo) AcceptInvitation(invitation *model.ClientInvitation, user *model.User, member *model.ClientMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// update bersyarat supaya dua request accept bersamaan tidak membuat dua akun
+		result := tx.Model(&model.ClientInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.Id).
+			Update("accepted_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errorenum.InvalidInvitation
+		}
+		return createUserWithMember(tx, user, member)
+	})
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type EngagementRepo struct {
+	db *gorm.DB
+}
+
+func NewEngagementRepo(db *gorm.DB) domain.EngagementRepository {
+	return &EngagementRepo{
+		db: db,
+	}
+}
+
+func (r *EngagementRepo) CreateEngagement(engagement *model.Engagement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// pentester adalah user yang sudah ada, cukup isi tabel relasinya
+		if err := tx.Omit("Pentesters").Create(engagement).Error; err != nil {
+			return err
+		}
+		return replacePentesters(tx, engagement)
+	})
+}
+
+func (r *EngagementRepo) UpdateEngagement(engagement *model.Engagement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		engagement.UpdatedAt = time.Now()
+		result := tx.Model(&model.Engagement{}).
+			Where("id = ?", engagement.Id).
+			Select("name", "start_date", "end_date", "updated_at").
+			Updates(engagement)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errorenum.DataNotFound
+		}
+
+		if err := tx.Where("id_engagement = ?", engagement.Id).Delete(&model.EngagementDomain{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id_engagement = ?", engagement.Id).Delete(&model.EngagementUrlPattern{}).Error; err != nil {
+			return err
+		}
+		if len(engagement.Domains) > 0 {
+			if err := tx.Create(&engagement.Domains).Error; err != nil {
+				return err
+			}
+		}
+		if len(engagement.UrlPatterns) > 0 {
+			if err := tx.Create(&engagement.UrlPatterns).Error; err != nil {
+				return err
+			}
+		}
+		return replacePentesters(tx, engagement)
+	})
+}
+
+func replacePentesters(tx *gorm.DB, engagement *model.Engagement) error {
+	if err := tx.Exec("DELETE FROM engagement_pentesters WHERE engagement_id = ?", engagement.Id).Error; err != nil {
+		return err
+	}
+	for _, user := range engagement.Pentesters {
+		if err := tx.Exec("INSERT INTO engagement_pentesters (engagement_id, user_id) VALUES (?, ?)", engagement.Id, user.Id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *EngagementRepo) FindEngagementByID(id string) (*model.Engagement, error) {
+	var engagement model.Engagement
+	if err := r.db.Preload("Domains").Preload("UrlPatterns").Preload("Pentesters").First(&engagement, "id = ?", id); err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &engagement, nil
+}
+
+func (r *EngagementRepo) FindEngagementsByClientID(idClient string) ([]model.Engagement, error) {
+	var engagements []model.Engagement
+	err := r.db.Preload("Domains").Preload("UrlPatterns").Preload("Pentesters").
+		Where("id_client = ?", idClient).
+		Order("start_date DESC").
+		Find(&engagements).Error
+	return engagements, err
+}
+
+func (r *EngagementRepo) DeleteEngagement(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Engagement{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errorenum.DataNotFound
+		}
+		// temuan tetap disimpan, hanya dilepas dari engagement yang dihapus
+		return tx.Model(&model.ListBug{}).Where("id_engagement = ?", id).Update("id_engagement", "").Error
+	})
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type ImpersonationRepo struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepo(db *gorm.DB) domain.ImpersonationRepository {
+	return &ImpersonationRepo{
+		db: db,
+	}
+}
+
+func (r *ImpersonationRepo) CreateImpersonation(impersonation *model.Impersonation) error {
+	return r.db.Create(impersonation).Error
+}
+
+func (r *ImpersonationRepo) FindImpersonationByID(id string) (*model.Impersonation, error) {
+	var impersonation model.Impersonation
+	err := r.db.Preload("Requests", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).First(&impersonation, "id = ?", id)
+	if err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &impersonation, err.Error
+}
+
+func (r *ImpersonationRepo) FindImpersonations(filter domain.ImpersonationFilter) ([]model.Impersonation, error) {
+	query := r.db.Model(&model.Impersonation{})
+	if filter.IdAdmin != "" {
+		query = query.Where("id_admin = ?", filter.IdAdmin)
+	}
+	if filter.IdUser != "" {
+		query = query.Where("id_user = ?", filter.IdUser)
+	}
+
+	var impersonations []model.Impersonation
+	err := query.Order("started_at DESC").Offset(filter.Offset).Limit(filter.Limit).Find(&impersonations).Error
+	return impersonations, err
+}
+
+func (r *ImpersonationRepo) EndImpersonation(id string, reason string) error {
+	result := r.db.Model(&model.Impersonation{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Updates(map[string]interface{}{"ended_at": time.Now(), "ended_reason": reason})
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return result.Error
+}
+
+func (r *ImpersonationRepo) EndImpersonationsByAdmin(idAdmin string, reason string) error {
+	return r.db.Model(&model.Impersonation{}).
+		Where("id_admin = ? AND ended_at IS NULL", idAdmin).
+		Updates(map[string]interface{}{"ended_at": time.Now(), "ended_reason": reason}).Error
+}
+
+func (r *ImpersonationRepo) CloseExpiredImpersonations() error {
+	return r.db.Model(&model.Impersonation{}).
+		Where("ended_at IS NULL AND expires_at <= ?", time.Now()).
+		Updates(map[string]interface{}{"ended_at": gorm.Expr("expires_at"), "ended_reason": model.ImpersonationEndedExpired}).Error
+}
+
+func (r *ImpersonationRepo) CreateImpersonationRequest(request *model.ImpersonationRequest) error {
+	return r.db.Create(request).Error
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type KnownDeviceRepo struct {
+	db *gorm.DB
+}
+
+func NewKnownDeviceRepo(db *gorm.DB) domain.KnownDeviceRepository {
+	return &KnownDeviceRepo{
+		db: db,
+	}
+}
+
+func (r *KnownDeviceRepo) CreateDevice(device *model.KnownDevice) error {
+	return r.db.Create(device).Error
+}
+
+func (r *KnownDeviceRepo) FindDevice(idUser, fingerprint string) (*model.KnownDevice, error) {
+	var device model.KnownDevice
+	if result := r.db.First(&device, "id_user = ? AND fingerprint = ?", idUser, fingerprint); result.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &device, nil
+}
+
+func (r *KnownDeviceRepo) FindDevicesByUserID(idUser string) ([]model.KnownDevice, error) {
+	var devices []model.KnownDevice
+	err := r.db.Where("id_user = ?", idUser).Order("last_seen_at DESC").Find(&devices).Error
+	return devices, err
+}
+
+func (r *KnownDeviceRepo) CountDevicesByUserID(idUser string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.KnownDevice{}).Where("id_user = ?", idUser).Count(&count).Error
+	return count, err
+}
+
+func (r *KnownDeviceRepo) TouchDevice(id string, at time.Time) error {
+	return r.db.Model(&model.KnownDevice{}).Where("id = ?", id).Update("last_seen_at", at).Error
+}
+
+func (r *KnownDeviceRepo) DeleteDevice(idUser, id string) error {
+	result := r.db.Where("id = ? AND id_user = ?", id, idUser).Delete(&model.KnownDevice{})
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
@@ -60,6 +60,7 @@ func (r *ListBugRepo) GetBugs(ctx context.Context, filter domain.ListBugFilter)
 			list_bugs.vulnerability,
 			list_bugs.validation,
 			list_bugs.flag_domain,
+			list_bugs.id_engagement,
 			list_bugs.created_at,
 			list_bugs.updated_at,
 			list_vulnerabilities.name_bug as name_bug,
@@ -84,8 +85,11 @@ func (r *ListBugRepo) GetBugs(ctx context.Context, filter domain.ListBugFilter)
 	}
 
 	// Apply flag_domain filter
-	if filter.FlagDomain != "" {
-		query = query.Where("list_bugs.flag_domain = ?", filter.FlagDomain)
+	if len(filter.FlagDomains) > 0 {
+		query = query.Where("list_bugs.flag_domain IN ?", filter.FlagDomains)
+	}
+	if filter.IdEngagement != "" {
+		query = query.Where("list_bugs.id_engagement = ?", filter.IdEngagement)
 	}
 
 	// Apply filters
@@ -265,6 +269,7 @@ func (r *ListBugRepo) GetBugs(ctx context.Context, filter domain.ListBugFilter)
 			Vulnerability:  util_uuid.Capitalize(bug.Vulnerability),
 			Validation:     util_uuid.Capitalize(bug.Validation),
 			FlagDomain:     bug.FlagDomain,
+			IdEngagement:   bug.IdEngagement,
 			CreatedAt:      bug.CreatedAt,
 			UpdatedAt:      bug.UpdatedAt,
 		}
@@ -393,8 +398,11 @@ func (r *ListBugRepo) applyFilters(query *gorm.DB, filter domain.ListBugFilter)
 		)
 	}
 
-	if filter.FlagDomain != "" {
-		query = query.Where("list_bugs.flag_domain = ?", filter.FlagDomain)
+	if len(filter.FlagDomains) > 0 {
+		query = query.Where("list_bugs.flag_domain IN ?", filter.FlagDomains)
+	}
+	if filter.IdEngagement != "" {
+		query = query.Where("list_bugs.id_engagement = ?", filter.IdEngagement)
 	}
 
 	// FIXED: Handle severity filter properly, exclude "all_severity"
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type PermissionRepo struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepo(db *gorm.DB) domain.PermissionRepository {
+	return &PermissionRepo{
+		db: db,
+	}
+}
+
+func (r *PermissionRepo) FindAllPermissions() ([]model.Permission, error) {
+	var permissions []model.Permission
+	if err := r.db.Order("name").Find(&permissions).Error; err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return permissions, nil
+}
+
+func (r *PermissionRepo) FindPermissionsByNames(names []string) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	if err := r.db.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return permissions, nil
+}
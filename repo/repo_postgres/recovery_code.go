@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type RecoveryCodeRepo struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepo(db *gorm.DB) domain.RecoveryCodeRepository {
+	return &RecoveryCodeRepo{
+		db: db,
+	}
+}
+
+// ReplaceRecoveryCodes menghapus kode lama user lalu menyimpan set kode yang baru
+func (r *RecoveryCodeRepo) ReplaceRecoveryCodes(idUser string, codes []model.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id_user = ?", idUser).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *RecoveryCodeRepo) FindUnusedByUserID(idUser string) ([]model.RecoveryCode, error) {
+	var codes []model.RecoveryCode
+	err := r.db.Where("id_user = ? AND used_at IS NULL", idUser).Find(&codes).Error
+	return codes, err
+}
+
+func (r *RecoveryCodeRepo) MarkUsed(id string) error {
+	now := time.Now()
+	result := r.db.Model(&model.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", &now)
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *RecoveryCodeRepo) DeleteByUserID(idUser string) error {
+	return r.db.Where("id_user = ?", idUser).Delete(&model.RecoveryCode{}).Error
+}
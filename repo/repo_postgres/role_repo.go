@@ -26,7 +26,7 @@ func (r *RoleRepo) CreateRole(role *model.Role) error {
 }
 func (r *RoleRepo) FindAllRoles() ([]*model.Role, error) {
 	var roles []*model.Role
-	result := r.db.Find(&roles)
+	result := r.db.Preload("Permissions").Order("id").Find(&roles)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -43,14 +43,14 @@ func (u *RoleRepo) FindRoleBYName(name string) (*model.Role, error) {
 
 func (u *RoleRepo) FindRoleBYID(id int) (*model.Role, error) {
 	var role model.Role
-	if err := u.db.First(&role, "id = ?", id); err.RowsAffected == 0 {
+	if err := u.db.Preload("Permissions").First(&role, "id = ?", id); err.RowsAffected == 0 {
 		return nil, errorenum.DataNotFound
 	}
 	return &role, nil
 }
 
 func (r *RoleRepo) DeleteRole(id int) error {
-	result := r.db.Delete(&model.Role{}, id)
+	result := r.db.Select("Permissions").Delete(&model.Role{Id: id})
 	if result.Error != nil {
 		return errorenum.SomethingError
 	}
@@ -64,6 +64,21 @@ func (r *RoleRepo) UpdateRole(role *model.Role) error {
 	return nil
 }
 
+func (r *RoleRepo) ReplaceRolePermissions(role *model.Role, permissions []model.Permission) error {
+	if err := r.db.Model(role).Association("Permissions").Replace(permissions); err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (r *RoleRepo) CountUsersByRoleID(id int) (int64, error) {
+	var total int64
+	if err := r.db.Model(&model.User{}).Where("id_role = ?", id).Count(&total).Error; err != nil {
+		return 0, errorenum.SomethingError
+	}
+	return total, nil
+}
+
 func NewRoleRepo(db *gorm.DB) domain.RoleRepository {
 	return &RoleRepo{
 		db: db,
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type SessionRepo struct {
+	db *gorm.DB
+}
+
+func NewSessionRepo(db *gorm.DB) domain.SessionRepository {
+	return &SessionRepo{
+		db: db,
+	}
+}
+
+func (r *SessionRepo) CreateSession(session *model.Session) error {
+	if err := r.db.Create(session).Error; err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (r *SessionRepo) FindSessionByID(id string) (*model.Session, error) {
+	var session model.Session
+	if err := r.db.First(&session, "id = ?", id); err.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &session, nil
+}
+
+func (r *SessionRepo) UpdateSession(session *model.Session) error {
+	if err := r.db.Save(session).Error; err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (r *SessionRepo) RotateRefreshToken(session *model.Session, previousTokenUuid string) (bool, error) {
+	result := r.db.Model(&model.Session{}).
+		Where("id = ? AND refresh_token_uuid = ? AND revoked_at IS NULL", session.Id, previousTokenUuid).
+		Updates(map[string]interface{}{
+			"refresh_token_uuid": session.RefreshTokenUuid,
+			"last_seen_at":       session.LastSeenAt,
+			"expires_at":         session.ExpiresAt,
+			"ip":                 session.IP,
+			"user_agent":         session.UserAgent,
+		})
+	if result.Error != nil {
+		return false, errorenum.SomethingError
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *SessionRepo) FindActiveByUserID(idUser string) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.
+		Where("id_user = ? AND revoked_at IS NULL AND expires_at > ?", idUser, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *SessionRepo) RevokeSession(id, reason string) error {
+	now := time.Now()
+	result := r.db.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": &now, "revoked_reason": reason})
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (r *SessionRepo) RevokeAllByUserID(idUser, reason string) error {
+	now := time.Now()
+	result := r.db.Model(&model.Session{}).
+		Where("id_user = ? AND revoked_at IS NULL", idUser).
+		Updates(map[string]interface{}{"revoked_at": &now, "revoked_reason": reason})
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
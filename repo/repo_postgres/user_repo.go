@@ -13,8 +13,7 @@ import (
 	domain_user_auth "xops-admin/domain/user/auth"
 	"xops-admin/helper/errorenum"
 	"xops-admin/model"
-	util_apikey "xops-admin/util/api_key"
-	util_encode "xops-admin/util/encode"
+	util_domainverify "xops-admin/util/domain_verify"
 )
 
 type UserRepo struct {
@@ -51,19 +50,19 @@ func (u *UserRepo) CreateUser(req *domain_user_auth.CreateUserWithClientRequest)
 		return errorenum.SomethingError
 	}
 	user := &model.User{
-		Id:           userId,
-		Name:         string(b),
-		Email:        req.Email,
-		Password:     string(hashedPassword),
-		IdRole:       req.IdRole,
-		IsVerified:   req.IsVerified,
-		IsTwoFA:      req.IsTwoFA,
-		VerifiedCode: "-",
-		TOTPKey:      "-",
-		RefreshToken: "-",
-		ApiKey:       util_apikey.GenerateSecureAPIKey(),
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		Id:                 userId,
+		Name:               string(b),
+		Email:              req.Email,
+		Password:           string(hashedPassword),
+		IdRole:             req.IdRole,
+		IsVerified:         req.IsVerified,
+		IsTwoFA:            req.IsTwoFA,
+		MustChangePassword: req.MustChangePassword,
+		VerifiedCode:       "-",
+		TOTPKey:            "-",
+		TwoFAMethod:        domain_user_auth.TwoFAMethodEmail,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}
 
 	if err := tx.Create(user).Error; err != nil {
@@ -75,7 +74,7 @@ func (u *UserRepo) CreateUser(req *domain_user_auth.CreateUserWithClientRequest)
 		return errorenum.SomethingError
 	}
 
-	if req.IdRole == 3 {
+	if req.WithClient || req.IdRole == model.RoleClient {
 		// Generate UUID for Client
 		clientId := uuid.New().String()
 
@@ -94,14 +93,35 @@ func (u *UserRepo) CreateUser(req *domain_user_auth.CreateUserWithClientRequest)
 			return errorenum.SomethingError
 		}
 
+		// pembuat client otomatis menjadi owner organisasi
+		member := &model.ClientMember{
+			Id:       uuid.New().String(),
+			IdClient: clientId,
+			IdUser:   userId,
+			Role:     model.ClientMemberOwner,
+		}
+		if err := tx.Create(member).Error; err != nil {
+			tx.Rollback()
+			return errorenum.SomethingError
+		}
+
 		// Create DomainClient entries
+		// domain baru belum aktif sampai kepemilikannya terverifikasi
 		for _, domain := range req.Domains {
+			token, err := util_domainverify.NewToken()
+			if err != nil {
+				tx.Rollback()
+				return errorenum.SomethingError
+			}
 			domainClient := &model.DomainClient{
-				Id:        uuid.New().String(),
-				IdClient:  clientId,
-				Domain:    domain,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				Id:                 uuid.New().String(),
+				IdClient:           clientId,
+				Domain:             domain,
+				Active:             false,
+				VerificationToken:  token,
+				VerificationStatus: model.DomainVerificationPending,
+				CreatedAt:          time.Now(),
+				UpdatedAt:          time.Now(),
 			}
 
 			if err := tx.Create(domainClient).Error; err != nil {
@@ -119,6 +139,17 @@ func (u *UserRepo) CreateUser(req *domain_user_auth.CreateUserWithClientRequest)
 	return nil
 }
 
+// InsertUser menyimpan user yang sudah lengkap tanpa membuat data client
+func (u *UserRepo) InsertUser(user *model.User) error {
+	if err := u.db.Create(user).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique") {
+			return errorenum.DuplicateEmail
+		}
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
 func (u *UserRepo) DeleteUser(id string) error {
 	if result := u.db.Where("id = ?", id).Delete(&model.User{}); result.RowsAffected == 0 {
 		return errorenum.DataNotFound
@@ -157,29 +188,14 @@ func (u *UserRepo) UpdateUser(user *model.User) error {
 	return nil
 }
 
-func (u *UserRepo) UserVerifyEmail(idUser, code string, duration int64) (*model.User, error) {
-
-	userDb, err := u.FindUserBYID(idUser)
-	if err != nil {
-		return nil, errorenum.FailedOtp
+func (u *UserRepo) AdvanceTOTPStep(id string, step int64) (bool, error) {
+	result := u.db.Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, errorenum.SomethingError
 	}
-	if !userDb.IsVerified {
-		return nil, errorenum.FailedLogin
-	}
-	verification_code := util_encode.Encode(code)
-	if err := u.db.First(userDb, "verified_code = ?", verification_code); err.RowsAffected == 0 {
-		return nil, errorenum.FailedOtp
-	}
-	currentTime := time.Now()
-	then := userDb.UpdatedAt.Add(time.Minute * time.Duration(duration))
-
-	if currentTime.After(then) {
-		return nil, errorenum.ExpiredOtp
-	}
-	userDb.UpdatedAt = time.Now()
-	userDb.VerifiedCode = ""
-
-	return userDb, nil
+	return result.RowsAffected > 0, nil
 }
 
 func NewUserRepo(db *gorm.DB) domain.UserRepository {
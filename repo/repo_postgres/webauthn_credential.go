@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"xops-admin/domain"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type WebauthnCredentialRepo struct {
+	db *gorm.DB
+}
+
+func NewWebauthnCredentialRepo(db *gorm.DB) domain.WebauthnCredentialRepository {
+	return &WebauthnCredentialRepo{
+		db: db,
+	}
+}
+
+func (r *WebauthnCredentialRepo) CreateCredential(credential *model.WebauthnCredential) error {
+	return r.db.Create(credential).Error
+}
+
+func (r *WebauthnCredentialRepo) FindCredentialsByUserID(idUser string) ([]model.WebauthnCredential, error) {
+	var credentials []model.WebauthnCredential
+	err := r.db.Where("id_user = ?", idUser).Order("created_at DESC").Find(&credentials).Error
+	return credentials, err
+}
+
+func (r *WebauthnCredentialRepo) FindCredentialByCredentialID(credentialID string) (*model.WebauthnCredential, error) {
+	var credential model.WebauthnCredential
+	if result := r.db.First(&credential, "credential_id = ?", credentialID); result.RowsAffected == 0 {
+		return nil, errorenum.DataNotFound
+	}
+	return &credential, nil
+}
+
+func (r *WebauthnCredentialRepo) CountCredentialsByUserID(idUser string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.WebauthnCredential{}).Where("id_user = ?", idUser).Count(&count).Error
+	return count, err
+}
+
+func (r *WebauthnCredentialRepo) RenameCredential(idUser, id, name string) error {
+	result := r.db.Model(&model.WebauthnCredential{}).
+		Where("id = ? AND id_user = ?", id, idUser).
+		Update("name", name)
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *WebauthnCredentialRepo) DeleteCredential(idUser, id string) error {
+	result := r.db.Where("id = ? AND id_user = ?", id, idUser).Delete(&model.WebauthnCredential{})
+	if result.Error != nil {
+		return errorenum.SomethingError
+	}
+	if result.RowsAffected == 0 {
+		return errorenum.DataNotFound
+	}
+	return nil
+}
+
+func (r *WebauthnCredentialRepo) TouchCredential(id string, signCount uint32, at time.Time) error {
+	return r.db.Model(&model.WebauthnCredential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"sign_count": signCount, "last_used_at": at}).Error
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -176,6 +177,16 @@ func (r *BulkUpdateSecurityChecklistRepo) insertToPostgreSQL(ctx context.Context
 		UpdatedAt:           time.Now(),
 	}
 
+	// Engagement asal temuan tidak berubah setelah tercatat
+	bugData.IdEngagement = existingBug.IdEngagement
+	if bugData.IdEngagement == "" {
+		idEngagement, findErr := r.findEngagementID(tx, flagDomain, update.URL, findingTime(doc))
+		if findErr != nil {
+			return fmt.Errorf("failed to find engagement: %w", findErr)
+		}
+		bugData.IdEngagement = idEngagement
+	}
+
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		// Record tidak ada, lakukan insert
 		bugData.CreatedAt = time.Now()
@@ -196,6 +207,45 @@ func (r *BulkUpdateSecurityChecklistRepo) insertToPostgreSQL(ctx context.Context
 	return nil
 }
 
+// findEngagementID engagement milik client pemilik flag_domain yang scope-nya mencakup temuan,
+// engagement terbaru didahulukan jika ada yang tumpang tindih
+func (r *BulkUpdateSecurityChecklistRepo) findEngagementID(tx *gorm.DB, flagDomain, url string, at time.Time) (string, error) {
+	if flagDomain == "" {
+		return "", nil
+	}
+	var engagements []model.Engagement
+	err := tx.Preload("Domains").Preload("UrlPatterns").
+		Joins("JOIN clients ON clients.id = engagements.id_client AND clients.deleted_at IS NULL").
+		Where("engagements.id IN (SELECT id_engagement FROM engagement_domains WHERE LOWER(domain) = LOWER(?))", flagDomain).
+		Order("engagements.start_date DESC").
+		Find(&engagements).Error
+	if err != nil {
+		return "", err
+	}
+	for i := range engagements {
+		if engagements[i].Covers(flagDomain, url, at) {
+			return engagements[i].Id, nil
+		}
+	}
+	return "", nil
+}
+
+// findingTime waktu temuan dari field "time" dokumen ES, sekarang jika tidak terbaca
+func findingTime(doc map[string]interface{}) time.Time {
+	switch v := doc["time"].(type) {
+	case float64:
+		return time.UnixMilli(int64(v))
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+		if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.UnixMilli(ms)
+		}
+	}
+	return time.Now()
+}
+
 // getVulnerabilityIDByName gets vulnerability ID by name, creates new one if not exists
 func (r *BulkUpdateSecurityChecklistRepo) getVulnerabilityIDByName(ctx context.Context, tx *gorm.DB, vulnerabilityName string) (int64, error) {
 	var vulnerability model.ListVulnerability
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_apikey "xops-admin/util/api_key"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const (
+	maxApiKeyLifetimeDays = 365
+	// dipakai jika expires_in_days tidak diisi, key tanpa masa berlaku tidak diizinkan
+	defaultApiKeyLifetimeDays = 90
+)
+
+type ApiKeyRepo struct {
+	apiKeyRepo domain.ApiKeyRepository
+}
+
+func NewApiKeyUseCase(apiKeyRepo domain.ApiKeyRepository) domain_user_auth.ApiKeyUseCase {
+	return &ApiKeyRepo{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+func (a *ApiKeyRepo) Create(idUser string, req *domain_user_auth.CreateApiKeyRequest) (*domain_user_auth.CreateApiKeyResponse, error) {
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !isValidApiKeyScope(scope) {
+			return nil, errorenum.InvalidApiKeyScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > maxApiKeyLifetimeDays {
+		return nil, errorenum.InvalidApiKeyExpiry
+	}
+	expiresInDays := req.ExpiresInDays
+	if expiresInDays == 0 {
+		expiresInDays = defaultApiKeyLifetimeDays
+	}
+	expiresAt := time.Now().AddDate(0, 0, expiresInDays)
+
+	key, prefix, err := util_apikey.GenerateAPIKey()
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	apiKey := &model.ApiKey{
+		Id:        util_uuid.GenerateID(),
+		IdUser:    idUser,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    prefix,
+		KeyHash:   util_apikey.HashAPIKey(key),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: &expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := a.apiKeyRepo.CreateApiKey(apiKey); err != nil {
+		return nil, err
+	}
+	return &domain_user_auth.CreateApiKeyResponse{
+		Key:    key,
+		ApiKey: domain_user_auth.ConvertApiKey(apiKey),
+	}, nil
+}
+
+func (a *ApiKeyRepo) List(idUser string) ([]domain_user_auth.ApiKeyResponse, error) {
+	apiKeys, err := a.apiKeyRepo.FindApiKeysByUserID(idUser)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain_user_auth.ApiKeyResponse, 0, len(apiKeys))
+	for i := range apiKeys {
+		result = append(result, domain_user_auth.ConvertApiKey(&apiKeys[i]))
+	}
+	return result, nil
+}
+
+func (a *ApiKeyRepo) Revoke(idUser, id string) error {
+	apiKey, err := a.apiKeyRepo.FindApiKeyByID(id)
+	if err != nil || apiKey.IdUser != idUser {
+		return errorenum.DataNotFound
+	}
+	return a.apiKeyRepo.RevokeApiKey(apiKey.Id)
+}
+
+func isValidApiKeyScope(scope string) bool {
+	for _, s := range model.ApiKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
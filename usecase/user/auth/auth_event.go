@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const (
+	defaultAuthEventLimit = 20
+	maxAuthEventLimit     = 100
+)
+
+type AuthEventRepo struct {
+	authEventRepo domain.AuthEventRepository
+}
+
+func NewAuthEventUseCase(authEventRepo domain.AuthEventRepository) domain_user_auth.AuthEventUseCase {
+	return &AuthEventRepo{
+		authEventRepo: authEventRepo,
+	}
+}
+
+func (a *AuthEventRepo) Record(input domain_user_auth.AuthEventInput) {
+	event := &model.AuthEvent{
+		Id:        util_uuid.GenerateID(),
+		IdUser:    input.IdUser,
+		Email:     strings.ToLower(input.Email),
+		EventType: input.EventType,
+		Outcome:   input.Outcome,
+		Reason:    truncate(input.Reason, 255),
+		IP:        truncate(input.IP, 45),
+		UserAgent: input.UserAgent,
+		SessionID: input.SessionID,
+		CreatedAt: time.Now(),
+	}
+	if err := a.authEventRepo.CreateAuthEvent(event); err != nil {
+		log.Println("auth event:", err)
+	}
+}
+
+func (a *AuthEventRepo) RecentSignIns(idUser string, limit int) ([]model.AuthEvent, error) {
+	events, err := a.authEventRepo.FindAuthEvents(domain.AuthEventFilter{
+		IdUser:     idUser,
+		EventTypes: model.SignInEvents,
+		Limit:      normalizeAuthEventLimit(limit),
+	})
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return events, nil
+}
+
+func (a *AuthEventRepo) Search(req *domain_user_auth.AuthEventSearchRequest) (*domain_user_auth.AuthEventListResponse, error) {
+	limit := normalizeAuthEventLimit(req.Limit)
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	filter := domain.AuthEventFilter{
+		IdUser:  req.IdUser,
+		Email:   strings.TrimSpace(req.Email),
+		IP:      strings.TrimSpace(req.IP),
+		Outcome: req.Outcome,
+		Offset:  (page - 1) * limit,
+		// ambil satu lebih untuk tahu ada halaman berikutnya
+		Limit: limit + 1,
+	}
+	if req.EventType != "" {
+		filter.EventTypes = strings.Split(req.EventType, ",")
+	}
+	if req.From != "" {
+		from, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return nil, errorenum.InvalidDateFormat
+		}
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return nil, errorenum.InvalidDateFormat
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, errorenum.InvalidDateRange
+	}
+
+	events, err := a.authEventRepo.FindAuthEvents(filter)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	hasNext := len(events) > limit
+	if hasNext {
+		events = events[:limit]
+	}
+	return &domain_user_auth.AuthEventListResponse{
+		Data: events,
+		Pagination: domain_user_auth.AuthEventPagination{
+			Page:        page,
+			Size:        len(events),
+			HasNext:     hasNext,
+			HasPrevious: page > 1,
+		},
+	}, nil
+}
+
+func normalizeAuthEventLimit(limit int) int {
+	if limit <= 0 {
+		return defaultAuthEventLimit
+	}
+	if limit > maxAuthEventLimit {
+		return maxAuthEventLimit
+	}
+	return limit
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_jwttoken "xops-admin/util/token_jwt"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const defaultImpersonationTokenTTL = 15 * time.Minute
+
+type ImpersonationRepo struct {
+	impersonationRepo domain.ImpersonationRepository
+	userRepo          domain.UserRepository
+}
+
+func NewImpersonationUseCase(impersonationRepo domain.ImpersonationRepository, userRepo domain.UserRepository) domain_user_auth.ImpersonationUseCase {
+	return &ImpersonationRepo{
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+	}
+}
+
+func (i *ImpersonationRepo) Start(idAdmin string, idUser string, req *domain_user_auth.StartImpersonationRequest, ip string, userAgent string) (*domain_user_auth.StartImpersonationResponse, error) {
+	user, err := i.userRepo.FindUserBYID(idUser)
+	if err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	// hanya user client yang boleh di-impersonate, bukan sesama staf
+	if user.Id == idAdmin || user.IdRole != model.RoleClient || !user.IsVerified {
+		return nil, errorenum.ImpersonationNotAllowed
+	}
+
+	loadconfig, _ := config.LoadConfig(".")
+	ttl := loadconfig.ImpersonationTokenExpiresIn
+	if ttl <= 0 {
+		ttl = defaultImpersonationTokenTTL
+	}
+
+	// satu admin hanya punya satu impersonation aktif
+	if err := i.impersonationRepo.EndImpersonationsByAdmin(idAdmin, model.ImpersonationEndedReplaced); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	now := time.Now()
+	impersonation := &model.Impersonation{
+		Id:        util_uuid.GenerateID(),
+		IdAdmin:   idAdmin,
+		IdUser:    user.Id,
+		Reason:    strings.TrimSpace(req.Reason),
+		IP:        truncate(ip, 45),
+		UserAgent: userAgent,
+		StartedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := i.impersonationRepo.CreateImpersonation(impersonation); err != nil {
+		return nil, errorenum.SomethingError
+	}
+
+	accessToken, err := util_jwttoken.GenerateImpersonationTokenJwt(ttl, user.Id, idAdmin, impersonation.Id, loadconfig.AccessTokenPrivateKey)
+	if err != nil {
+		_ = i.impersonationRepo.EndImpersonation(impersonation.Id, model.ImpersonationEndedByOperator)
+		return nil, errorenum.SomethingError
+	}
+	return &domain_user_auth.StartImpersonationResponse{
+		Impersonation: *impersonation,
+		AccessToken:   *accessToken.Token,
+		ExpiresAt:     impersonation.ExpiresAt,
+	}, nil
+}
+
+func (i *ImpersonationRepo) End(id string, reason string) error {
+	if err := i.impersonationRepo.EndImpersonation(id, reason); err != nil {
+		return errorenum.ImpersonationEnded
+	}
+	return nil
+}
+
+func (i *ImpersonationRepo) Search(req *domain_user_auth.ImpersonationSearchRequest) (*domain_user_auth.ImpersonationListResponse, error) {
+	if err := i.impersonationRepo.CloseExpiredImpersonations(); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	limit := normalizeAuthEventLimit(req.Limit)
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	impersonations, err := i.impersonationRepo.FindImpersonations(domain.ImpersonationFilter{
+		IdAdmin: req.IdAdmin,
+		IdUser:  req.IdUser,
+		Offset:  (page - 1) * limit,
+		Limit:   limit + 1,
+	})
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	hasNext := len(impersonations) > limit
+	if hasNext {
+		impersonations = impersonations[:limit]
+	}
+	return &domain_user_auth.ImpersonationListResponse{
+		Data: impersonations,
+		Pagination: domain_user_auth.AuthEventPagination{
+			Page:        page,
+			Size:        len(impersonations),
+			HasNext:     hasNext,
+			HasPrevious: page > 1,
+		},
+	}, nil
+}
+
+func (i *ImpersonationRepo) Detail(id string) (*model.Impersonation, error) {
+	if err := i.impersonationRepo.CloseExpiredImpersonations(); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return i.impersonationRepo.FindImpersonationByID(id)
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_signedtoken "xops-admin/util/signed_token"
+	util_useragent "xops-admin/util/user_agent"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const (
+	notMePurpose = "not_me"
+	notMeTTL     = 7 * 24 * time.Hour
+)
+
+type KnownDeviceRepo struct {
+	deviceRepo  domain.KnownDeviceRepository
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	apiKeyRepo  domain.ApiKeyRepository
+}
+
+func NewKnownDeviceUseCase(deviceRepo domain.KnownDeviceRepository, userRepo domain.UserRepository, sessionRepo domain.SessionRepository, apiKeyRepo domain.ApiKeyRepository) domain_user_auth.KnownDeviceUseCase {
+	return &KnownDeviceRepo{
+		deviceRepo:  deviceRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+func deviceFingerprint(ip, userAgent string) string {
+	sum := sha256.Sum256([]byte(ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+func (k *KnownDeviceRepo) CheckSignIn(user *model.User, ip, userAgent string) {
+	now := time.Now()
+	fingerprint := deviceFingerprint(ip, userAgent)
+	if device, err := k.deviceRepo.FindDevice(user.Id, fingerprint); err == nil {
+		k.deviceRepo.TouchDevice(device.Id, now)
+		return
+	}
+
+	// device pertama user tidak perlu diberi peringatan
+	count, err := k.deviceRepo.CountDevicesByUserID(user.Id)
+	if err != nil {
+		log.Println("known device:", err)
+		return
+	}
+	device := &model.KnownDevice{
+		Id:          util_uuid.GenerateID(),
+		IdUser:      user.Id,
+		Fingerprint: fingerprint,
+		IP:          ip,
+		UserAgent:   userAgent,
+		Browser:     util_useragent.Describe(userAgent),
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+	if err := k.deviceRepo.CreateDevice(device); err != nil {
+		log.Println("known device:", err)
+		return
+	}
+	if count == 0 {
+		return
+	}
+
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return
+	}
+	token, _, err := util_signedtoken.Generate(loadconfig.LinkSigningSecret, notMePurpose, user.Id+":"+device.Id, notMeTTL)
+	if err != nil {
+		return
+	}
+	emailData := domain.EmailData{
+		FirstName: user.Name,
+		Data:      loadconfig.FrontendURL + "/security/not-me?token=" + url.QueryEscape(token),
+		Subject:   "New sign-in to your SectorOne account",
+		Device: &domain.EmailDevice{
+			Time:    now.Format("02 Jan 2006 15:04 MST"),
+			IP:      ip,
+			Browser: device.Browser,
+		},
+	}
+	go domain.SendEmail(user, user.Email, &emailData, "new_device_signin.html", "templates/new_device_signin")
+}
+
+func (k *KnownDeviceRepo) ListDevices(idUser string) ([]model.KnownDevice, error) {
+	devices, err := k.deviceRepo.FindDevicesByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return devices, nil
+}
+
+func (k *KnownDeviceRepo) ForgetDevice(idUser, id string) error {
+	return k.deviceRepo.DeleteDevice(idUser, id)
+}
+
+func (k *KnownDeviceRepo) ReportNotMe(token string) error {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return errorenum.SomethingError
+	}
+	claims, err := util_signedtoken.Parse(loadconfig.LinkSigningSecret, notMePurpose, token)
+	if err != nil {
+		return errorenum.InvalidNotMeLink
+	}
+	idUser, idDevice, ok := strings.Cut(claims.Subject, ":")
+	if !ok {
+		return errorenum.InvalidNotMeLink
+	}
+	if _, err := k.userRepo.FindUserBYID(idUser); err != nil {
+		return errorenum.InvalidNotMeLink
+	}
+
+	if err := k.sessionRepo.RevokeAllByUserID(idUser, domain_user_auth.SessionRevokedNotMe); err != nil {
+		return errorenum.SomethingError
+	}
+	if err := k.apiKeyRepo.RevokeAllByUserID(idUser); err != nil {
+		return errorenum.SomethingError
+	}
+	if err := config.RevokeUserTokens(idUser, time.Now(), loadconfig.RefreshTokenExpiresIn); err != nil {
+		return errorenum.SomethingError
+	}
+	// device mungkin sudah dihapus user, tidak masalah
+	k.deviceRepo.DeleteDevice(idUser, idDevice)
+	return nil
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+const (
+	maxFailedAttempts = 5
+	failedAttemptTTL  = 15 * time.Minute
+	lockLevelTTL      = 24 * time.Hour
+)
+
+// lama kunci naik setiap kali akun terkunci lagi dalam lockLevelTTL
+var lockWindows = []time.Duration{
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+type LockoutRepo struct {
+	userRepo domain.UserRepository
+}
+
+func NewLockoutUseCase(userRepo domain.UserRepository) domain_user_auth.LockoutUseCase {
+	return &LockoutRepo{
+		userRepo: userRepo,
+	}
+}
+
+func failedAttemptKey(idUser, kind string) string {
+	return fmt.Sprintf("auth:failed:%s:%s", kind, idUser)
+}
+
+func lockKey(idUser string) string {
+	return "auth:locked:" + idUser
+}
+
+func lockLevelKey(idUser string) string {
+	return "auth:lock_level:" + idUser
+}
+
+func (l *LockoutRepo) CheckLocked(idUser string) error {
+	ttl, err := config.RedisClient.TTL(context.Background(), lockKey(idUser)).Result()
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	if ttl > 0 {
+		return errorenum.AccountLocked
+	}
+	return nil
+}
+
+func (l *LockoutRepo) RegisterFailure(user *model.User, kind string) error {
+	ctx := context.Background()
+	key := failedAttemptKey(user.Id, kind)
+
+	failed, err := config.RedisClient.Incr(ctx, key).Result()
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	if failed == 1 {
+		config.RedisClient.Expire(ctx, key, failedAttemptTTL)
+	}
+	if failed < maxFailedAttempts {
+		return nil
+	}
+
+	level, err := config.RedisClient.Incr(ctx, lockLevelKey(user.Id)).Result()
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	config.RedisClient.Expire(ctx, lockLevelKey(user.Id), lockLevelTTL)
+
+	window := lockWindows[len(lockWindows)-1]
+	if int(level) <= len(lockWindows) {
+		window = lockWindows[level-1]
+	}
+	if err := config.RedisClient.Set(ctx, lockKey(user.Id), strconv.FormatInt(level, 10), window).Err(); err != nil {
+		return errorenum.SomethingError
+	}
+	config.RedisClient.Del(ctx, failedAttemptKey(user.Id, domain_user_auth.FailedAttemptPassword), failedAttemptKey(user.Id, domain_user_auth.FailedAttemptOTP))
+
+	emailData := domain.EmailData{
+		FirstName: user.Name,
+		Data:      time.Now().Add(window).Format("02 Jan 2006 15:04 MST"),
+		Subject:   "Your SectorOne account has been temporarily locked",
+	}
+	go domain.SendEmail(user, user.Email, &emailData, "account_locked.html", "templates/account_locked")
+
+	return errorenum.AccountLocked
+}
+
+func (l *LockoutRepo) ResetFailures(idUser, kind string) {
+	config.RedisClient.Del(context.Background(), failedAttemptKey(idUser, kind))
+}
+
+func (l *LockoutRepo) ClearLock(idUser string) {
+	config.RedisClient.Del(context.Background(),
+		failedAttemptKey(idUser, domain_user_auth.FailedAttemptPassword),
+		failedAttemptKey(idUser, domain_user_auth.FailedAttemptOTP),
+		lockLevelKey(idUser),
+	)
+}
+
+func (l *LockoutRepo) Status(idUser string) (*domain_user_auth.LockoutStatusResponse, error) {
+	if _, err := l.userRepo.FindUserBYID(idUser); err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	ttl, err := config.RedisClient.TTL(context.Background(), lockKey(idUser)).Result()
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	if ttl <= 0 {
+		return &domain_user_auth.LockoutStatusResponse{Locked: false}, nil
+	}
+	return &domain_user_auth.LockoutStatusResponse{
+		Locked:      true,
+		LockedUntil: time.Now().Add(ttl),
+	}, nil
+}
+
+func (l *LockoutRepo) Unlock(idUser string) error {
+	if _, err := l.userRepo.FindUserBYID(idUser); err != nil {
+		return errorenum.DataNotFound
+	}
+	if err := config.RedisClient.Del(context.Background(), lockKey(idUser)).Err(); err != nil {
+		return errorenum.SomethingError
+	}
+	l.ClearLock(idUser)
+	return nil
+}
@@ -1,9 +1,6 @@
 package auth
 
 import (
-	"fmt"
-	"math/rand"
-	"strconv"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -12,12 +9,12 @@ import (
 	domain_user_auth "xops-admin/domain/user/auth"
 	"xops-admin/helper/errorenum"
 	"xops-admin/model"
-	util_encode "xops-admin/util/encode"
 	jwttoken "xops-admin/util/token_jwt"
 )
 
 type LoginUserRepo struct {
-	loginRepo domain.UserRepository
+	loginRepo  domain.UserRepository
+	otpUseCase domain_user_auth.OtpUseCase
 }
 
 func (l *LoginUserRepo) LoginUser(email string) (*model.User, error) {
@@ -50,9 +47,15 @@ func (l *LoginUserRepo) ComparePasswordHash(user *model.User, password string) e
 }
 
 func (l *LoginUserRepo) ConvertUserToLoginResponse(user *model.User, accesToken string) domain_user_auth.LoginResponse {
+	method := user.TwoFAMethod
+	if method == "" {
+		method = domain_user_auth.TwoFAMethodEmail
+	}
 	return domain_user_auth.LoginResponse{
-		Is2fa:       user.IsTwoFA,
-		AccessToken: accesToken,
+		Is2fa:              user.IsTwoFA,
+		TwoFAMethod:        method,
+		AccessToken:        accesToken,
+		MustChangePassword: user.MustChangePassword,
 	}
 }
 
@@ -68,25 +71,17 @@ func (l *LoginUserRepo) GenerateTokenJwt(jwtTokenTime time.Duration, userID stri
 }
 
 func (l *LoginUserRepo) SendOtpVerifedCode(user *model.User) {
-	rand.Seed(time.Now().UnixNano())
-	code := 100000 + rand.Intn(900000)
-	verification_code := util_encode.Encode(strconv.Itoa(code))
-	user.VerifiedCode = verification_code
-	if err := l.loginRepo.UpdateUser(user); err != nil {
-		fmt.Println(err)
-	}
-	file := "otp_sign_in.html"
-	temp := "templates/otp_sign_in"
-	emaiData := domain.EmailData{
-		FirstName: user.Name,
-		Data:      strconv.Itoa(code),
-		Subject:   "Your OTP Code for SectorOne",
+	// user dengan authenticator app tidak perlu dikirimi OTP lewat email
+	if user.TwoFAMethod == domain_user_auth.TwoFAMethodTOTP {
+		return
 	}
-	go domain.SendEmail(user, user.Email, &emaiData, file, temp)
+	// masih cooldown berarti kode sebelumnya masih berlaku, tidak perlu kirim ulang
+	l.otpUseCase.Issue(user)
 }
 
-func NewLoginUseCase(loginRepo domain.UserRepository) domain_user_auth.LoginUseCase {
+func NewLoginUseCase(loginRepo domain.UserRepository, otpUseCase domain_user_auth.OtpUseCase) domain_user_auth.LoginUseCase {
 	return &LoginUserRepo{
-		loginRepo: loginRepo,
+		loginRepo:  loginRepo,
+		otpUseCase: otpUseCase,
 	}
 }
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+)
+
+type LogoutRepo struct {
+	sessionRepo domain.SessionRepository
+	userRepo    domain.UserRepository
+	apiKeyRepo  domain.ApiKeyRepository
+}
+
+func NewLogoutUseCase(sessionRepo domain.SessionRepository, userRepo domain.UserRepository, apiKeyRepo domain.ApiKeyRepository) domain_user_auth.LogoutUseCase {
+	return &LogoutRepo{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+func (l *LogoutRepo) Logout(accessTokenUuid string, accessTokenExpires int64, sessionID string) error {
+	if err := config.DenylistToken(accessTokenUuid, time.Until(time.Unix(accessTokenExpires, 0))); err != nil {
+		return errorenum.SomethingError
+	}
+	if sessionID != "" {
+		if err := l.sessionRepo.RevokeSession(sessionID, domain_user_auth.SessionRevokedLogout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LogoutRepo) RevokeUserTokens(idUser string) error {
+	if _, err := l.userRepo.FindUserBYID(idUser); err != nil {
+		return errorenum.DataNotFound
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	// refresh token paling lama umurnya, jadi penanda revoke cukup disimpan selama itu
+	if err := config.RevokeUserTokens(idUser, time.Now(), loadconfig.RefreshTokenExpiresIn); err != nil {
+		return errorenum.SomethingError
+	}
+	if err := l.sessionRepo.RevokeAllByUserID(idUser, domain_user_auth.SessionRevokedByAdmin); err != nil {
+		return err
+	}
+	return l.apiKeyRepo.RevokeAllByUserID(idUser)
+}
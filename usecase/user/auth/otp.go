@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+const (
+	otpTTL         = 5 * time.Minute
+	otpMaxAttempts = 5
+	otpCooldown    = time.Minute
+)
+
+// otpAttemptScript membaca hash sekaligus menaikkan attempts secara atomik, jadi key yang
+// kedaluwarsa di antara keduanya tidak dibuat ulang tanpa TTL. Mengembalikan {hash, attempts},
+// hash kosong berarti OTP tidak ada.
+var otpAttemptScript = redis.NewScript(`
+local stored = redis.call("HGET", KEYS[1], "hash")
+if not stored then
+	return {"", 0}
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+return {stored, attempts}
+`)
+
+type OtpRepo struct{}
+
+func NewOtpUseCase() domain_user_auth.OtpUseCase {
+	return &OtpRepo{}
+}
+
+func otpKey(idUser string) string {
+	return "auth:otp:" + idUser
+}
+
+func otpCooldownKey(idUser string) string {
+	return "auth:otp_cooldown:" + idUser
+}
+
+func hashOtp(idUser, code string) (string, error) {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.OtpHmacSecret == "" {
+		return "", errorenum.SomethingError
+	}
+	mac := hmac.New(sha256.New, []byte(loadconfig.OtpHmacSecret))
+	mac.Write([]byte(idUser + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func generateOtpCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func (o *OtpRepo) resendResponse(idUser string) *domain_user_auth.SendOtpResponse {
+	ttl, _ := config.RedisClient.TTL(context.Background(), otpCooldownKey(idUser)).Result()
+	if ttl < 0 {
+		ttl = 0
+	}
+	return &domain_user_auth.SendOtpResponse{
+		ResendAvailableAt:  time.Now().Add(ttl),
+		ResendAfterSeconds: int64(ttl.Round(time.Second).Seconds()),
+	}
+}
+
+func (o *OtpRepo) Issue(user *model.User) (*domain_user_auth.SendOtpResponse, error) {
+	ctx := context.Background()
+
+	// SETNX sekaligus mengunci cooldown supaya dua request bersamaan tidak sama-sama kirim
+	acquired, err := config.RedisClient.SetNX(ctx, otpCooldownKey(user.Id), 1, otpCooldown).Result()
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	if !acquired {
+		return o.resendResponse(user.Id), errorenum.OtpResendCooldown
+	}
+
+	code, err := generateOtpCode()
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	hash, err := hashOtp(user.Id, code)
+	if err != nil {
+		return nil, err
+	}
+	_, err = config.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, otpKey(user.Id))
+		pipe.HSet(ctx, otpKey(user.Id), "hash", hash, "attempts", 0)
+		pipe.Expire(ctx, otpKey(user.Id), otpTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+
+	emailData := domain.EmailData{
+		FirstName: user.Name,
+		Data:      code,
+		Subject:   "Your OTP Code for SectorOne",
+	}
+	go domain.SendEmail(user, user.Email, &emailData, "otp_sign_in.html", "templates/otp_sign_in")
+
+	return o.resendResponse(user.Id), nil
+}
+
+func (o *OtpRepo) Verify(idUser, code string) error {
+	ctx := context.Background()
+	key := otpKey(idUser)
+
+	result, err := otpAttemptScript.Run(ctx, config.RedisClient, []string{key}).Slice()
+	if err != nil || len(result) != 2 {
+		return errorenum.SomethingError
+	}
+	stored, _ := result[0].(string)
+	attempts, _ := result[1].(int64)
+	if stored == "" {
+		return errorenum.ExpiredOtp
+	}
+	if attempts > otpMaxAttempts {
+		config.RedisClient.Del(ctx, key)
+		return errorenum.OtpAttemptsExceeded
+	}
+
+	hash, err := hashOtp(idUser, code)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(hash), []byte(stored)) {
+		if attempts == otpMaxAttempts {
+			config.RedisClient.Del(ctx, key)
+			return errorenum.OtpAttemptsExceeded
+		}
+		return errorenum.FailedOtp
+	}
+
+	// hanya request yang berhasil menghapus key yang dianggap valid (sekali pakai)
+	deleted, err := config.RedisClient.Del(ctx, key).Result()
+	if err != nil || deleted == 0 {
+		return errorenum.ExpiredOtp
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_password "xops-admin/util/password"
+	util_signedtoken "xops-admin/util/signed_token"
+)
+
+const (
+	passwordResetPurpose = "password_reset"
+	passwordResetTTL     = 30 * time.Minute
+	activationPurpose    = "account_activation"
+	activationTTL        = 72 * time.Hour
+)
+
+type PasswordRepo struct {
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	apiKeyRepo  domain.ApiKeyRepository
+}
+
+func NewPasswordUseCase(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, apiKeyRepo domain.ApiKeyRepository) domain_user_auth.PasswordUseCase {
+	return &PasswordRepo{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+// hanya nonce terakhir per user yang berlaku, jadi link lama otomatis hangus
+func passwordResetKey(idUser string) string {
+	return "auth:password_reset:" + idUser
+}
+
+func activationKey(idUser string) string {
+	return "auth:activation:" + idUser
+}
+
+func (p *PasswordRepo) ForgotPassword(email string) error {
+	user, err := p.userRepo.FindUserBYEmail(email)
+	if err != nil || !user.IsVerified {
+		return nil
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return errorenum.SomethingError
+	}
+
+	token, claims, err := util_signedtoken.Generate(loadconfig.LinkSigningSecret, passwordResetPurpose, user.Id, passwordResetTTL)
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	if err := config.RedisClient.Set(context.Background(), passwordResetKey(user.Id), claims.Nonce, passwordResetTTL).Err(); err != nil {
+		return errorenum.SomethingError
+	}
+
+	emailData := domain.EmailData{
+		FirstName: user.Name,
+		Data:      loadconfig.FrontendURL + "/reset-password?token=" + url.QueryEscape(token),
+		Subject:   "Reset your SectorOne password",
+	}
+	go domain.SendEmail(user, user.Email, &emailData, "reset_password.html", "templates/reset_password")
+	return nil
+}
+
+func (p *PasswordRepo) ResetPassword(token, newPassword string) error {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return errorenum.SomethingError
+	}
+	claims, err := util_signedtoken.Parse(loadconfig.LinkSigningSecret, passwordResetPurpose, token)
+	if err != nil {
+		return errorenum.InvalidResetLink
+	}
+	if err := util_password.CheckPolicy(newPassword); err != nil {
+		return err
+	}
+
+	// GETDEL supaya link yang sama tidak bisa dipakai dua kali walau request bersamaan
+	nonce, err := config.RedisClient.GetDel(context.Background(), passwordResetKey(claims.Subject)).Result()
+	if err != nil || nonce != claims.Nonce {
+		return errorenum.InvalidResetLink
+	}
+
+	user, err := p.userRepo.FindUserBYID(claims.Subject)
+	if err != nil {
+		return errorenum.InvalidResetLink
+	}
+	return p.applyNewPassword(user, newPassword)
+}
+
+func (p *PasswordRepo) SendActivationLink(user *model.User) error {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return errorenum.SomethingError
+	}
+	token, claims, err := util_signedtoken.Generate(loadconfig.LinkSigningSecret, activationPurpose, user.Id, activationTTL)
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	if err := config.RedisClient.Set(context.Background(), activationKey(user.Id), claims.Nonce, activationTTL).Err(); err != nil {
+		return errorenum.SomethingError
+	}
+
+	emailData := domain.EmailData{
+		FirstName: user.Name,
+		Data:      loadconfig.FrontendURL + "/activate?token=" + url.QueryEscape(token),
+		Subject:   "Activate your SectorOne account",
+	}
+	go domain.SendEmail(user, user.Email, &emailData, "account_activation.html", "templates/account_activation")
+	return nil
+}
+
+func (p *PasswordRepo) ActivateAccount(token, newPassword string) error {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return errorenum.SomethingError
+	}
+	claims, err := util_signedtoken.Parse(loadconfig.LinkSigningSecret, activationPurpose, token)
+	if err != nil {
+		return errorenum.InvalidActivationLink
+	}
+	if err := util_password.CheckPolicy(newPassword); err != nil {
+		return err
+	}
+	nonce, err := config.RedisClient.GetDel(context.Background(), activationKey(claims.Subject)).Result()
+	if err != nil || nonce != claims.Nonce {
+		return errorenum.InvalidActivationLink
+	}
+
+	user, err := p.userRepo.FindUserBYID(claims.Subject)
+	if err != nil || !user.MustChangePassword {
+		return errorenum.InvalidActivationLink
+	}
+	return p.applyNewPassword(user, newPassword)
+}
+
+func (p *PasswordRepo) ChangePassword(idUser, currentPassword, newPassword string) error {
+	user, err := p.userRepo.FindUserBYID(idUser)
+	if err != nil {
+		return errorenum.Unauthorized
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return errorenum.WrongCurrentPassword
+	}
+	if currentPassword == newPassword {
+		return errorenum.SamePassword
+	}
+	if err := util_password.CheckPolicy(newPassword); err != nil {
+		return err
+	}
+	return p.applyNewPassword(user, newPassword)
+}
+
+// applyNewPassword menyimpan hash password baru lalu mencabut semua session dan token
+// yang sudah terbit, kemudian mengirim email konfirmasi
+func (p *PasswordRepo) applyNewPassword(user *model.User, newPassword string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	user.Password = string(hashed)
+	user.MustChangePassword = false
+	user.UpdatedAt = time.Now()
+	if err := p.userRepo.UpdateUser(user); err != nil {
+		return errorenum.SomethingError
+	}
+
+	loadconfig, _ := config.LoadConfig(".")
+	if err := p.sessionRepo.RevokeAllByUserID(user.Id, domain_user_auth.SessionRevokedPassword); err != nil {
+		return err
+	}
+	if err := p.apiKeyRepo.RevokeAllByUserID(user.Id); err != nil {
+		return err
+	}
+	if err := config.RevokeUserTokens(user.Id, time.Now(), loadconfig.RefreshTokenExpiresIn); err != nil {
+		return errorenum.SomethingError
+	}
+
+	emailData := domain.EmailData{
+		FirstName: user.Name,
+		Data:      time.Now().Format("02 Jan 2006 15:04 MST"),
+		Subject:   "Your SectorOne password was changed",
+	}
+	go domain.SendEmail(user, user.Email, &emailData, "password_changed.html", "templates/password_changed")
+	return nil
+}
@@ -21,14 +21,6 @@ func (r *RefreshTokenUseCase) FindUserBYID(id string) (*model.User, error) {
 	return user, nil
 }
 
-func (l *RefreshTokenUseCase) SaveRefreshToken(token string, user *model.User) (*model.User, error) {
-	user.RefreshToken = token
-	if err := l.refreshTokenRepo.UpdateUser(user); err != nil {
-		return nil, err
-	}
-	return user, nil
-}
-
 func (u *RefreshTokenUseCase) GenerateTokenJwt(jwtTokenTime time.Duration, userID string, privateKey string) (*jwttoken.TokenDetails, error) {
 	return jwttoken.GenerateTokenJwt(jwtTokenTime, userID, privateKey)
 }
@@ -4,6 +4,7 @@ import (
 	"xops-admin/domain"
 	domain_user_auth "xops-admin/domain/user/auth"
 	"xops-admin/helper/errorenum"
+	"xops-admin/model"
 )
 
 type RegisterUserRepo struct {
@@ -15,7 +16,7 @@ func (r *RegisterUserRepo) ValidateCreateUserRequest(req *domain_user_auth.Creat
 		return errorenum.InvalidEmail
 	}
 
-	if req.IdRole == 3 {
+	if req.WithClient || req.IdRole == model.RoleClient {
 		if req.LogoCompany == "" {
 			return errorenum.LogoCompanyRequired
 		}
@@ -58,7 +59,7 @@ func (r *RegisterUserRepo) CreateUser(createUser *domain_user_auth.CreateUserWit
 		Email: createUser.Email,
 	}
 
-	if createUser.IdRole == 3 {
+	if createUser.WithClient || createUser.IdRole == model.RoleClient {
 		response.CompanyLogo = createUser.LogoCompany
 		response.CompanyName = createUser.CompanyName
 		response.Domain = createUser.Domains
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	jwttoken "xops-admin/util/token_jwt"
+	util_useragent "xops-admin/util/user_agent"
+	util_uuid "xops-admin/util/uuid"
+)
+
+type SessionRepo struct {
+	sessionRepo domain.SessionRepository
+	userRepo    domain.UserRepository
+}
+
+func NewSessionUseCase(sessionRepo domain.SessionRepository, userRepo domain.UserRepository) domain_user_auth.SessionUseCase {
+	return &SessionRepo{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *SessionRepo) StartSession(user *model.User, meta domain_user_auth.SessionMeta) (*model.Session, *jwttoken.TokenDetails, error) {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil {
+		return nil, nil, errorenum.SomethingError
+	}
+
+	sessionID := util_uuid.GenerateID()
+	tokenDetails, err := jwttoken.GenerateSessionTokenJwt(loadconfig.RefreshTokenExpiresIn, user.Id, sessionID, loadconfig.RefreshTokenPrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	label := strings.TrimSpace(meta.DeviceLabel)
+	if label == "" {
+		label = util_useragent.Describe(meta.UserAgent)
+	}
+	now := time.Now()
+	session := &model.Session{
+		Id:               sessionID,
+		IdUser:           user.Id,
+		DeviceLabel:      label,
+		UserAgent:        meta.UserAgent,
+		IP:               meta.IP,
+		RefreshTokenUuid: tokenDetails.TokenUuid,
+		CreatedAt:        now,
+		LastSeenAt:       now,
+		ExpiresAt:        time.Unix(*tokenDetails.ExpiresIn, 0),
+	}
+	if err := s.sessionRepo.CreateSession(session); err != nil {
+		return nil, nil, err
+	}
+	return session, tokenDetails, nil
+}
+
+func (s *SessionRepo) RotateRefreshToken(refreshToken string, meta domain_user_auth.SessionMeta) (*model.User, *model.Session, *jwttoken.TokenDetails, error) {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil {
+		return nil, nil, nil, errorenum.SomethingError
+	}
+
+	claims, err := jwttoken.ValidateToken(refreshToken, loadconfig.RefreshTokenKeyring())
+	if err != nil {
+		return nil, nil, nil, errorenum.Unauthorized
+	}
+	if claims.SessionID == "" {
+		return nil, nil, nil, errorenum.SessionExpired
+	}
+
+	session, err := s.sessionRepo.FindSessionByID(claims.SessionID)
+	if err != nil || session.IdUser != claims.UserID || !session.IsActive() {
+		return nil, nil, nil, errorenum.SessionExpired
+	}
+
+	// token lama dipakai lagi setelah dirotasi: kemungkinan dicuri, cabut seluruh family
+	if session.RefreshTokenUuid != claims.TokenUuid {
+		s.sessionRepo.RevokeSession(session.Id, domain_user_auth.SessionRevokedReuse)
+		return nil, nil, nil, errorenum.SessionExpired
+	}
+
+	user, err := s.userRepo.FindUserBYID(claims.UserID)
+	if err != nil {
+		return nil, nil, nil, errorenum.Unauthorized
+	}
+
+	tokenDetails, err := jwttoken.GenerateSessionTokenJwt(loadconfig.RefreshTokenExpiresIn, user.Id, session.Id, loadconfig.RefreshTokenPrivateKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	session.RefreshTokenUuid = tokenDetails.TokenUuid
+	session.LastSeenAt = time.Now()
+	session.ExpiresAt = time.Unix(*tokenDetails.ExpiresIn, 0)
+	if meta.IP != "" {
+		session.IP = meta.IP
+	}
+	if meta.UserAgent != "" {
+		session.UserAgent = meta.UserAgent
+	}
+	// update bersyarat: dari dua refresh bersamaan dengan token yang sama hanya satu yang menang,
+	// yang kalah diperlakukan sebagai reuse
+	rotated, err := s.sessionRepo.RotateRefreshToken(session, claims.TokenUuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !rotated {
+		s.sessionRepo.RevokeSession(session.Id, domain_user_auth.SessionRevokedReuse)
+		return nil, nil, nil, errorenum.SessionExpired
+	}
+	return user, session, tokenDetails, nil
+}
+
+func (s *SessionRepo) ValidateSession(sessionID, tokenUuid string) (*model.Session, error) {
+	session, err := s.sessionRepo.FindSessionByID(sessionID)
+	if err != nil || !session.IsActive() || session.RefreshTokenUuid != tokenUuid {
+		return nil, errorenum.SessionExpired
+	}
+	return session, nil
+}
+
+func (s *SessionRepo) ListActiveSessions(idUser, currentSessionID string) ([]domain_user_auth.SessionResponse, error) {
+	sessions, err := s.sessionRepo.FindActiveByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	result := make([]domain_user_auth.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, domain_user_auth.SessionResponse{
+			ID:          session.Id,
+			DeviceLabel: session.DeviceLabel,
+			UserAgent:   session.UserAgent,
+			IP:          session.IP,
+			Current:     session.Id == currentSessionID,
+			CreatedAt:   session.CreatedAt,
+			LastSeenAt:  session.LastSeenAt,
+			ExpiresAt:   session.ExpiresAt,
+		})
+	}
+	return result, nil
+}
+
+func (s *SessionRepo) RevokeSession(idUser, sessionID string) error {
+	session, err := s.sessionRepo.FindSessionByID(sessionID)
+	if err != nil || session.IdUser != idUser {
+		return errorenum.DataNotFound
+	}
+	return s.sessionRepo.RevokeSession(session.Id, domain_user_auth.SessionRevokedByUser)
+}
+
+func (s *SessionRepo) RevokeAllSessions(idUser, reason string) error {
+	return s.sessionRepo.RevokeAllByUserID(idUser, reason)
+}
@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_oidc "xops-admin/util/oidc"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const ssoStateTTL = 10 * time.Minute
+
+type SsoRepo struct {
+	userRepo   domain.UserRepository
+	clientRepo domain.ClientRepository
+	memberRepo domain.ClientMemberRepository
+}
+
+func NewSsoUseCase(userRepo domain.UserRepository, clientRepo domain.ClientRepository, memberRepo domain.ClientMemberRepository) domain_user_auth.SsoUseCase {
+	return &SsoRepo{
+		userRepo:   userRepo,
+		clientRepo: clientRepo,
+		memberRepo: memberRepo,
+	}
+}
+
+func ssoStateKey(state string) string {
+	return "auth:oidc_state:" + state
+}
+
+func (s *SsoRepo) BeginLogin(ctx context.Context, clientID string) (string, error) {
+	client, err := s.clientRepo.GetClientByID(clientID)
+	if err != nil || !client.OidcEnabled {
+		return "", errorenum.SsoNotEnabled
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.OidcRedirectURL == "" {
+		return "", errorenum.SomethingError
+	}
+	metadata, err := util_oidc.Discover(ctx, client.OidcIssuer)
+	if err != nil {
+		return "", errorenum.SsoFailed
+	}
+
+	state, err := util_oidc.RandomString()
+	if err != nil {
+		return "", errorenum.SomethingError
+	}
+	verifier, err := util_oidc.RandomString()
+	if err != nil {
+		return "", errorenum.SomethingError
+	}
+	nonce, err := util_oidc.RandomString()
+	if err != nil {
+		return "", errorenum.SomethingError
+	}
+
+	raw, _ := json.Marshal(domain_user_auth.SsoState{
+		ClientID: client.Id,
+		Verifier: verifier,
+		Nonce:    nonce,
+	})
+	if err := config.RedisClient.Set(ctx, ssoStateKey(state), raw, ssoStateTTL).Err(); err != nil {
+		return "", errorenum.SomethingError
+	}
+	return util_oidc.AuthCodeURL(metadata, client.OidcClientID, loadconfig.OidcRedirectURL, state, nonce, verifier), nil
+}
+
+func (s *SsoRepo) CompleteLogin(ctx context.Context, state, code string) (*model.User, error) {
+	if state == "" || code == "" {
+		return nil, errorenum.SsoFailed
+	}
+	// state hanya bisa dipakai sekali
+	raw, err := config.RedisClient.GetDel(ctx, ssoStateKey(state)).Result()
+	if err != nil {
+		return nil, errorenum.SsoFailed
+	}
+	var stored domain_user_auth.SsoState
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+		return nil, errorenum.SsoFailed
+	}
+
+	client, err := s.clientRepo.GetClientByID(stored.ClientID)
+	if err != nil || !client.OidcEnabled {
+		return nil, errorenum.SsoNotEnabled
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return s.loginWithCode(ctx, client, &stored, code, loadconfig.OidcRedirectURL)
+}
+
+// loginWithCode menukar code dari IdP, memverifikasi id_token lalu mencari atau membuat user
+func (s *SsoRepo) loginWithCode(ctx context.Context, client *model.Client, stored *domain_user_auth.SsoState, code, redirectURI string) (*model.User, error) {
+	metadata, err := util_oidc.Discover(ctx, client.OidcIssuer)
+	if err != nil {
+		return nil, errorenum.SsoFailed
+	}
+	rawIDToken, err := util_oidc.ExchangeCode(ctx, metadata, client.OidcClientID, client.OidcClientSecret, code, redirectURI, stored.Verifier)
+	if err != nil {
+		return nil, errorenum.SsoFailed
+	}
+	claims, err := util_oidc.VerifyIDToken(ctx, metadata, client.OidcClientID, stored.Nonce, rawIDToken)
+	if err != nil {
+		return nil, errorenum.SsoFailed
+	}
+
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || !claims.EmailVerified || !emailDomainAllowed(email, client.OidcAllowedDomains) {
+		return nil, errorenum.SsoEmailNotAllowed
+	}
+
+	user, err := s.userRepo.FindUserBYEmail(email)
+	if err == nil {
+		// akun yang sudah ada hanya boleh masuk lewat SSO jika memang milik client ini,
+		// supaya IdP client tidak bisa mengambil alih akun admin/pentester
+		member, err := s.memberRepo.FindMemberByUserID(user.Id)
+		if err != nil || member.IdClient != client.Id {
+			return nil, errorenum.SsoAccountConflict
+		}
+		if !user.IsVerified {
+			return nil, errorenum.FailedLogin
+		}
+		return user, nil
+	}
+	return s.provisionUser(client, email, claims.Name)
+}
+
+func (s *SsoRepo) provisionUser(client *model.Client, email, name string) (*model.User, error) {
+	// password acak karena user SSO tidak pernah login dengan password
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(randomBytes)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	if strings.TrimSpace(name) == "" {
+		name = strings.SplitN(email, "@", 2)[0]
+	}
+
+	now := time.Now()
+	user := &model.User{
+		Id:           util_uuid.GenerateID(),
+		Name:         name,
+		Email:        email,
+		Password:     string(hashed),
+		IdRole:       model.RoleClient,
+		IsVerified:   true,
+		VerifiedCode: "-",
+		TOTPKey:      "-",
+		TwoFAMethod:  domain_user_auth.TwoFAMethodEmail,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	// user SSO baru masuk sebagai viewer, owner bisa menaikkan role-nya nanti
+	member := &model.ClientMember{
+		Id:       util_uuid.GenerateID(),
+		IdClient: client.Id,
+		IdUser:   user.Id,
+		Role:     model.ClientMemberViewer,
+	}
+	if err := s.memberRepo.CreateUserWithMember(user, member); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *SsoRepo) UpdateSsoConfig(clientID string, req *domain_user_auth.SsoConfigRequest) error {
+	client, err := s.clientRepo.GetClientByID(clientID)
+	if err != nil {
+		return errorenum.DataNotFound
+	}
+
+	domains := make([]string, 0, len(req.AllowedDomains))
+	for _, d := range req.AllowedDomains {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	issuer := strings.TrimSuffix(strings.TrimSpace(req.Issuer), "/")
+	if req.Enabled && (issuer == "" || strings.TrimSpace(req.ClientID) == "" || len(domains) == 0) {
+		return errorenum.InvalidSsoConfig
+	}
+	if req.Enabled && !strings.HasPrefix(issuer, "https://") {
+		return errorenum.InvalidSsoConfig
+	}
+
+	client.OidcEnabled = req.Enabled
+	client.OidcIssuer = issuer
+	client.OidcClientID = strings.TrimSpace(req.ClientID)
+	client.OidcAllowedDomains = strings.Join(domains, ",")
+	// secret kosong berarti tetap pakai secret lama
+	if req.ClientSecret != "" {
+		client.OidcClientSecret = req.ClientSecret
+	}
+	if err := s.clientRepo.UpdateSsoConfig(client); err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func emailDomainAllowed(email, allowedDomains string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range strings.Split(allowedDomains, ",") {
+		if strings.TrimSpace(allowed) == domain {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_oidc "xops-admin/util/oidc"
+)
+
+// fakeIdP token endpoint mengembalikan id_token yang ditandatangani dengan claims saat ini
+type fakeIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &fakeIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if util_oidc.CodeChallenge(r.Form.Get("code_verifier")) != util_oidc.CodeChallenge("verifier") {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims).SignedString(key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	idp.claims = jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"aud":            "xops",
+		"sub":            "sub-1",
+		"email":          "Alice@Acme.com",
+		"email_verified": true,
+		"name":           "Alice",
+		"nonce":          "nonce",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+	return idp
+}
+
+type fakeUserRepo struct {
+	domain.UserRepository
+	users map[string]*model.User
+}
+
+func (f *fakeUserRepo) FindUserBYEmail(email string) (*model.User, error) {
+	if user, ok := f.users[email]; ok {
+		return user, nil
+	}
+	return nil, errorenum.DataNotFound
+}
+
+type fakeMemberRepo struct {
+	domain.ClientMemberRepository
+	members map[string]*model.ClientMember
+	created []*model.User
+}
+
+func (f *fakeMemberRepo) FindMemberByUserID(idUser string) (*model.ClientMember, error) {
+	if member, ok := f.members[idUser]; ok {
+		return member, nil
+	}
+	return nil, errorenum.DataNotFound
+}
+
+func (f *fakeMemberRepo) CreateUserWithMember(user *model.User, member *model.ClientMember) error {
+	f.created = append(f.created, user)
+	f.members[user.Id] = member
+	return nil
+}
+
+func newSsoFixture(t *testing.T) (*SsoRepo, *fakeIdP, *model.Client, *fakeUserRepo, *fakeMemberRepo) {
+	idp := newFakeIdP(t)
+	client := &model.Client{
+		Id:                 "client-1",
+		OidcEnabled:        true,
+		OidcIssuer:         idp.server.URL,
+		OidcClientID:       "xops",
+		OidcAllowedDomains: "acme.com",
+	}
+	users := &fakeUserRepo{users: map[string]*model.User{}}
+	members := &fakeMemberRepo{members: map[string]*model.ClientMember{}}
+	return &SsoRepo{userRepo: users, memberRepo: members}, idp, client, users, members
+}
+
+func ssoLogin(s *SsoRepo, client *model.Client) (*model.User, error) {
+	stored := &domain_user_auth.SsoState{ClientID: client.Id, Verifier: "verifier", Nonce: "nonce"}
+	return s.loginWithCode(context.Background(), client, stored, "code", "https://app.example.com/cb")
+}
+
+func TestSsoProvisionsNewUser(t *testing.T) {
+	s, _, client, _, members := newSsoFixture(t)
+
+	user, err := ssoLogin(s, client)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if user.Email != "alice@acme.com" || user.IdRole != model.RoleClient || !user.IsVerified {
+		t.Errorf("unexpected user %+v", user)
+	}
+	if len(members.created) != 1 {
+		t.Fatalf("created %d users, want 1", len(members.created))
+	}
+	member := members.members[user.Id]
+	if member.IdClient != client.Id || member.Role != model.ClientMemberViewer {
+		t.Errorf("unexpected membership %+v", member)
+	}
+}
+
+func TestSsoExistingMemberLogsIn(t *testing.T) {
+	s, _, client, users, members := newSsoFixture(t)
+	existing := &model.User{Id: "user-1", Email: "alice@acme.com", IsVerified: true}
+	users.users[existing.Email] = existing
+	members.members[existing.Id] = &model.ClientMember{IdClient: client.Id, IdUser: existing.Id}
+
+	user, err := ssoLogin(s, client)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if user != existing || len(members.created) != 0 {
+		t.Error("existing member should log in without provisioning")
+	}
+}
+
+func TestSsoRejectsAccountOfOtherClient(t *testing.T) {
+	s, _, client, users, members := newSsoFixture(t)
+	admin := &model.User{Id: "admin-1", Email: "alice@acme.com", IsVerified: true}
+	users.users[admin.Email] = admin
+
+	if _, err := ssoLogin(s, client); !errors.Is(err, errorenum.SsoAccountConflict) {
+		t.Fatalf("user without membership: err = %v", err)
+	}
+	members.members[admin.Id] = &model.ClientMember{IdClient: "client-2", IdUser: admin.Id}
+	if _, err := ssoLogin(s, client); !errors.Is(err, errorenum.SsoAccountConflict) {
+		t.Fatalf("member of other client: err = %v", err)
+	}
+}
+
+func TestSsoRejectsInvalidIdentity(t *testing.T) {
+	cases := map[string]struct {
+		mutate func(c jwt.MapClaims)
+		want   error
+	}{
+		"domain not allowed": {func(c jwt.MapClaims) { c["email"] = "alice@evil.com" }, errorenum.SsoEmailNotAllowed},
+		"email unverified":   {func(c jwt.MapClaims) { c["email_verified"] = false }, errorenum.SsoEmailNotAllowed},
+		"nonce mismatch":     {func(c jwt.MapClaims) { c["nonce"] = "replayed" }, errorenum.SsoFailed},
+		"wrong audience":     {func(c jwt.MapClaims) { c["aud"] = "other" }, errorenum.SsoFailed},
+		"expired":            {func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }, errorenum.SsoFailed},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, idp, client, _, members := newSsoFixture(t)
+			tc.mutate(idp.claims)
+			if _, err := ssoLogin(s, client); !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if len(members.created) != 0 {
+				t.Error("user provisioned for rejected identity")
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const (
+	totpIssuer        = "SectorOne"
+	recoveryCodeCount = 10
+	totpPeriod        = 30
+)
+
+type TwoFactorRepo struct {
+	userRepo     domain.UserRepository
+	recoveryRepo domain.RecoveryCodeRepository
+}
+
+func NewTwoFactorUseCase(userRepo domain.UserRepository, recoveryRepo domain.RecoveryCodeRepository) domain_user_auth.TwoFactorUseCase {
+	return &TwoFactorRepo{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+	}
+}
+
+func (t *TwoFactorRepo) FindUserBYID(id string) (*model.User, error) {
+	return t.userRepo.FindUserBYID(id)
+}
+
+func (t *TwoFactorRepo) Status(user *model.User) (*domain_user_auth.TwoFactorStatusResponse, error) {
+	method := user.TwoFAMethod
+	if method == "" {
+		method = domain_user_auth.TwoFAMethodEmail
+	}
+	remaining := 0
+	if method == domain_user_auth.TwoFAMethodTOTP {
+		codes, err := t.recoveryRepo.FindUnusedByUserID(user.Id)
+		if err != nil {
+			return nil, errorenum.SomethingError
+		}
+		remaining = len(codes)
+	}
+	return &domain_user_auth.TwoFactorStatusResponse{
+		Method:                 method,
+		IsTwoFA:                user.IsTwoFA,
+		PendingEnrollment:      user.TOTPPendingKey != "",
+		RemainingRecoveryCodes: remaining,
+	}, nil
+}
+
+// EnrollTOTP membuat secret baru yang belum aktif sampai dikonfirmasi lewat ConfirmTOTP
+func (t *TwoFactorRepo) EnrollTOTP(user *model.User) (*domain_user_auth.TOTPEnrollResponse, error) {
+	if user.TwoFAMethod == domain_user_auth.TwoFAMethodTOTP {
+		return nil, errorenum.TOTPAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, errorenum.SomethingError
+	}
+
+	user.TOTPPendingKey = key.Secret()
+	if err := t.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	return &domain_user_auth.TOTPEnrollResponse{
+		Secret:     key.Secret(),
+		OtpauthURL: key.URL(),
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// ConfirmTOTP mengaktifkan TOTP setelah kode pertama valid dan mengembalikan recovery code sekali tampil
+func (t *TwoFactorRepo) ConfirmTOTP(user *model.User, code string) (*domain_user_auth.TOTPConfirmResponse, error) {
+	if user.TOTPPendingKey == "" {
+		return nil, errorenum.TOTPNotEnrolled
+	}
+	accepted, err := t.acceptTOTP(user, user.TOTPPendingKey, code)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, errorenum.FailedOtp
+	}
+
+	plainCodes, hashedCodes, err := t.generateRecoveryCodes(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.recoveryRepo.ReplaceRecoveryCodes(user.Id, hashedCodes); err != nil {
+		return nil, errorenum.SomethingError
+	}
+
+	user.TOTPKey = user.TOTPPendingKey
+	user.TOTPPendingKey = ""
+	user.TwoFAMethod = domain_user_auth.TwoFAMethodTOTP
+	user.IsTwoFA = true
+	if err := t.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	return &domain_user_auth.TOTPConfirmResponse{
+		RecoveryCodes: plainCodes,
+	}, nil
+}
+
+func (t *TwoFactorRepo) DisableTOTP(user *model.User, password, code string) error {
+	if user.TwoFAMethod != domain_user_auth.TwoFAMethodTOTP {
+		return errorenum.TOTPNotEnabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errorenum.FailedLogin
+	}
+	if err := t.VerifySecondFactor(user, code); err != nil {
+		return err
+	}
+
+	if err := t.recoveryRepo.DeleteByUserID(user.Id); err != nil {
+		return errorenum.SomethingError
+	}
+	user.TOTPKey = "-"
+	user.TOTPPendingKey = ""
+	user.TwoFAMethod = domain_user_auth.TwoFAMethodEmail
+	return t.userRepo.UpdateUser(user)
+}
+
+func (t *TwoFactorRepo) VerifySecondFactor(user *model.User, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return errorenum.CodeVerifiedNull
+	}
+	accepted, err := t.acceptTOTP(user, user.TOTPKey, code)
+	if err != nil {
+		return err
+	}
+	if accepted {
+		return nil
+	}
+
+	codes, err := t.recoveryRepo.FindUnusedByUserID(user.Id)
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	normalized := normalizeRecoveryCode(code)
+	for _, recovery := range codes {
+		if bcrypt.CompareHashAndPassword([]byte(recovery.CodeHash), []byte(normalized)) == nil {
+			if err := t.recoveryRepo.MarkUsed(recovery.Id); err != nil {
+				return errorenum.FailedOtp
+			}
+			return nil
+		}
+	}
+	return errorenum.FailedOtp
+}
+
+// acceptTOTP menerima kode sekali per time-step, step disimpan lewat update bersyarat
+// supaya request paralel dengan kode yang sama tidak lolos dua kali
+func (t *TwoFactorRepo) acceptTOTP(user *model.User, secret, code string) (bool, error) {
+	step, ok := matchTOTPStep(strings.TrimSpace(code), secret, time.Now())
+	if !ok || step <= user.TOTPLastStep {
+		return false, nil
+	}
+	advanced, err := t.userRepo.AdvanceTOTPStep(user.Id, step)
+	if err != nil {
+		return false, errorenum.SomethingError
+	}
+	if !advanced {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+// matchTOTPStep time-step yang cocok dengan kode, toleransi satu step seperti totp.Validate
+func matchTOTPStep(code, secret string, now time.Time) (int64, bool) {
+	if code == "" || secret == "" {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current - 1, current, current + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func (t *TwoFactorRepo) generateRecoveryCodes(idUser string) ([]string, []model.RecoveryCode, error) {
+	plainCodes := make([]string, 0, recoveryCodeCount)
+	hashedCodes := make([]model.RecoveryCode, 0, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 6)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, errorenum.SomethingError
+		}
+		code := strings.ToLower(encoding.EncodeToString(raw))
+		code = code[:5] + "-" + code[5:]
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(code)), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, nil, errorenum.SomethingError
+		}
+		plainCodes = append(plainCodes, code)
+		hashedCodes = append(hashedCodes, model.RecoveryCode{
+			Id:        util_uuid.GenerateID(),
+			IdUser:    idUser,
+			CodeHash:  string(hash),
+			CreatedAt: time.Now(),
+		})
+	}
+	return plainCodes, hashedCodes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
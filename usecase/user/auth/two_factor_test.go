@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+// fakeStepRepo meniru update bersyarat totp_last_step di postgres
+type fakeStepRepo struct {
+	domain.UserRepository
+	mu    sync.Mutex
+	steps map[string]int64
+}
+
+func (f *fakeStepRepo) AdvanceTOTPStep(id string, step int64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.steps[id] >= step {
+		return false, nil
+	}
+	f.steps[id] = step
+	return true, nil
+}
+
+type emptyRecoveryRepo struct {
+	domain.RecoveryCodeRepository
+}
+
+func (emptyRecoveryRepo) FindUnusedByUserID(idUser string) ([]model.RecoveryCode, error) {
+	return nil, nil
+}
+
+func newTOTPUser(t *testing.T) (*model.User, string) {
+	t.Helper()
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: totpIssuer, AccountName: "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &model.User{Id: "user-1", TOTPKey: key.Secret(), TwoFAMethod: domain_user_auth.TwoFAMethodTOTP}, key.Secret()
+}
+
+func TestVerifySecondFactorRejectsReplayedCode(t *testing.T) {
+	user, secret := newTOTPUser(t)
+	repo := &fakeStepRepo{steps: map[string]int64{}}
+	twoFactor := &TwoFactorRepo{userRepo: repo, recoveryRepo: emptyRecoveryRepo{}}
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := twoFactor.VerifySecondFactor(user, code); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := twoFactor.VerifySecondFactor(user, code); !errors.Is(err, errorenum.FailedOtp) {
+		t.Fatalf("replay: err = %v, want FailedOtp", err)
+	}
+
+	// user dimuat ulang dari database (TOTPLastStep masih lama) tetap ditolak oleh update bersyarat
+	stale := *user
+	stale.TOTPLastStep = 0
+	if err := twoFactor.VerifySecondFactor(&stale, code); !errors.Is(err, errorenum.FailedOtp) {
+		t.Fatalf("replay with stale user: err = %v, want FailedOtp", err)
+	}
+
+	// kode dari step sebelumnya masih dalam toleransi waktu tapi sudah lebih lama dari step yang diterima
+	previous, _ := totp.GenerateCode(secret, time.Now().Add(-totpPeriod*time.Second))
+	if err := twoFactor.VerifySecondFactor(user, previous); !errors.Is(err, errorenum.FailedOtp) {
+		t.Fatalf("older step: err = %v, want FailedOtp", err)
+	}
+}
+
+func TestVerifySecondFactorConcurrentReplay(t *testing.T) {
+	user, secret := newTOTPUser(t)
+	repo := &fakeStepRepo{steps: map[string]int64{}}
+	twoFactor := &TwoFactorRepo{userRepo: repo, recoveryRepo: emptyRecoveryRepo{}}
+	code, _ := totp.GenerateCode(secret, time.Now())
+
+	var wg sync.WaitGroup
+	results := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			copied := *user
+			results <- twoFactor.VerifySecondFactor(&copied, code)
+		}()
+	}
+	wg.Wait()
+	close(results)
+	accepted := 0
+	for err := range results {
+		if err == nil {
+			accepted++
+		}
+	}
+	if accepted != 1 {
+		t.Fatalf("code accepted %d times, want 1", accepted)
+	}
+}
+
+func TestMatchTOTPStep(t *testing.T) {
+	_, secret := newTOTPUser(t)
+	now := time.Unix(1700000000, 0)
+	current := now.Unix() / totpPeriod
+	for offset := int64(-2); offset <= 2; offset++ {
+		code, _ := totp.GenerateCode(secret, time.Unix((current+offset)*totpPeriod, 0))
+		step, ok := matchTOTPStep(code, secret, now)
+		inWindow := offset >= -1 && offset <= 1
+		if ok != inWindow || (ok && step != current+offset) {
+			t.Errorf("offset %d: step %d ok %v", offset, step, ok)
+		}
+	}
+}
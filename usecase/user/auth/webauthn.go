@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_uuid "xops-admin/util/uuid"
+	util_webauthn "xops-admin/util/webauthn"
+)
+
+const (
+	webauthnChallengeTTL = 5 * time.Minute
+	webauthnTimeoutMs    = 300000
+	webauthnCreate       = "webauthn.create"
+	webauthnGet          = "webauthn.get"
+)
+
+type WebauthnRepo struct {
+	credentialRepo domain.WebauthnCredentialRepository
+	userRepo       domain.UserRepository
+	clientRepo     domain.ClientRepository
+}
+
+func NewWebauthnUseCase(credentialRepo domain.WebauthnCredentialRepository, userRepo domain.UserRepository, clientRepo domain.ClientRepository) domain_user_auth.WebauthnUseCase {
+	return &WebauthnRepo{
+		credentialRepo: credentialRepo,
+		userRepo:       userRepo,
+		clientRepo:     clientRepo,
+	}
+}
+
+// challenge disimpan per user dan per ceremony, hanya bisa dipakai sekali
+func webauthnChallengeKey(ceremony, idUser string) string {
+	return "auth:webauthn:" + ceremony + ":" + idUser
+}
+
+func (w *WebauthnRepo) storeChallenge(ceremony, idUser string) (string, error) {
+	challenge, err := util_webauthn.NewChallenge()
+	if err != nil {
+		return "", errorenum.SomethingError
+	}
+	if err := config.RedisClient.Set(context.Background(), webauthnChallengeKey(ceremony, idUser), challenge, webauthnChallengeTTL).Err(); err != nil {
+		return "", errorenum.SomethingError
+	}
+	return challenge, nil
+}
+
+func (w *WebauthnRepo) consumeChallenge(ceremony, idUser string) (string, error) {
+	challenge, err := config.RedisClient.GetDel(context.Background(), webauthnChallengeKey(ceremony, idUser)).Result()
+	if err != nil || challenge == "" {
+		return "", errorenum.InvalidPasskey
+	}
+	return challenge, nil
+}
+
+func (w *WebauthnRepo) descriptors(idUser string) ([]domain_user_auth.WebauthnCredentialDescriptor, error) {
+	credentials, err := w.credentialRepo.FindCredentialsByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	result := make([]domain_user_auth.WebauthnCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		result = append(result, domain_user_auth.WebauthnCredentialDescriptor{
+			Type: "public-key",
+			ID:   credential.CredentialID,
+		})
+	}
+	return result, nil
+}
+
+func (w *WebauthnRepo) BeginRegistration(idUser string) (*domain_user_auth.WebauthnCreationOptions, error) {
+	user, err := w.userRepo.FindUserBYID(idUser)
+	if err != nil {
+		return nil, err
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.WebauthnRPID == "" {
+		return nil, errorenum.SomethingError
+	}
+	challenge, err := w.storeChallenge("register", user.Id)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := w.descriptors(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain_user_auth.WebauthnCreationOptions{
+		Challenge: challenge,
+		RP: domain_user_auth.WebauthnRelyingParty{
+			ID:   loadconfig.WebauthnRPID,
+			Name: loadconfig.WebauthnRPName,
+		},
+		User: domain_user_auth.WebauthnUserEntity{
+			ID:          util_webauthn.EncodeBase64URL([]byte(user.Id)),
+			Name:        user.Email,
+			DisplayName: user.Name,
+		},
+		PubKeyCredParams: []domain_user_auth.WebauthnCredentialParam{
+			{Type: "public-key", Alg: util_webauthn.AlgES256},
+			{Type: "public-key", Alg: util_webauthn.AlgEdDSA},
+			{Type: "public-key", Alg: util_webauthn.AlgRS256},
+		},
+		Timeout:            webauthnTimeoutMs,
+		Attestation:        "none",
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: domain_user_auth.WebauthnAuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: "preferred",
+		},
+	}, nil
+}
+
+func (w *WebauthnRepo) FinishRegistration(idUser string, req *domain_user_auth.WebauthnRegisterRequest) (*domain_user_auth.WebauthnCredentialResponse, error) {
+	user, err := w.userRepo.FindUserBYID(idUser)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := w.consumeChallenge("register", user.Id)
+	if err != nil {
+		return nil, err
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	if req.Type != "public-key" {
+		return nil, errorenum.InvalidPasskey
+	}
+	clientDataJSON, err := util_webauthn.DecodeBase64URL(req.Response.ClientDataJSON)
+	if err != nil {
+		return nil, errorenum.InvalidPasskey
+	}
+	attestationObject, err := util_webauthn.DecodeBase64URL(req.Response.AttestationObject)
+	if err != nil {
+		return nil, errorenum.InvalidPasskey
+	}
+	if err := util_webauthn.VerifyClientData(clientDataJSON, webauthnCreate, challenge, strings.Split(loadconfig.WebauthnOrigins, ",")); err != nil {
+		return nil, errorenum.InvalidPasskey
+	}
+	authData, err := util_webauthn.ParseAttestationObject(attestationObject, loadconfig.WebauthnRPID)
+	if err != nil {
+		return nil, errorenum.InvalidPasskey
+	}
+
+	credentialID := util_webauthn.EncodeBase64URL(authData.CredentialID)
+	if _, err := w.credentialRepo.FindCredentialByCredentialID(credentialID); err == nil {
+		return nil, errorenum.DuplicatePasskey
+	}
+	credential := &model.WebauthnCredential{
+		Id:           util_uuid.GenerateID(),
+		IdUser:       user.Id,
+		CredentialID: credentialID,
+		PublicKey:    authData.PublicKey,
+		Algorithm:    authData.Algorithm,
+		SignCount:    authData.SignCount,
+		Name:         strings.TrimSpace(req.Name),
+		CreatedAt:    time.Now(),
+	}
+	if err := w.credentialRepo.CreateCredential(credential); err != nil {
+		return nil, errorenum.SomethingError
+	}
+
+	// passkey pertama memenuhi kewajiban dari client
+	if user.MustRegisterPasskey {
+		user.MustRegisterPasskey = false
+		if err := w.userRepo.UpdateUser(user); err != nil {
+			return nil, err
+		}
+	}
+	response := domain_user_auth.ConvertWebauthnCredential(credential)
+	return &response, nil
+}
+
+func (w *WebauthnRepo) BeginAssertion(user *model.User) (*domain_user_auth.WebauthnRequestOptions, error) {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.WebauthnRPID == "" {
+		return nil, errorenum.SomethingError
+	}
+	allow, err := w.descriptors(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(allow) == 0 {
+		return nil, errorenum.InvalidPasskey
+	}
+	challenge, err := w.storeChallenge("login", user.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain_user_auth.WebauthnRequestOptions{
+		Challenge:        challenge,
+		RPID:             loadconfig.WebauthnRPID,
+		Timeout:          webauthnTimeoutMs,
+		AllowCredentials: allow,
+		UserVerification: "preferred",
+	}, nil
+}
+
+func (w *WebauthnRepo) VerifyAssertion(user *model.User, assertion *domain_user_auth.WebauthnAssertion) error {
+	challenge, err := w.consumeChallenge("login", user.Id)
+	if err != nil {
+		return err
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	if assertion.Type != "public-key" {
+		return errorenum.InvalidPasskey
+	}
+	credential, err := w.credentialRepo.FindCredentialByCredentialID(strings.TrimRight(assertion.ID, "="))
+	if err != nil || credential.IdUser != user.Id {
+		return errorenum.InvalidPasskey
+	}
+
+	clientDataJSON, err := util_webauthn.DecodeBase64URL(assertion.Response.ClientDataJSON)
+	if err != nil {
+		return errorenum.InvalidPasskey
+	}
+	rawAuthData, err := util_webauthn.DecodeBase64URL(assertion.Response.AuthenticatorData)
+	if err != nil {
+		return errorenum.InvalidPasskey
+	}
+	signature, err := util_webauthn.DecodeBase64URL(assertion.Response.Signature)
+	if err != nil {
+		return errorenum.InvalidPasskey
+	}
+	if err := util_webauthn.VerifyClientData(clientDataJSON, webauthnGet, challenge, strings.Split(loadconfig.WebauthnOrigins, ",")); err != nil {
+		return errorenum.InvalidPasskey
+	}
+	authData, err := util_webauthn.ParseAuthenticatorData(rawAuthData, loadconfig.WebauthnRPID)
+	if err != nil {
+		return errorenum.InvalidPasskey
+	}
+	if err := util_webauthn.VerifySignature(credential.PublicKey, credential.Algorithm, rawAuthData, clientDataJSON, signature); err != nil {
+		return errorenum.InvalidPasskey
+	}
+	if err := util_webauthn.CheckSignCount(credential.SignCount, authData.SignCount); err != nil {
+		return errorenum.InvalidPasskey
+	}
+	return w.credentialRepo.TouchCredential(credential.Id, authData.SignCount, time.Now())
+}
+
+func (w *WebauthnRepo) ListCredentials(idUser string) ([]domain_user_auth.WebauthnCredentialResponse, error) {
+	credentials, err := w.credentialRepo.FindCredentialsByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	result := make([]domain_user_auth.WebauthnCredentialResponse, 0, len(credentials))
+	for i := range credentials {
+		result = append(result, domain_user_auth.ConvertWebauthnCredential(&credentials[i]))
+	}
+	return result, nil
+}
+
+func (w *WebauthnRepo) RenameCredential(idUser, id, name string) error {
+	return w.credentialRepo.RenameCredential(idUser, id, strings.TrimSpace(name))
+}
+
+func (w *WebauthnRepo) DeleteCredential(idUser, id string) error {
+	user, err := w.userRepo.FindUserBYID(idUser)
+	if err != nil {
+		return err
+	}
+	// passkey terakhir tidak boleh dihapus selama client mewajibkannya
+	if w.PasskeyRequired(user) {
+		count, err := w.credentialRepo.CountCredentialsByUserID(idUser)
+		if err != nil {
+			return errorenum.SomethingError
+		}
+		if count <= 1 {
+			return errorenum.LastPasskeyRequired
+		}
+	}
+	return w.credentialRepo.DeleteCredential(idUser, id)
+}
+
+func (w *WebauthnRepo) PasskeyRequired(user *model.User) bool {
+	required, err := w.clientRepo.PasskeyRequiredForUser(user)
+	return err == nil && required
+}
+
+func (w *WebauthnRepo) HasCredentials(idUser string) bool {
+	count, err := w.credentialRepo.CountCredentialsByUserID(idUser)
+	return err == nil && count > 0
+}
+
+func (w *WebauthnRepo) SetPasskeyPolicy(clientID string, required bool) error {
+	return w.clientRepo.SetRequirePasskey(clientID, required)
+}
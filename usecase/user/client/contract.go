@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"xops-admin/domain"
+	domain_client "xops-admin/domain/user/client"
+	domain_overview "xops-admin/domain/user/overview"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+const contractReminderInterval = time.Hour
+
+// pengingat dikirim saat sisa kontrak mencapai salah satu batas ini, terbesar dulu
+var contractReminderDays = []int{14, 3}
+
+type contractWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+func parseContractWindow(startDate, endDate string) (*contractWindow, error) {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, errorenum.InvalidContractWindow
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil || end.Before(start) {
+		return nil, errorenum.InvalidContractWindow
+	}
+	return &contractWindow{start: start, end: end}, nil
+}
+
+func (c *ClientUserRepo) UpdateContractWindow(id string, req *domain_client.ContractWindowRequest) (*model.Client, error) {
+	window, err := parseContractWindow(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.clientRepo.GetClientByID(id); err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	if err := c.clientRepo.UpdateContractWindow(id, window.start, window.end); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return c.clientRepo.GetClientByID(id)
+}
+
+// RunContractReminders mengirim pengingat kontrak tiap jam sampai ctx selesai.
+// Aman dijalankan di banyak instance, lihat MarkContractReminder.
+func (c *ClientUserRepo) RunContractReminders(ctx context.Context) {
+	ticker := time.NewTicker(contractReminderInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.SendContractReminders(time.Now()); err != nil {
+			log.Printf("contract reminder: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// contractToday tanggal hari ini di zona chart, disimpan sebagai tengah malam UTC seperti
+// StartDate / EndDate supaya selisihnya selalu kelipatan 24 jam
+func contractToday(now time.Time) time.Time {
+	local := now.In(domain_overview.ChartLocation())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// contractDaysLeft jumlah hari kalender dari today sampai hari terakhir kontrak, 0 = hari ini
+func contractDaysLeft(end, today time.Time) int {
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return int(last.Sub(today) / (24 * time.Hour))
+}
+
+func contractReminderSubject(daysLeft int) string {
+	switch daysLeft {
+	case 0:
+		return "Your SectorOne contract ends today"
+	case 1:
+		return "Your SectorOne contract ends tomorrow"
+	}
+	return fmt.Sprintf("Your SectorOne contract ends in %d days", daysLeft)
+}
+
+func (c *ClientUserRepo) SendContractReminders(now time.Time) error {
+	today := contractToday(now)
+	clients, err := c.clientRepo.FindClientsEndingBetween(today, today.AddDate(0, 0, contractReminderDays[0]+1))
+	if err != nil {
+		return err
+	}
+
+	for i := range clients {
+		client := &clients[i]
+		daysLeft := contractDaysLeft(client.EndDate, today)
+		threshold := 0
+		for _, days := range contractReminderDays {
+			if daysLeft <= days {
+				threshold = days
+			}
+		}
+		// sudah dikirim untuk batas ini atau yang lebih dekat
+		if threshold == 0 || (client.ContractReminderDays != 0 && client.ContractReminderDays <= threshold) {
+			continue
+		}
+		marked, err := c.clientRepo.MarkContractReminder(client.Id, client.ContractReminderDays, threshold)
+		if err != nil {
+			log.Printf("contract reminder: failed to mark client %s: %v", client.Id, err)
+			continue
+		}
+		if !marked {
+			continue
+		}
+		c.sendContractReminder(client, daysLeft)
+	}
+	return nil
+}
+
+func (c *ClientUserRepo) sendContractReminder(client *model.Client, daysLeft int) {
+	members, err := c.memberRepo.FindMembersByClientID(client.Id)
+	if err != nil {
+		log.Printf("contract reminder: failed to load members of client %s: %v", client.Id, err)
+		return
+	}
+	for _, member := range members {
+		if member.Role != model.ClientMemberOwner || member.User == nil {
+			continue
+		}
+		emailData := domain.EmailData{
+			FirstName: member.User.Name,
+			Subject:   contractReminderSubject(daysLeft),
+			Contract: &domain.EmailContract{
+				Company:  client.CompanyName,
+				EndDate:  client.EndDate.Format("02 Jan 2006"),
+				DaysLeft: daysLeft,
+			},
+		}
+		go domain.SendEmail(member.User, member.User.Email, &emailData, "contract_expiry.html", "templates/contract_expiry")
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+// sisa hari dihitung per tanggal kalender di zona chart, bukan dari jam yang tersisa
+func TestContractDaysLeft(t *testing.T) {
+	end := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"last day", time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC), 0},
+		{"last day in WIB, still the day before in UTC", time.Date(2026, 3, 9, 17, 30, 0, 0, time.UTC), 0},
+		{"day before", time.Date(2026, 3, 9, 1, 0, 0, 0, time.UTC), 1},
+		{"almost four days", time.Date(2026, 3, 6, 1, 0, 0, 0, time.UTC), 4},
+	}
+	for _, tt := range tests {
+		if got := contractDaysLeft(end, contractToday(tt.now)); got != tt.want {
+			t.Errorf("%s: daysLeft = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if subject := contractReminderSubject(0); subject != "Your SectorOne contract ends today" {
+		t.Errorf("final day subject = %q", subject)
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"strings"
+	"time"
+
+	"xops-admin/domain"
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_uuid "xops-admin/util/uuid"
+)
+
+type EngagementRepo struct {
+	engagementRepo domain.EngagementRepository
+	clientRepo     domain.ClientRepository
+	userRepo       domain.UserRepository
+}
+
+func NewEngagementUseCase(engagementRepo domain.EngagementRepository, clientRepo domain.ClientRepository, userRepo domain.UserRepository) domain_client.EngagementUseCase {
+	return &EngagementRepo{
+		engagementRepo: engagementRepo,
+		clientRepo:     clientRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (e *EngagementRepo) ListClientEngagements(idUser string) ([]domain_client.EngagementResponse, error) {
+	client, err := e.clientRepo.GetClientByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.NotClientMember
+	}
+	return e.ListEngagements(client.Id)
+}
+
+func (e *EngagementRepo) ListEngagements(idClient string) ([]domain_client.EngagementResponse, error) {
+	engagements, err := e.engagementRepo.FindEngagementsByClientID(idClient)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	result := make([]domain_client.EngagementResponse, 0, len(engagements))
+	for i := range engagements {
+		result = append(result, *toEngagementResponse(&engagements[i]))
+	}
+	return result, nil
+}
+
+func (e *EngagementRepo) GetEngagement(id string) (*domain_client.EngagementResponse, error) {
+	engagement, err := e.engagementRepo.FindEngagementByID(id)
+	if err != nil {
+		return nil, errorenum.EngagementNotFound
+	}
+	return toEngagementResponse(engagement), nil
+}
+
+func (e *EngagementRepo) CreateEngagement(idClient string, req *domain_client.EngagementRequest) (*domain_client.EngagementResponse, error) {
+	client, err := e.clientRepo.GetClientByID(idClient)
+	if err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	now := time.Now()
+	engagement := &model.Engagement{
+		Id:        util_uuid.GenerateID(),
+		IdClient:  client.Id,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := e.applyEngagementRequest(client, engagement, req); err != nil {
+		return nil, err
+	}
+	if err := e.engagementRepo.CreateEngagement(engagement); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return e.GetEngagement(engagement.Id)
+}
+
+func (e *EngagementRepo) UpdateEngagement(id string, req *domain_client.EngagementRequest) (*domain_client.EngagementResponse, error) {
+	engagement, err := e.engagementRepo.FindEngagementByID(id)
+	if err != nil {
+		return nil, errorenum.EngagementNotFound
+	}
+	client, err := e.clientRepo.GetClientByID(engagement.IdClient)
+	if err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	if err := e.applyEngagementRequest(client, engagement, req); err != nil {
+		return nil, err
+	}
+	if err := e.engagementRepo.UpdateEngagement(engagement); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return e.GetEngagement(engagement.Id)
+}
+
+func (e *EngagementRepo) DeleteEngagement(id string) error {
+	if err := e.engagementRepo.DeleteEngagement(id); err != nil {
+		if err == errorenum.DataNotFound {
+			return errorenum.EngagementNotFound
+		}
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+// applyEngagementRequest memvalidasi request terhadap kontrak dan domain client lalu mengisi engagement
+func (e *EngagementRepo) applyEngagementRequest(client *model.Client, engagement *model.Engagement, req *domain_client.EngagementRequest) error {
+	window, err := parseContractWindow(req.StartDate, req.EndDate)
+	if err != nil {
+		return errorenum.InvalidEngagement
+	}
+	if window.start.Before(client.StartDate) || (!client.EndDate.IsZero() && window.end.After(client.EndDate)) {
+		return errorenum.InvalidEngagement
+	}
+
+	owned := make(map[string]string, len(client.DomainClient))
+	for _, d := range client.DomainClient {
+		owned[strings.ToLower(d.Domain)] = d.Domain
+	}
+	domains := make([]model.EngagementDomain, 0, len(req.Domains))
+	seen := map[string]bool{}
+	for _, name := range req.Domains {
+		key := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+		domainName, ok := owned[key]
+		if !ok {
+			return errorenum.EngagementDomainNotAllowed
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		domains = append(domains, model.EngagementDomain{Id: util_uuid.GenerateID(), IdEngagement: engagement.Id, Domain: domainName})
+	}
+
+	patterns := make([]model.EngagementUrlPattern, 0, len(req.UrlPatterns))
+	seen = map[string]bool{}
+	for _, pattern := range req.UrlPatterns {
+		pattern = strings.TrimSpace(pattern)
+		lower := strings.ToLower(pattern)
+		if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+			return errorenum.InvalidUrlPattern
+		}
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		patterns = append(patterns, model.EngagementUrlPattern{Id: util_uuid.GenerateID(), IdEngagement: engagement.Id, Pattern: pattern})
+	}
+
+	pentesters := make([]model.User, 0, len(req.Pentesters))
+	seen = map[string]bool{}
+	for _, idUser := range req.Pentesters {
+		if seen[idUser] {
+			continue
+		}
+		seen[idUser] = true
+		user, err := e.userRepo.FindUserBYID(idUser)
+		if err != nil {
+			return errorenum.InvalidPentester
+		}
+		pentesters = append(pentesters, *user)
+	}
+
+	engagement.Name = strings.TrimSpace(req.Name)
+	engagement.StartDate = window.start
+	engagement.EndDate = window.end
+	engagement.Domains = domains
+	engagement.UrlPatterns = patterns
+	engagement.Pentesters = pentesters
+	return nil
+}
+
+func toEngagementResponse(engagement *model.Engagement) *domain_client.EngagementResponse {
+	response := &domain_client.EngagementResponse{
+		Engagement: *engagement,
+		Pentesters: make([]domain_client.EngagementPentester, 0, len(engagement.Pentesters)),
+	}
+	for _, user := range engagement.Pentesters {
+		response.Pentesters = append(response.Pentesters, domain_client.EngagementPentester{
+			Id:    user.Id,
+			Name:  user.Name,
+			Email: user.Email,
+		})
+	}
+	return response
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+	util_blobstore "xops-admin/util/blob_store"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const (
+	logoKeyPrefix = "logos/"
+	// folder lama yang di-serve publik lewat app.Static, hanya dipakai untuk migrasi
+	legacyLogoDir     = "public/static"
+	legacyLogoBaseURL = "https://xops-api.sector.co.id/static/"
+
+	defaultLogoMaxBytes = 2 << 20
+	defaultLogoURLTTL   = 15 * time.Minute
+)
+
+func logoLimits() (int64, time.Duration) {
+	loadconfig, _ := config.LoadConfig(".")
+	maxBytes, ttl := int64(defaultLogoMaxBytes), defaultLogoURLTTL
+	if loadconfig.BlobMaxUploadBytes > 0 {
+		maxBytes = loadconfig.BlobMaxUploadBytes
+	}
+	if loadconfig.BlobURLExpiresIn > 0 {
+		ttl = loadconfig.BlobURLExpiresIn
+	}
+	return maxBytes, ttl
+}
+
+// saveLogoFile memvalidasi upload lalu menyimpannya ke blob store, mengembalikan key blob
+func (c *ClientUserRepo) saveLogoFile(fileHeader *multipart.FileHeader) (string, error) {
+	maxBytes, _ := logoLimits()
+	if fileHeader.Size > maxBytes {
+		return "", errorenum.LogoTooLarge
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", errorenum.InvalidLogo
+	}
+	defer file.Close()
+
+	// baca satu byte lebih dari batas supaya ukuran yang dilaporkan header tidak bisa dipalsukan
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		return "", errorenum.InvalidLogo
+	}
+	return c.storeLogo(context.TODO(), data, maxBytes)
+}
+
+func (c *ClientUserRepo) storeLogo(ctx context.Context, data []byte, maxBytes int64) (string, error) {
+	img, err := util_blobstore.SanitizeImage(data, maxBytes)
+	if errors.Is(err, util_blobstore.ErrImageTooLarge) {
+		return "", errorenum.LogoTooLarge
+	}
+	if err != nil {
+		return "", errorenum.InvalidLogo
+	}
+	key := logoKeyPrefix + util_uuid.GenerateID() + img.Ext
+	if err := c.blobs.Put(ctx, key, img.Data, img.ContentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// removeLogo menghapus logo dari blob store, atau dari folder lama jika belum dimigrasi
+func (c *ClientUserRepo) removeLogo(key string) {
+	if !strings.HasPrefix(key, logoKeyPrefix) {
+		os.Remove(filepath.Join(legacyLogoDir, filepath.Base(key)))
+		return
+	}
+	if err := c.blobs.Delete(context.TODO(), key); err != nil && !errors.Is(err, util_blobstore.ErrNotFound) {
+		log.Printf("remove logo %s: %v", key, err)
+	}
+}
+
+// logoURL link bertanda tangan untuk logo, logo lama yang belum dimigrasi tetap memakai URL static
+func (c *ClientUserRepo) logoURL(key string) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(key, logoKeyPrefix) {
+		return legacyLogoBaseURL + key, nil
+	}
+	_, ttl := logoLimits()
+	return c.blobs.SignedURL(context.TODO(), key, ttl)
+}
+
+func (c *ClientUserRepo) MigrateLegacyLogos(ctx context.Context) error {
+	clients, err := c.clientRepo.FindClientsWithLegacyLogo()
+	if err != nil {
+		return err
+	}
+	for _, client := range clients {
+		path := filepath.Join(legacyLogoDir, filepath.Base(client.LogoCompany))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("migrate logo client %s: %v", client.Id, err)
+			continue
+		}
+		// logo lama juga divalidasi dan di-encode ulang, batas ukurannya tidak berlaku
+		key, err := c.storeLogo(ctx, data, int64(len(data)))
+		if err != nil {
+			log.Printf("migrate logo client %s: %v", client.Id, err)
+			continue
+		}
+		if err := c.clientRepo.UpdateLogo(client.Id, key); err != nil {
+			c.blobs.Delete(ctx, key)
+			return err
+		}
+		os.Remove(path)
+	}
+	return nil
+}
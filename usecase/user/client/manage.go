@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_domainverify "xops-admin/util/domain_verify"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const (
+	defaultClientLimit  = 20
+	maxClientLimit      = 100
+	domainVerifyTimeout = 15 * time.Second
+)
+
+// domainPattern hostname biasa tanpa skema, port, atau path
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+func (c *ClientUserRepo) SearchClients(req *domain_client.ClientSearchRequest) (*domain_client.ClientListResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultClientLimit
+	}
+	if limit > maxClientLimit {
+		limit = maxClientLimit
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	clients, err := c.clientRepo.SearchClients(domain.ClientFilter{
+		Query:  strings.TrimSpace(req.Query),
+		Offset: (page - 1) * limit,
+		Limit:  limit + 1,
+	})
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	hasNext := len(clients) > limit
+	if hasNext {
+		clients = clients[:limit]
+	}
+	return &domain_client.ClientListResponse{
+		Data: clients,
+		Pagination: domain_client.ClientPagination{
+			Page:        page,
+			Size:        len(clients),
+			HasNext:     hasNext,
+			HasPrevious: page > 1,
+		},
+	}, nil
+}
+
+func (c *ClientUserRepo) GetClient(id string) (*model.Client, error) {
+	client, err := c.clientRepo.GetClientByID(id)
+	if err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	return client, nil
+}
+
+func (c *ClientUserRepo) AddDomain(clientID string, req *domain_client.AddDomainRequest) (*model.DomainClient, error) {
+	if _, err := c.clientRepo.GetClientByID(clientID); err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	name, err := c.validateNewDomain(req.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	token, err := util_domainverify.NewToken()
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	domainClient := &model.DomainClient{
+		Id:                 util_uuid.GenerateID(),
+		IdClient:           clientID,
+		Domain:             name,
+		Active:             false,
+		VerificationToken:  token,
+		VerificationStatus: model.DomainVerificationPending,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := c.clientRepo.AddDomain(domainClient); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return domainClient, nil
+}
+
+func normalizeDomain(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// validateNewDomain menormalisasi nama domain baru lalu memastikan formatnya benar dan
+// belum dimiliki client lain, dipakai semua jalur yang menambah domain
+func (c *ClientUserRepo) validateNewDomain(raw string) (string, error) {
+	name := normalizeDomain(raw)
+	if !domainPattern.MatchString(name) {
+		return "", errorenum.InvalidDomain
+	}
+	// satu domain hanya boleh milik satu client supaya temuan di ES tidak bocor ke client lain
+	taken, err := c.clientRepo.DomainTaken(name)
+	if err != nil {
+		return "", errorenum.SomethingError
+	}
+	if taken {
+		return "", errorenum.DuplicateDomain
+	}
+	return name, nil
+}
+
+// validateNewDomains validateNewDomain untuk beberapa domain sekaligus, duplikat dibuang.
+// Domain yang sudah ada di existing dilewati.
+func (c *ClientUserRepo) validateNewDomains(raw []string, existing map[string]bool) ([]string, error) {
+	names := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, r := range raw {
+		if key := normalizeDomain(r); existing[key] || seen[key] {
+			continue
+		}
+		name, err := c.validateNewDomain(r)
+		if err != nil {
+			return nil, err
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// SetDomainActive langsung berlaku karena domain selalu di-resolve ulang tiap request dashboard
+func (c *ClientUserRepo) SetDomainActive(clientID, domainID string, active bool) error {
+	if _, err := c.clientRepo.GetClientByID(clientID); err != nil {
+		return errorenum.DataNotFound
+	}
+	domainClient, err := c.clientRepo.GetDomainByID(clientID, domainID)
+	if err != nil {
+		return err
+	}
+	if active && !domainClient.IsVerified() {
+		return errorenum.DomainNotVerified
+	}
+	return c.clientRepo.SetDomainActive(clientID, domainID, active)
+}
+
+func (c *ClientUserRepo) DomainVerification(clientID, domainID string) (*domain_client.DomainVerificationResponse, error) {
+	if _, err := c.clientRepo.GetClientByID(clientID); err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	domainClient, err := c.clientRepo.GetDomainByID(clientID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return toDomainVerificationResponse(domainClient), nil
+}
+
+func (c *ClientUserRepo) VerifyDomain(ctx context.Context, clientID, domainID string) (*domain_client.DomainVerificationResponse, error) {
+	if _, err := c.clientRepo.GetClientByID(clientID); err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	domainClient, err := c.clientRepo.GetDomainByID(clientID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	// kepemilikan cukup dibuktikan sekali, record boleh dilepas setelah verified
+	if domainClient.IsVerified() {
+		return toDomainVerificationResponse(domainClient), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, domainVerifyTimeout)
+	defer cancel()
+	method, verifyErr := c.verifier.Verify(ctx, domainClient.Domain, domainClient.VerificationToken)
+
+	now := time.Now()
+	domainClient.LastCheckedAt = &now
+	if verifyErr != nil {
+		domainClient.VerificationStatus = model.DomainVerificationFailed
+		domainClient.LastCheckError = verifyErr.Error()
+	} else {
+		domainClient.VerificationStatus = model.DomainVerificationVerified
+		domainClient.VerificationMethod = method
+		domainClient.VerifiedAt = &now
+		domainClient.LastCheckError = ""
+		domainClient.Active = true
+	}
+	if err := c.clientRepo.UpdateDomainVerification(domainClient); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return toDomainVerificationResponse(domainClient), nil
+}
+
+func toDomainVerificationResponse(d *model.DomainClient) *domain_client.DomainVerificationResponse {
+	response := &domain_client.DomainVerificationResponse{
+		Id:             d.Id,
+		Domain:         d.Domain,
+		Active:         d.Active,
+		Status:         d.VerificationStatus,
+		Method:         d.VerificationMethod,
+		VerifiedAt:     d.VerifiedAt,
+		LastCheckedAt:  d.LastCheckedAt,
+		LastCheckError: d.LastCheckError,
+	}
+	// domain lama hasil backfill tidak punya token, jadi tidak ada instruksi
+	if d.VerificationToken != "" {
+		response.TxtRecordName = util_domainverify.TxtRecordName(d.Domain)
+		response.TxtRecordValue = util_domainverify.TxtValuePrefix + d.VerificationToken
+		response.FileURL = util_domainverify.FileURL(d.Domain)
+		response.FileContent = d.VerificationToken
+	}
+	return response
+}
+
+func (c *ClientUserRepo) RemoveDomain(clientID, domainID string) error {
+	if _, err := c.clientRepo.GetClientByID(clientID); err != nil {
+		return errorenum.DataNotFound
+	}
+	return c.clientRepo.DeleteDomain(clientID, domainID)
+}
+
+func (c *ClientUserRepo) DeleteClient(id string) error {
+	members, err := c.memberRepo.FindMembersByClientID(id)
+	if err != nil {
+		return errorenum.SomethingError
+	}
+	if err := c.clientRepo.DeleteClient(id); err != nil {
+		return err
+	}
+
+	loadconfig, _ := config.LoadConfig(".")
+	for _, member := range members {
+		if err := c.sessionRepo.RevokeAllByUserID(member.IdUser, domain_user_auth.SessionRevokedClientDeleted); err != nil {
+			return err
+		}
+		if err := c.apiKeyRepo.RevokeAllByUserID(member.IdUser); err != nil {
+			return err
+		}
+		if err := config.RevokeUserTokens(member.IdUser, time.Now(), loadconfig.RefreshTokenExpiresIn); err != nil {
+			return errorenum.SomethingError
+		}
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"xops-admin/config"
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_password "xops-admin/util/password"
+	util_signedtoken "xops-admin/util/signed_token"
+	util_uuid "xops-admin/util/uuid"
+)
+
+const (
+	invitationPurpose = "client_invitation"
+	invitationTTL     = 7 * 24 * time.Hour
+)
+
+type ClientMemberRepo struct {
+	memberRepo  domain.ClientMemberRepository
+	clientRepo  domain.ClientRepository
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	apiKeyRepo  domain.ApiKeyRepository
+}
+
+func NewClientMemberUseCase(memberRepo domain.ClientMemberRepository, clientRepo domain.ClientRepository, userRepo domain.UserRepository, sessionRepo domain.SessionRepository, apiKeyRepo domain.ApiKeyRepository) domain_client.ClientMemberUseCase {
+	return &ClientMemberRepo{
+		memberRepo:  memberRepo,
+		clientRepo:  clientRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+func (m *ClientMemberRepo) ListMembers(idUser string) (*domain_client.MemberListResponse, error) {
+	member, err := m.memberRepo.FindMemberByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.NotClientMember
+	}
+	members, err := m.memberRepo.FindMembersByClientID(member.IdClient)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	result := &domain_client.MemberListResponse{
+		Members:     make([]domain_client.MemberResponse, 0, len(members)),
+		Invitations: []model.ClientInvitation{},
+	}
+	for _, item := range members {
+		response := domain_client.MemberResponse{
+			Id:        item.Id,
+			IdUser:    item.IdUser,
+			Role:      item.Role,
+			CreatedAt: item.CreatedAt,
+		}
+		if item.User != nil {
+			response.Name = item.User.Name
+			response.Email = item.User.Email
+		}
+		result.Members = append(result.Members, response)
+	}
+	// undangan hanya terlihat oleh owner karena berisi email yang belum menjadi anggota
+	if member.Role == model.ClientMemberOwner {
+		invitations, err := m.memberRepo.FindPendingInvitations(member.IdClient)
+		if err != nil {
+			return nil, errorenum.SomethingError
+		}
+		result.Invitations = invitations
+	}
+	return result, nil
+}
+
+func (m *ClientMemberRepo) Invite(idUser string, req *domain_client.InviteMemberRequest) (*model.ClientInvitation, error) {
+	owner, err := m.findOwner(idUser)
+	if err != nil {
+		return nil, err
+	}
+	if !model.IsValidClientMemberRole(req.Role) {
+		return nil, errorenum.InvalidMemberRole
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if user, err := m.userRepo.FindUserBYEmail(email); err == nil && user != nil {
+		return nil, errorenum.InvitationEmailTaken
+	}
+	client, err := m.clientRepo.GetClientByID(owner.IdClient)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	if client.ContractExpired(time.Now()) {
+		return nil, errorenum.ContractEnded
+	}
+	inviter, err := m.userRepo.FindUserBYID(idUser)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return nil, errorenum.SomethingError
+	}
+
+	invitation := &model.ClientInvitation{
+		Id:        util_uuid.GenerateID(),
+		IdClient:  owner.IdClient,
+		Email:     email,
+		Role:      req.Role,
+		InvitedBy: idUser,
+		ExpiresAt: time.Now().Add(invitationTTL),
+		CreatedAt: time.Now(),
+	}
+	token, claims, err := util_signedtoken.Generate(loadconfig.LinkSigningSecret, invitationPurpose, invitation.Id, invitationTTL)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	invitation.Nonce = claims.Nonce
+	if err := m.memberRepo.CreateInvitation(invitation); err != nil {
+		return nil, errorenum.SomethingError
+	}
+
+	emailData := domain.EmailData{
+		Data:    loadconfig.FrontendURL + "/invitation?token=" + url.QueryEscape(token),
+		Subject: "You're invited to " + client.CompanyName + " on SectorOne",
+		Invitation: &domain.EmailInvitation{
+			Company:   client.CompanyName,
+			Role:      invitation.Role,
+			InvitedBy: inviter.Name,
+			ExpiresAt: invitation.ExpiresAt.Format("02 Jan 2006 15:04 MST"),
+		},
+	}
+	go domain.SendEmail(&model.User{Email: email}, email, &emailData, "client_invitation.html", "templates/client_invitation")
+	return invitation, nil
+}
+
+func (m *ClientMemberRepo) RevokeInvitation(idUser, idInvitation string) error {
+	owner, err := m.findOwner(idUser)
+	if err != nil {
+		return err
+	}
+	invitation, err := m.memberRepo.FindInvitationByID(idInvitation)
+	if err != nil || invitation.IdClient != owner.IdClient {
+		return errorenum.DataNotFound
+	}
+	return m.memberRepo.DeleteInvitation(invitation.Id)
+}
+
+func (m *ClientMemberRepo) RemoveMember(idUser, idMember string) error {
+	owner, err := m.findOwner(idUser)
+	if err != nil {
+		return err
+	}
+	member, err := m.memberRepo.FindMemberByID(idMember)
+	if err != nil || member.IdClient != owner.IdClient {
+		return errorenum.DataNotFound
+	}
+	if member.Role == model.ClientMemberOwner {
+		count, err := m.memberRepo.CountOwners(member.IdClient)
+		if err != nil {
+			return errorenum.SomethingError
+		}
+		if count <= 1 {
+			return errorenum.LastOwnerRequired
+		}
+	}
+	if err := m.memberRepo.DeleteMember(member.Id); err != nil {
+		return err
+	}
+
+	// anggota yang dikeluarkan langsung kehilangan akses dari semua perangkat
+	loadconfig, _ := config.LoadConfig(".")
+	if err := m.sessionRepo.RevokeAllByUserID(member.IdUser, domain_user_auth.SessionRevokedRemoved); err != nil {
+		return err
+	}
+	if err := m.apiKeyRepo.RevokeAllByUserID(member.IdUser); err != nil {
+		return err
+	}
+	if err := config.RevokeUserTokens(member.IdUser, time.Now(), loadconfig.RefreshTokenExpiresIn); err != nil {
+		return errorenum.SomethingError
+	}
+	return nil
+}
+
+func (m *ClientMemberRepo) PreviewInvitation(token string) (*domain_client.InvitationPreview, error) {
+	invitation, err := m.parseInvitation(token)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.clientRepo.GetClientByID(invitation.IdClient)
+	if err != nil {
+		return nil, errorenum.InvalidInvitation
+	}
+	return &domain_client.InvitationPreview{
+		Email:     invitation.Email,
+		Company:   client.CompanyName,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+func (m *ClientMemberRepo) AcceptInvitation(req *domain_client.AcceptInvitationRequest) error {
+	invitation, err := m.parseInvitation(req.Token)
+	if err != nil {
+		return err
+	}
+	if err := util_password.CheckPolicy(req.Password); err != nil {
+		return err
+	}
+	if user, err := m.userRepo.FindUserBYEmail(invitation.Email); err == nil && user != nil {
+		return errorenum.InvitationEmailTaken
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return errorenum.SomethingError
+	}
+
+	// email sudah terbukti milik user karena link dikirim ke alamat tersebut
+	now := time.Now()
+	user := &model.User{
+		Id:           util_uuid.GenerateID(),
+		Name:         strings.TrimSpace(req.Name),
+		Email:        invitation.Email,
+		Password:     string(hashed),
+		IdRole:       model.RoleClient,
+		IsVerified:   true,
+		IsTwoFA:      true,
+		VerifiedCode: "-",
+		TOTPKey:      "-",
+		TwoFAMethod:  domain_user_auth.TwoFAMethodEmail,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	member := &model.ClientMember{
+		Id:        util_uuid.GenerateID(),
+		IdClient:  invitation.IdClient,
+		IdUser:    user.Id,
+		Role:      invitation.Role,
+		CreatedAt: now,
+	}
+	if err := m.memberRepo.AcceptInvitation(invitation, user, member); err != nil {
+		if err == errorenum.DuplicateEmail {
+			return errorenum.InvitationEmailTaken
+		}
+		return err
+	}
+	return nil
+}
+
+// parseInvitation memverifikasi token dan memastikan undangan masih berlaku
+func (m *ClientMemberRepo) parseInvitation(token string) (*model.ClientInvitation, error) {
+	loadconfig, err := config.LoadConfig(".")
+	if err != nil || loadconfig.LinkSigningSecret == "" {
+		return nil, errorenum.SomethingError
+	}
+	claims, err := util_signedtoken.Parse(loadconfig.LinkSigningSecret, invitationPurpose, token)
+	if err != nil {
+		return nil, errorenum.InvalidInvitation
+	}
+	invitation, err := m.memberRepo.FindInvitationByID(claims.Subject)
+	if err != nil || invitation.Nonce != claims.Nonce || !invitation.IsPending() {
+		return nil, errorenum.InvalidInvitation
+	}
+	return invitation, nil
+}
+
+func (m *ClientMemberRepo) findOwner(idUser string) (*model.ClientMember, error) {
+	member, err := m.memberRepo.FindMemberByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.NotClientMember
+	}
+	if member.Role != model.ClientMemberOwner {
+		return nil, errorenum.NotClientOwner
+	}
+	return member, nil
+}
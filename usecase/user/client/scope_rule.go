@@ -0,0 +1,259 @@
+package client
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+	util_uuid "xops-admin/util/uuid"
+)
+
+// hostGlobPattern hostname dengan wildcard "*", tanpa skema, port, atau path
+var hostGlobPattern = regexp.MustCompile(`^[a-z0-9*]([a-z0-9*.-]*[a-z0-9*])?$`)
+
+func (c *ClientUserRepo) ListScopeRules(clientID string) ([]model.ScopeRule, error) {
+	if _, err := c.clientRepo.GetClientByID(clientID); err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	rules, err := c.clientRepo.FindScopeRules(clientID)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return rules, nil
+}
+
+func (c *ClientUserRepo) AddScopeRule(clientID string, req *domain_client.ScopeRuleRequest) (*model.ScopeRule, error) {
+	if _, err := c.clientRepo.GetClientByID(clientID); err != nil {
+		return nil, errorenum.DataNotFound
+	}
+	pattern, err := normalizeScopeRule(req.Action, req.Type, req.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	rule := &model.ScopeRule{
+		Id:          util_uuid.GenerateID(),
+		IdClient:    clientID,
+		Action:      req.Action,
+		Type:        req.Type,
+		Pattern:     pattern,
+		Description: strings.TrimSpace(req.Description),
+		CreatedAt:   time.Now(),
+	}
+	if err := c.clientRepo.AddScopeRule(rule); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	return rule, nil
+}
+
+// RemoveScopeRule langsung berlaku karena rule dibaca ulang tiap request dashboard
+func (c *ClientUserRepo) RemoveScopeRule(clientID, ruleID string) error {
+	return c.clientRepo.DeleteScopeRule(clientID, ruleID)
+}
+
+// normalizeScopeRule memvalidasi pola sesuai jenis rule dan mengembalikan bentuk yang disimpan
+func normalizeScopeRule(action, ruleType, pattern string) (string, error) {
+	if action != model.ScopeRuleInclude && action != model.ScopeRuleExclude {
+		return "", errorenum.InvalidScopeRule
+	}
+	pattern = strings.TrimSpace(pattern)
+	switch ruleType {
+	case model.ScopeRuleHost:
+		pattern = strings.TrimSuffix(strings.ToLower(pattern), ".")
+		if !hostGlobPattern.MatchString(pattern) {
+			return "", errorenum.InvalidScopeRule
+		}
+	case model.ScopeRulePath:
+		if !strings.HasPrefix(pattern, "/") || strings.ContainsAny(pattern, " \t") {
+			return "", errorenum.InvalidScopeRule
+		}
+	case model.ScopeRuleRegex:
+		// pola dijalankan sebagai regexp Lucene di ES, bukan RE2, jadi divalidasi dengan aturan Lucene
+		if !validLuceneRegexp(pattern) {
+			return "", errorenum.InvalidScopeRule
+		}
+	default:
+		return "", errorenum.InvalidScopeRule
+	}
+	return pattern, nil
+}
+
+// luceneParser memeriksa pola terhadap subset regexp Lucene yang artinya sama di RE2: literal,
+// ".", kelas karakter, grup, "|" dan satu quantifier (?, *, +, {n,m}) per atom. Yang ditolak:
+// anchor ^ $ (Lucene selalu mencocokkan seluruh URL), operator Lucene # @ & < > ~ ",
+// escape huruf / angka seperti \d, grup (?...) dan quantifier bertumpuk seperti *? yang
+// di Lucene tidak berarti lazy.
+type luceneParser struct {
+	s []rune
+	i int
+}
+
+const luceneReserved = `.?+*|{}[]()"\#@&<>~^$`
+
+func validLuceneRegexp(pattern string) bool {
+	p := &luceneParser{s: []rune(pattern)}
+	return len(p.s) > 0 && p.alternation() && p.i == len(p.s)
+}
+
+func (p *luceneParser) peek() (rune, bool) {
+	if p.i >= len(p.s) {
+		return 0, false
+	}
+	return p.s[p.i], true
+}
+
+func (p *luceneParser) alternation() bool {
+	if !p.sequence() {
+		return false
+	}
+	for r, ok := p.peek(); ok && r == '|'; r, ok = p.peek() {
+		p.i++
+		if !p.sequence() {
+			return false
+		}
+	}
+	return true
+}
+
+// sequence minimal satu atom sampai "|", ")" atau akhir pola
+func (p *luceneParser) sequence() bool {
+	count := 0
+	for r, ok := p.peek(); ok && r != '|' && r != ')'; r, ok = p.peek() {
+		if !p.atom() || !p.quantifier() {
+			return false
+		}
+		count++
+	}
+	return count > 0
+}
+
+func (p *luceneParser) atom() bool {
+	r, _ := p.peek()
+	p.i++
+	switch r {
+	case '.':
+		return true
+	case '(':
+		if next, ok := p.peek(); !ok || next == '?' || next == ')' {
+			return false
+		}
+		if !p.alternation() {
+			return false
+		}
+		if next, ok := p.peek(); !ok || next != ')' {
+			return false
+		}
+		p.i++
+		return true
+	case '[':
+		return p.class()
+	case '\\':
+		return p.escaped()
+	}
+	return !strings.ContainsRune(luceneReserved, r)
+}
+
+// escaped hanya tanda baca, \d \w dan sejenisnya artinya berbeda antar versi Lucene
+func (p *luceneParser) escaped() bool {
+	r, ok := p.peek()
+	if !ok || unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return false
+	}
+	p.i++
+	return true
+}
+
+func (p *luceneParser) class() bool {
+	if r, ok := p.peek(); ok && r == '^' {
+		p.i++
+	}
+	items := 0
+	for {
+		r, ok := p.peek()
+		if !ok || r == '[' {
+			return false
+		}
+		if r == ']' {
+			p.i++
+			return items > 0
+		}
+		lo, ok := p.classChar()
+		if !ok {
+			return false
+		}
+		if next, ok := p.peek(); ok && next == '-' && p.i+1 < len(p.s) && p.s[p.i+1] != ']' {
+			p.i++
+			hi, ok := p.classChar()
+			if !ok || hi < lo {
+				return false
+			}
+		}
+		items++
+	}
+}
+
+func (p *luceneParser) classChar() (rune, bool) {
+	r, _ := p.peek()
+	p.i++
+	if r != '\\' {
+		return r, true
+	}
+	if !p.escaped() {
+		return 0, false
+	}
+	return p.s[p.i-1], true
+}
+
+// quantifier opsional setelah atom, paling banyak satu
+func (p *luceneParser) quantifier() bool {
+	r, ok := p.peek()
+	if !ok {
+		return true
+	}
+	switch r {
+	case '?', '*', '+':
+		p.i++
+	case '{':
+		p.i++
+		min, ok := p.number()
+		if !ok {
+			return false
+		}
+		max := min
+		if r, _ := p.peek(); r == ',' {
+			p.i++
+			max = -1
+			if r, _ := p.peek(); r != '}' {
+				if max, ok = p.number(); !ok || max < min {
+					return false
+				}
+			}
+		}
+		if r, _ := p.peek(); r != '}' {
+			return false
+		}
+		p.i++
+	default:
+		return true
+	}
+	if r, ok := p.peek(); ok && strings.ContainsRune("?*+{", r) {
+		return false
+	}
+	return true
+}
+
+func (p *luceneParser) number() (int, bool) {
+	start := p.i
+	n := 0
+	for r, ok := p.peek(); ok && r >= '0' && r <= '9'; r, ok = p.peek() {
+		n = n*10 + int(r-'0')
+		if n > 1000 {
+			return 0, false
+		}
+		p.i++
+	}
+	return n, p.i > start
+}
@@ -2,12 +2,10 @@ package client
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"io"
-	"math/rand"
-	"mime/multipart"
-	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
@@ -15,27 +13,70 @@ import (
 	"xops-admin/domain"
 	domain_user_auth "xops-admin/domain/user/auth"
 	domain_client "xops-admin/domain/user/client"
+	domain_overview "xops-admin/domain/user/overview"
+	"xops-admin/helper/errorenum"
 	"xops-admin/model"
+	util_blobstore "xops-admin/util/blob_store"
+	util_domainverify "xops-admin/util/domain_verify"
 )
 
 type ClientUserRepo struct {
-	clientRepo domain.ClientRepository
-	userRepo   domain.UserRepository
+	clientRepo      domain.ClientRepository
+	userRepo        domain.UserRepository
+	roleRepo        domain.RoleRepository
+	memberRepo      domain.ClientMemberRepository
+	sessionRepo     domain.SessionRepository
+	apiKeyRepo      domain.ApiKeyRepository
+	passwordUseCase domain_user_auth.PasswordUseCase
+	verifier        *util_domainverify.Verifier
+	blobs           util_blobstore.BlobStore
 }
 
-func (c *ClientUserRepo) GetDomainByClientID(id string) (*model.DomainClient, error) {
-	return c.clientRepo.GetDomainByClientID(id)
+func NewClientUseCase(clientRepo domain.ClientRepository, userRepo domain.UserRepository, roleRepo domain.RoleRepository, memberRepo domain.ClientMemberRepository, sessionRepo domain.SessionRepository, apiKeyRepo domain.ApiKeyRepository, passwordUseCase domain_user_auth.PasswordUseCase, verifier *util_domainverify.Verifier, blobs util_blobstore.BlobStore) domain_client.ClientUseCase {
+	return &ClientUserRepo{
+		clientRepo:      clientRepo,
+		userRepo:        userRepo,
+		roleRepo:        roleRepo,
+		memberRepo:      memberRepo,
+		sessionRepo:     sessionRepo,
+		apiKeyRepo:      apiKeyRepo,
+		passwordUseCase: passwordUseCase,
+		verifier:        verifier,
+		blobs:           blobs,
+	}
 }
 
-func NewClientUseCase(clientRepo domain.ClientRepository, userRepo domain.UserRepository) domain_client.ClientUseCase {
-	return &ClientUserRepo{
-		clientRepo: clientRepo,
-		userRepo:   userRepo,
+func (c *ClientUserRepo) GetClientWithLastPentest(clientID string, domains []string, es *elasticsearch.Client) (*domain_client.ClientPenTestInfo, error) {
+	info, err := c.clientRepo.GetClientWithLastPentest(context.TODO(), clientID, domains, es)
+	if err != nil {
+		return nil, err
+	}
+	// repo mengembalikan key blob, yang dikirim ke frontend link bertanda tangan berumur pendek
+	info.LogoCompany, err = c.logoURL(info.LogoCompany)
+	if err != nil {
+		return nil, err
 	}
+	return info, nil
 }
 
-func (c *ClientUserRepo) GetClientWithLastPentest(clientID, domain string, es *elasticsearch.Client) (*domain_client.ClientPenTestInfo, error) {
-	return c.clientRepo.GetClientWithLastPentest(context.TODO(), clientID, domain, es)
+func (c *ClientUserRepo) ListDomains(idUser string) (*domain_client.SelectableDomainsResponse, error) {
+	domains, err := c.clientRepo.GetActiveDomainsByUserID(idUser)
+	if err != nil {
+		return nil, errorenum.SomethingError
+	}
+	result := &domain_client.SelectableDomainsResponse{
+		Domains: make([]domain_client.SelectableDomain, 0, len(domains)),
+		All:     domain_overview.AllDomains,
+	}
+	for i, d := range domains {
+		result.Domains = append(result.Domains, domain_client.SelectableDomain{
+			Id:     d.Id,
+			Domain: d.Domain,
+			// sama dengan domain yang dipakai jika query param "domain" kosong
+			IsDefault: i == 0,
+		})
+	}
+	return result, nil
 }
 
 func (c *ClientUserRepo) CreateUserClient(req *domain_client.CreateClientRequest) (*domain_client.ClientResponse, error) {
@@ -59,34 +100,55 @@ func (c *ClientUserRepo) CreateUserClient(req *domain_client.CreateClientRequest
 	if err != nil {
 		return nil, fmt.Errorf("invalid end date format: %w", err)
 	}
+	domains, err := c.validateNewDomains(req.Domains, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// role default client, tapi bisa diganti misalnya untuk staff client dengan hak berbeda.
+	// Pemanggil cukup punya clients:manage, jadi role yang lebih luas (misalnya admin) ditolak.
+	idRole := model.RoleClient
+	if req.IdRole != 0 {
+		role, err := c.roleRepo.FindRoleBYID(req.IdRole)
+		if err != nil {
+			return nil, errorenum.InvalidRole
+		}
+		if !model.IsClientScopedRole(role) {
+			return nil, errorenum.RoleNotClientScoped
+		}
+		idRole = req.IdRole
+	}
 
 	generatedPassword := c.generateRandomPassword()
 
 	var logoPath string
 	if req.Logo != nil {
-		logoPath, err = c.saveLogoFile(req.Logo, fmt.Sprintf("temp_%d", time.Now().Unix()))
+		logoPath, err = c.saveLogoFile(req.Logo)
 		if err != nil {
-			return nil, fmt.Errorf("failed to save logo: %w", err)
+			return nil, err
 		}
 	}
 
 	userWithClientReq := &domain_user_auth.CreateUserWithClientRequest{
 		Email:       req.Email,
-		IdRole:      3,
+		IdRole:      idRole,
+		WithClient:  true,
 		IsVerified:  req.IsVerified,
 		IsTwoFA:     req.IsTwoFA,
 		LogoCompany: logoPath,
 		CompanyName: req.CompanyName,
 		StartDate:   startDate,
 		EndDate:     endDate,
-		Domains:     req.Domains,
+		Domains:     domains,
 		Password:    generatedPassword,
+
+		MustChangePassword: true,
 	}
 
 	if err := c.userRepo.CreateUser(userWithClientReq); err != nil {
 
 		if logoPath != "" {
-			os.Remove(filepath.Join("public/static", logoPath))
+			c.removeLogo(logoPath)
 		}
 		return nil, fmt.Errorf("failed to create user and client: %w", err)
 	}
@@ -101,31 +163,14 @@ func (c *ClientUserRepo) CreateUserClient(req *domain_client.CreateClientRequest
 		return nil, fmt.Errorf("failed to find created client: %w", err)
 	}
 
-	if logoPath != "" {
-		newLogoPath, err := c.renameLogoFile(logoPath, client.Id)
-		if err != nil {
-
-			fmt.Printf("Warning: failed to rename logo file: %v\n", err)
-		} else {
-
-			client.LogoCompany = newLogoPath
-			c.clientRepo.UpdateClient(client)
-		}
-	}
-
-	file := "password.html"
-	temp := "templates/new_password"
-	emailData := domain.EmailData{
-		FirstName: user.Name,
-		Data:      generatedPassword,
-		Subject:   "Your password",
+	// password awal tidak pernah dikirim, user mengisinya sendiri lewat link aktivasi
+	if err := c.passwordUseCase.SendActivationLink(user); err != nil {
+		return nil, fmt.Errorf("failed to send activation link: %w", err)
 	}
-	go domain.SendEmail(user, user.Email, &emailData, file, temp)
 
 	response := &domain_client.ClientResponse{
-		User:     user,
-		Client:   client,
-		Password: generatedPassword,
+		User:   user,
+		Client: client,
 	}
 
 	return response, nil
@@ -135,27 +180,32 @@ func (c *ClientUserRepo) handleExistingClientDomainUpdate(existingClient *model.
 	// Buat map dari domain yang sudah ada
 	existingDomains := make(map[string]bool)
 	for _, domainClient := range existingClient.DomainClient {
-		existingDomains[domainClient.Domain] = true
+		existingDomains[normalizeDomain(domainClient.Domain)] = true
 	}
 
-	// Cari domain baru yang belum ada
-	var newDomains []string
-	for _, domain := range req.Domains {
-		if !existingDomains[domain] {
-			newDomains = append(newDomains, domain)
-		}
+	// Cari domain baru yang belum ada, divalidasi sama seperti AddDomain
+	newDomains, err := c.validateNewDomains(req.Domains, existingDomains)
+	if err != nil {
+		return nil, err
 	}
 
 	// Jika ada domain baru, tambahkan dan simpan ke database
 	if len(newDomains) > 0 {
 		for _, domain := range newDomains {
+			token, err := util_domainverify.NewToken()
+			if err != nil {
+				return nil, errorenum.SomethingError
+			}
+			// domain baru baru aktif setelah kepemilikannya terverifikasi
 			domainClient := model.DomainClient{
-				Id:        fmt.Sprintf("domain_%s_%d", existingClient.Id, time.Now().UnixNano()),
-				IdClient:  existingClient.Id,
-				Domain:    domain,
-				Active:    true,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
+				Id:                 fmt.Sprintf("domain_%s_%d", existingClient.Id, time.Now().UnixNano()),
+				IdClient:           existingClient.Id,
+				Domain:             domain,
+				Active:             false,
+				VerificationToken:  token,
+				VerificationStatus: model.DomainVerificationPending,
+				CreatedAt:          time.Now(),
+				UpdatedAt:          time.Now(),
 			}
 			existingClient.DomainClient = append(existingClient.DomainClient, domainClient)
 		}
@@ -172,151 +222,88 @@ func (c *ClientUserRepo) handleExistingClientDomainUpdate(existingClient *model.
 	}
 
 	response := &domain_client.ClientResponse{
-		User:     user,
-		Client:   existingClient,
-		Password: "",
+		User:   user,
+		Client: existingClient,
 	}
 
 	return response, nil
 }
-func (c *ClientUserRepo) generateRandomPassword() string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	const randomLength = 5
 
-	rand.Seed(time.Now().UnixNano())
-	randomPart := make([]byte, randomLength)
-	for i := range randomPart {
-		randomPart[i] = charset[rand.Intn(len(charset))]
+// generateRandomPassword hanya placeholder acak sampai akun diaktivasi
+func (c *ClientUserRepo) generateRandomPassword() string {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		panic(err)
 	}
-
-	return "Default_password1" + string(randomPart)
+	return hex.EncodeToString(randomBytes)
 }
 
 func (c *ClientUserRepo) UpdateUserClient(id string, req *domain_client.UpdateClientRequest) error {
 
 	existingClient, err := c.clientRepo.GetClientByID(id)
 	if err != nil {
-		return fmt.Errorf("client not found: %w", err)
+		return errorenum.DataNotFound
+	}
+	// domain tidak ikut disimpan ulang, perubahannya lewat AddDomain / SetDomainActive / RemoveDomain
+	existingClient.DomainClient = nil
+
+	// tanggal divalidasi dulu supaya tidak ada perubahan setengah jadi, lalu disimpan
+	// lewat jalur yang sama dengan endpoint kontrak supaya pengingat ikut di-reset
+	var window *contractWindow
+	if req.StartDate != "" || req.EndDate != "" {
+		startDate, endDate := req.StartDate, req.EndDate
+		if startDate == "" {
+			startDate = existingClient.StartDate.Format("2006-01-02")
+		}
+		if endDate == "" {
+			endDate = existingClient.EndDate.Format("2006-01-02")
+		}
+		window, err = parseContractWindow(startDate, endDate)
+		if err != nil {
+			return err
+		}
 	}
 
 	if req.CompanyName != "" {
 		existingClient.CompanyName = req.CompanyName
 	}
 
-	if req.StartDate != "" {
-		startDate, err := time.Parse("2006-01-02", req.StartDate)
-		if err != nil {
-			return fmt.Errorf("invalid start date format: %w", err)
-		}
-		existingClient.StartDate = startDate
-	}
-
-	if req.EndDate != "" {
-		endDate, err := time.Parse("2006-01-02", req.EndDate)
-		if err != nil {
-			return fmt.Errorf("invalid end date format: %w", err)
+	// email dicek sebelum apa pun disimpan supaya email yang sudah dipakai tidak berakhir
+	// sebagai error constraint dari database
+	var email string
+	if req.Email != "" {
+		email = strings.ToLower(strings.TrimSpace(req.Email))
+		owner, err := c.userRepo.FindUserBYEmail(email)
+		if err == nil && owner != nil && owner.Id != existingClient.IdUser {
+			return errorenum.DuplicateEmail
 		}
-		existingClient.EndDate = endDate
 	}
 
+	// logo lama baru dihapus setelah client tersimpan dengan logo baru
+	var oldLogo string
 	if req.Logo != nil {
-
-		if existingClient.LogoCompany != "" {
-			oldLogoPath := filepath.Join("public/static", existingClient.LogoCompany)
-			os.Remove(oldLogoPath)
-		}
-
-		logoPath, err := c.saveLogoFile(req.Logo, id)
+		logoPath, err := c.saveLogoFile(req.Logo)
 		if err != nil {
-			return fmt.Errorf("failed to save logo: %w", err)
+			return err
 		}
+		oldLogo = existingClient.LogoCompany
 		existingClient.LogoCompany = logoPath
 	}
 
-	if req.Email != "" {
-		user, err := c.userRepo.FindUserBYID(existingClient.IdUser)
-		if err != nil {
-			return fmt.Errorf("failed to find user: %w", err)
-		}
-		user.Email = req.Email
-		if err := c.userRepo.UpdateUser(user); err != nil {
-			return fmt.Errorf("failed to update user email: %w", err)
-		}
-	}
-
-	if len(req.Domains) > 0 {
-
-		existingClient.DomainClient = []model.DomainClient{}
-
-		for _, domain := range req.Domains {
-			fmt.Println(domain)
-			domainClient := model.DomainClient{
-				Id:        fmt.Sprintf("domain_%s_%d", id, time.Now().UnixNano()),
-				IdClient:  id,
-				Domain:    domain,
-				Active:    true,
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			}
-			existingClient.DomainClient = append(existingClient.DomainClient, domainClient)
+	if err := c.clientRepo.UpdateClientWithEmail(existingClient, email); err != nil {
+		if req.Logo != nil {
+			c.removeLogo(existingClient.LogoCompany)
 		}
+		return err
 	}
-
-	return c.clientRepo.UpdateClient(existingClient)
-}
-
-func (c *ClientUserRepo) saveLogoFile(fileHeader *multipart.FileHeader, clientID string) (string, error) {
-
-	staticDir := "public/static"
-	if err := os.MkdirAll(staticDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create static directory: %w", err)
-	}
-
-	ext := filepath.Ext(fileHeader.Filename)
-	if ext == "" {
-		ext = ".jpg"
+	if oldLogo != "" {
+		c.removeLogo(oldLogo)
 	}
 
-	filename := fmt.Sprintf("logo_%s_%d%s", clientID, time.Now().Unix(), ext)
-	filePath := filepath.Join(staticDir, filename)
-
-	src, err := fileHeader.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to open uploaded file: %w", err)
-	}
-	defer src.Close()
-
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create destination file: %w", err)
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("failed to save file: %w", err)
-	}
-
-	return filename, nil
-}
-
-func (c *ClientUserRepo) renameLogoFile(oldPath, clientID string) (string, error) {
-	if oldPath == "" {
-		return "", nil
-	}
-
-	oldFilePath := filepath.Join("public/static", oldPath)
-
-	ext := filepath.Ext(oldPath)
-	if ext == "" {
-		ext = ".jpg"
-	}
-
-	newFilename := fmt.Sprintf("logo_%s_%d%s", clientID, time.Now().Unix(), ext)
-	newFilePath := filepath.Join("public/static", newFilename)
-
-	if err := os.Rename(oldFilePath, newFilePath); err != nil {
-		return "", fmt.Errorf("failed to rename file: %w", err)
+	if window != nil {
+		if err := c.clientRepo.UpdateContractWindow(id, window.start, window.end); err != nil {
+			return errorenum.SomethingError
+		}
 	}
-
-	return newFilename, nil
+	return nil
 }
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"xops-admin/domain"
+	domain_user_auth "xops-admin/domain/user/auth"
+	domain_client "xops-admin/domain/user/client"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type fakeRoleRepo struct {
+	domain.RoleRepository
+	roles map[int]*model.Role
+}
+
+func (f *fakeRoleRepo) FindRoleBYID(id int) (*model.Role, error) {
+	if role, ok := f.roles[id]; ok {
+		return role, nil
+	}
+	return nil, errorenum.DataNotFound
+}
+
+type fakeUserRepo struct {
+	domain.UserRepository
+	created []*domain_user_auth.CreateUserWithClientRequest
+}
+
+func (f *fakeUserRepo) FindUserBYEmail(email string) (*model.User, error) {
+	for _, req := range f.created {
+		if req.Email == email {
+			return &model.User{Id: "user-1", Email: email, IdRole: req.IdRole}, nil
+		}
+	}
+	return nil, errorenum.DataNotFound
+}
+
+func (f *fakeUserRepo) CreateUser(req *domain_user_auth.CreateUserWithClientRequest) error {
+	f.created = append(f.created, req)
+	return nil
+}
+
+type fakeClientRepo struct {
+	domain.ClientRepository
+}
+
+func (fakeClientRepo) DomainTaken(name string) (bool, error) {
+	return false, nil
+}
+
+func (fakeClientRepo) GetClientByUserID(idUser string) (*model.Client, error) {
+	return &model.Client{IdUser: idUser}, nil
+}
+
+type fakePasswordUseCase struct {
+	domain_user_auth.PasswordUseCase
+}
+
+func (fakePasswordUseCase) SendActivationLink(user *model.User) error {
+	return nil
+}
+
+func permissions(names ...string) []model.Permission {
+	result := make([]model.Permission, 0, len(names))
+	for _, name := range names {
+		result = append(result, model.Permission{Name: name})
+	}
+	return result
+}
+
+func newRoleTestUseCase() (*ClientUserRepo, *fakeUserRepo) {
+	users := &fakeUserRepo{}
+	roles := &fakeRoleRepo{roles: map[int]*model.Role{
+		model.RoleAdmin:  {Id: model.RoleAdmin, NameRole: "admin", Permissions: permissions(model.PermissionClientsManage, model.PermissionRolesManage, model.PermissionFindingsRead)},
+		model.RoleClient: {Id: model.RoleClient, NameRole: "client", Permissions: permissions(model.PermissionFindingsRead, model.PermissionFindingsTriage)},
+		// role buatan admin yang diam-diam menyelipkan users:manage
+		4: {Id: 4, NameRole: "client-plus", Permissions: permissions(model.PermissionFindingsRead, model.PermissionUsersManage)},
+		5: {Id: 5, NameRole: "client-viewer", Permissions: permissions(model.PermissionFindingsRead)},
+	}}
+	return &ClientUserRepo{clientRepo: fakeClientRepo{}, userRepo: users, roleRepo: roles, passwordUseCase: fakePasswordUseCase{}}, users
+}
+
+func newClientRequest(idRole int) *domain_client.CreateClientRequest {
+	return &domain_client.CreateClientRequest{
+		CompanyName: "Example",
+		Email:       "owner@example.com",
+		Domains:     []string{"example.com"},
+		StartDate:   "2024-01-01",
+		EndDate:     "2024-12-31",
+		IdRole:      idRole,
+	}
+}
+
+// pemanggil dengan clients:manage tidak boleh membuat user admin lewat id_role
+func TestCreateUserClientRejectsPrivilegedRole(t *testing.T) {
+	cases := map[string]struct {
+		idRole int
+		want   error
+	}{
+		"admin":             {model.RoleAdmin, errorenum.RoleNotClientScoped},
+		"custom with extra": {4, errorenum.RoleNotClientScoped},
+		"unknown role":      {99, errorenum.InvalidRole},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			usecase, users := newRoleTestUseCase()
+			if _, err := usecase.CreateUserClient(newClientRequest(tc.idRole)); !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if len(users.created) != 0 {
+				t.Fatalf("user created with role %d", users.created[0].IdRole)
+			}
+		})
+	}
+}
+
+func TestCreateUserClientAcceptsClientScopedRole(t *testing.T) {
+	cases := map[string]struct {
+		idRole, want int
+	}{
+		"default":       {0, model.RoleClient},
+		"client viewer": {5, 5},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			usecase, users := newRoleTestUseCase()
+			if _, err := usecase.CreateUserClient(newClientRequest(tc.idRole)); err != nil {
+				t.Fatalf("CreateUserClient: %v", err)
+			}
+			if len(users.created) != 1 || users.created[0].IdRole != tc.want {
+				t.Fatalf("created = %+v, want role %d", users.created, tc.want)
+			}
+		})
+	}
+}
+
+type fakeUpdateClientRepo struct {
+	fakeClientRepo
+	saved []string
+}
+
+func (fakeUpdateClientRepo) GetClientByID(id string) (*model.Client, error) {
+	return &model.Client{Id: id, IdUser: "owner"}, nil
+}
+
+func (f *fakeUpdateClientRepo) UpdateClientWithEmail(client *model.Client, email string) error {
+	f.saved = append(f.saved, email)
+	return nil
+}
+
+// email milik user lain ditolak sebelum client maupun email disimpan
+func TestUpdateUserClientEmail(t *testing.T) {
+	cases := map[string]struct {
+		email     string
+		want      error
+		wantSaved []string
+	}{
+		"taken by other user": {"Taken@Example.com ", errorenum.DuplicateEmail, nil},
+		"free email":          {"New@Example.com", nil, []string{"new@example.com"}},
+		"email unchanged":     {"", nil, []string{""}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clients := &fakeUpdateClientRepo{}
+			users := &fakeUserRepo{created: []*domain_user_auth.CreateUserWithClientRequest{{Email: "taken@example.com"}}}
+			usecase := &ClientUserRepo{clientRepo: clients, userRepo: users}
+			err := usecase.UpdateUserClient("client-1", &domain_client.UpdateClientRequest{Email: tc.email})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+			if len(clients.saved) != len(tc.wantSaved) || (len(tc.wantSaved) == 1 && clients.saved[0] != tc.wantSaved[0]) {
+				t.Fatalf("saved = %q, want %q", clients.saved, tc.wantSaved)
+			}
+		})
+	}
+}
@@ -6,28 +6,20 @@ import (
 
 	"xops-admin/domain"
 	domain_overview "xops-admin/domain/user/overview"
-	"xops-admin/model"
 )
 
 type BugDiscoveryTimelineRepo struct {
-	repo       domain.OverviewRepository
-	clientRepo domain.ClientRepository
+	repo domain.OverviewRepository
 }
 
-func NewBugDiscoveryTimeline(repo domain.OverviewRepository, clientRepo domain.ClientRepository) domain_overview.BugDiscoveryTimelineUseCase {
+func NewBugDiscoveryTimeline(repo domain.OverviewRepository) domain_overview.BugDiscoveryTimelineUseCase {
 	return &BugDiscoveryTimelineRepo{
-		repo:       repo,
-		clientRepo: clientRepo,
+		repo: repo,
 	}
 }
 
-func (u *BugDiscoveryTimelineRepo) GetDomainByClientID(id string) (*model.DomainClient, error) {
-	return u.clientRepo.GetDomainByClientID(id)
-}
-
-func (u *BugDiscoveryTimelineRepo) GetRealTimePentesterStatus(ctx context.Context, domainName string) ([]domain_overview.PentesterEffectiveness, error) {
-	// Ambil data untuk periode 1 hari terakhir
-	pentesters, err := u.repo.GetPentestersEffectiveness(ctx, domainName, 30)
+func (u *BugDiscoveryTimelineRepo) GetRealTimePentesterStatus(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.PentesterEffectiveness, error) {
+	pentesters, err := u.repo.GetPentestersEffectiveness(ctx, scope, rng)
 	if err != nil {
 		return nil, err
 	}
@@ -36,8 +28,8 @@ func (u *BugDiscoveryTimelineRepo) GetRealTimePentesterStatus(ctx context.Contex
 	// Bisa diurutkan berdasarkan status aktif atau total findings
 	return pentesters, nil
 }
-func (u *BugDiscoveryTimelineRepo) GetTotalFindingsWithTrend(ctx context.Context, domainName string) (*domain_overview.ResponseTotalFindings, error) {
-	return u.repo.GetTotalFindingsWithTrend(ctx, domainName)
+func (u *BugDiscoveryTimelineRepo) GetTotalFindingsWithTrend(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) (*domain_overview.ResponseTotalFindings, error) {
+	return u.repo.GetTotalFindingsWithTrend(ctx, scope, rng)
 }
 
 func (u *BugDiscoveryTimelineRepo) GetLogActivity(ctx context.Context, params domain_overview.LogActivityPaginationParams) (*domain_overview.LogActivityResponse, error) {
@@ -45,21 +37,27 @@ func (u *BugDiscoveryTimelineRepo) GetLogActivity(ctx context.Context, params do
 }
 
 // Existing function - Chart 1: Vulnerability Timeline
-func (s *BugDiscoveryTimelineRepo) GetVulnerabilityChart(ctx context.Context, period int, domainName, filter string) ([]domain_overview.ChartData, error) {
+func (s *BugDiscoveryTimelineRepo) GetVulnerabilityChart(ctx context.Context, rng domain_overview.ChartRange, scope domain_overview.DataScope, filter string) (*domain_overview.VulnerabilityChartResponse, error) {
 
-	stats, err := s.repo.GetVulnerabilityStats(ctx, period, domainName, filter)
+	stats, err := s.repo.GetVulnerabilityStats(ctx, rng, scope, filter)
 	if err != nil {
 		fmt.Println(err)
 		return nil, fmt.Errorf("failed to get vulnerability stats: %w", err)
 	}
-	chartData := ConvertToChartData(stats)
 
-	return chartData, nil
+	// label dikirim bersama data supaya UI tidak perlu menebak tanggal tiap titik
+	return &domain_overview.VulnerabilityChartResponse{
+		Interval: rng.Interval,
+		From:     rng.From,
+		To:       rng.To,
+		Labels:   rng.Labels(),
+		Series:   ConvertToChartData(stats),
+	}, nil
 }
 
 // NEW: Chart 2 - Bug Severity Distribution
-func (s *BugDiscoveryTimelineRepo) GetBugSeverityDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.SeverityDistribution, error) {
-	distributions, err := s.repo.GetBugSeverityDistribution(ctx, domainName, period, status)
+func (s *BugDiscoveryTimelineRepo) GetBugSeverityDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.SeverityDistribution, error) {
+	distributions, err := s.repo.GetBugSeverityDistribution(ctx, scope, rng, status)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bug severity distribution: %w", err)
 	}
@@ -67,8 +65,8 @@ func (s *BugDiscoveryTimelineRepo) GetBugSeverityDistribution(ctx context.Contex
 }
 
 // NEW: Chart 2 - Bug Status Distribution
-func (s *BugDiscoveryTimelineRepo) GetBugStatusDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.StatusDistribution, error) {
-	distributions, err := s.repo.GetBugStatusDistribution(ctx, domainName, period, status)
+func (s *BugDiscoveryTimelineRepo) GetBugStatusDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.StatusDistribution, error) {
+	distributions, err := s.repo.GetBugStatusDistribution(ctx, scope, rng, status)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bug status distribution: %w", err)
 	}
@@ -76,8 +74,8 @@ func (s *BugDiscoveryTimelineRepo) GetBugStatusDistribution(ctx context.Context,
 }
 
 // NEW: Chart 2 - Bug Validation Distribution
-func (s *BugDiscoveryTimelineRepo) GetBugValidationDistribution(ctx context.Context, domainName string, period int, status string) ([]domain_overview.ValidationDistribution, error) {
-	distributions, err := s.repo.GetBugValidationDistribution(ctx, domainName, period, status)
+func (s *BugDiscoveryTimelineRepo) GetBugValidationDistribution(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange, status string) ([]domain_overview.ValidationDistribution, error) {
+	distributions, err := s.repo.GetBugValidationDistribution(ctx, scope, rng, status)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bug validation distribution: %w", err)
 	}
@@ -85,16 +83,16 @@ func (s *BugDiscoveryTimelineRepo) GetBugValidationDistribution(ctx context.Cont
 }
 
 // NEW: Chart 3 - Host/Domain Bugs Exposure
-func (s *BugDiscoveryTimelineRepo) GetHostBugsExposure(ctx context.Context, domainName string, period int) ([]domain_overview.HostExposure, error) {
-	exposure, err := s.repo.GetHostBugsExposure(ctx, domainName, period)
+func (s *BugDiscoveryTimelineRepo) GetHostBugsExposure(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.HostExposure, error) {
+	exposure, err := s.repo.GetHostBugsExposure(ctx, scope, rng)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get host bugs exposure: %w", err)
 	}
 	return exposure, nil
 }
 
-func (s *BugDiscoveryTimelineRepo) GetPentestersActivityStats(ctx context.Context, domainName string, period int) ([]domain_overview.PentesterActivity, error) {
-	activity, err := s.repo.GetPentestersActivity(ctx, domainName, period)
+func (s *BugDiscoveryTimelineRepo) GetPentestersActivityStats(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.PentesterActivity, error) {
+	activity, err := s.repo.GetPentestersActivity(ctx, scope, rng)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pentesters activity stats: %w", err)
 	}
@@ -102,8 +100,8 @@ func (s *BugDiscoveryTimelineRepo) GetPentestersActivityStats(ctx context.Contex
 }
 
 // NEW: Chart 4 - Bug Type Frequency
-func (s *BugDiscoveryTimelineRepo) GetBugTypeFrequency(ctx context.Context, domainName string, period int) ([]domain_overview.BugTypeFrequency, error) {
-	frequency, err := s.repo.GetBugTypeFrequency(ctx, domainName, period)
+func (s *BugDiscoveryTimelineRepo) GetBugTypeFrequency(ctx context.Context, scope domain_overview.DataScope, rng domain_overview.ChartRange) ([]domain_overview.BugTypeFrequency, error) {
+	frequency, err := s.repo.GetBugTypeFrequency(ctx, scope, rng)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bug type frequency: %w", err)
 	}
@@ -0,0 +1,133 @@
+package role
+
+import (
+	"strings"
+
+	"xops-admin/domain"
+	domain_role "xops-admin/domain/user/role"
+	"xops-admin/helper/errorenum"
+	"xops-admin/model"
+)
+
+type RoleRepo struct {
+	roleRepo       domain.RoleRepository
+	permissionRepo domain.PermissionRepository
+}
+
+func NewRoleUseCase(roleRepo domain.RoleRepository, permissionRepo domain.PermissionRepository) domain_role.RoleUseCase {
+	return &RoleRepo{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+	}
+}
+
+func (r *RoleRepo) List() ([]*model.Role, error) {
+	return r.roleRepo.FindAllRoles()
+}
+
+func (r *RoleRepo) GetByID(id int) (*model.Role, error) {
+	return r.roleRepo.FindRoleBYID(id)
+}
+
+func (r *RoleRepo) Create(req *domain_role.RoleRequest) (*model.Role, error) {
+	name := strings.TrimSpace(req.NameRole)
+	if _, err := r.roleRepo.FindRoleBYName(name); err == nil {
+		return nil, errorenum.DuplicateRole
+	}
+	permissions, err := r.resolvePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{NameRole: name}
+	if err := r.roleRepo.CreateRole(role); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	if err := r.roleRepo.ReplaceRolePermissions(role, permissions); err != nil {
+		return nil, err
+	}
+	return r.roleRepo.FindRoleBYID(role.Id)
+}
+
+func (r *RoleRepo) Update(id int, req *domain_role.RoleRequest) (*model.Role, error) {
+	role, err := r.roleRepo.FindRoleBYID(id)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.NameRole)
+	if existing, err := r.roleRepo.FindRoleBYName(name); err == nil && existing.Id != role.Id {
+		return nil, errorenum.DuplicateRole
+	}
+	role.NameRole = name
+	// permission diatur lewat ReplaceRolePermissions, jangan ikut di-upsert oleh Save
+	role.Permissions = nil
+	if err := r.roleRepo.UpdateRole(role); err != nil {
+		return nil, errorenum.SomethingError
+	}
+	if req.Permissions != nil {
+		return r.SetPermissions(id, req.Permissions)
+	}
+	return r.roleRepo.FindRoleBYID(id)
+}
+
+func (r *RoleRepo) Delete(id int) error {
+	if id == model.RoleAdmin || id == model.RoleClient {
+		return errorenum.RoleBuiltIn
+	}
+	if _, err := r.roleRepo.FindRoleBYID(id); err != nil {
+		return err
+	}
+	total, err := r.roleRepo.CountUsersByRoleID(id)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return errorenum.RoleInUse
+	}
+	return r.roleRepo.DeleteRole(id)
+}
+
+func (r *RoleRepo) SetPermissions(id int, names []string) (*model.Role, error) {
+	role, err := r.roleRepo.FindRoleBYID(id)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := r.resolvePermissions(names)
+	if err != nil {
+		return nil, err
+	}
+	// admin tidak boleh kehilangan akses ke pengaturan role, kalau tidak semua orang terkunci
+	if role.Id == model.RoleAdmin && !hasPermission(permissions, model.PermissionRolesManage) {
+		return nil, errorenum.RoleBuiltIn
+	}
+	if err := r.roleRepo.ReplaceRolePermissions(role, permissions); err != nil {
+		return nil, err
+	}
+	return r.roleRepo.FindRoleBYID(id)
+}
+
+func (r *RoleRepo) ListPermissions() ([]model.Permission, error) {
+	return r.permissionRepo.FindAllPermissions()
+}
+
+func (r *RoleRepo) resolvePermissions(names []string) ([]model.Permission, error) {
+	permissions, err := r.permissionRepo.FindPermissionsByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if !hasPermission(permissions, name) {
+			return nil, errorenum.InvalidPermission
+		}
+	}
+	return permissions, nil
+}
+
+func hasPermission(permissions []model.Permission, name string) bool {
+	for _, permission := range permissions {
+		if permission.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package scope
+
+import (
+	"strings"
+
+	"xops-admin/domain"
+	domain_overview "xops-admin/domain/user/overview"
+	"xops-admin/helper/errorenum"
+)
+
+type ScopeRepo struct {
+	clientRepo     domain.ClientRepository
+	engagementRepo domain.EngagementRepository
+}
+
+func NewScopeUseCase(clientRepo domain.ClientRepository, engagementRepo domain.EngagementRepository) domain_overview.ScopeUseCase {
+	return &ScopeRepo{
+		clientRepo:     clientRepo,
+		engagementRepo: engagementRepo,
+	}
+}
+
+// ResolveDomains menerjemahkan query param "domain" menjadi daftar flag_domain yang boleh
+// dipakai query dashboard. Kosong = domain aktif terbaru, "all" = semua domain aktif,
+// selain itu harus salah satu domain aktif milik client user.
+func (s *ScopeRepo) ResolveDomains(idUser string, selected string) ([]string, error) {
+	domains, err := s.clientRepo.GetActiveDomainsByUserID(idUser)
+	if err != nil {
+		return nil, err
+	}
+	if len(domains) == 0 {
+		return nil, errorenum.NoActiveDomain
+	}
+
+	selected = strings.ToLower(strings.TrimSpace(selected))
+	switch selected {
+	case "":
+		return []string{domains[0].Domain}, nil
+	case domain_overview.AllDomains:
+		names := make([]string, 0, len(domains))
+		for _, d := range domains {
+			names = append(names, d.Domain)
+		}
+		return names, nil
+	}
+	for _, d := range domains {
+		if strings.EqualFold(d.Domain, selected) {
+			return []string{d.Domain}, nil
+		}
+	}
+	return nil, errorenum.DomainNotAllowed
+}
+
+// ResolveScope sama dengan ResolveDomains ditambah jendela kontrak client dan rule scope
+// client, supaya data di luar kontrak dan traffic out-of-scope tidak ikut tampil. Jika engagementID
+// diisi, domain dan jendela waktu dipersempit lagi ke scope engagement tersebut.
+func (s *ScopeRepo) ResolveScope(idUser string, selected string, engagementID string) (domain_overview.DataScope, error) {
+	engagementID = strings.TrimSpace(engagementID)
+	if engagementID != "" && strings.TrimSpace(selected) == "" {
+		// tanpa pilihan domain, engagement mencakup semua domainnya
+		selected = domain_overview.AllDomains
+	}
+	domains, err := s.ResolveDomains(idUser, selected)
+	if err != nil {
+		return domain_overview.DataScope{}, err
+	}
+	client, err := s.clientRepo.GetClientByUserID(idUser)
+	if err != nil {
+		return domain_overview.DataScope{}, errorenum.NoActiveDomain
+	}
+	rules, err := s.clientRepo.FindScopeRules(client.Id)
+	if err != nil {
+		return domain_overview.DataScope{}, errorenum.SomethingError
+	}
+	scope := domain_overview.DataScope{
+		Domains: domains,
+		From:    client.ContractStart(),
+		To:      client.ContractEnd(),
+		Rules:   make([]domain_overview.ScopeRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		scope.Rules = append(scope.Rules, domain_overview.ScopeRule{Action: rule.Action, Type: rule.Type, Pattern: rule.Pattern})
+	}
+	if engagementID == "" {
+		return scope, nil
+	}
+
+	engagement, err := s.engagementRepo.FindEngagementByID(engagementID)
+	if err != nil || engagement.IdClient != client.Id {
+		return domain_overview.DataScope{}, errorenum.EngagementNotFound
+	}
+	inScope := make([]string, 0, len(domains))
+	for _, d := range domains {
+		for _, name := range engagement.DomainNames() {
+			if strings.EqualFold(d, name) {
+				inScope = append(inScope, d)
+				break
+			}
+		}
+	}
+	if len(inScope) == 0 {
+		return domain_overview.DataScope{}, errorenum.DomainNotAllowed
+	}
+	scope.Domains = inScope
+	scope.IdEngagement = engagement.Id
+	if scope.From.IsZero() || engagement.WindowStart().After(scope.From) {
+		scope.From = engagement.WindowStart()
+	}
+	if scope.To.IsZero() || engagement.WindowEnd().Before(scope.To) {
+		scope.To = engagement.WindowEnd()
+	}
+	return scope, nil
+}
@@ -6,12 +6,10 @@ import (
 
 	"xops-admin/domain"
 	domain_overview "xops-admin/domain/user/overview"
-	"xops-admin/model"
 )
 
 type SecurityChecklistRepo struct {
 	repo                  domain.SecurityChecklistRepository
-	clientRepo            domain.ClientRepository
 	listVuln              domain.ListVulnerabilityRepository
 	bulkSecurityChecklist domain.BulkUpdateSecurityChecklistRepository
 }
@@ -45,27 +43,31 @@ func (s *SecurityChecklistRepo) ListVulnerabilityNames(ctx context.Context, sear
 }
 
 // GetURLList implements domain_overview.SecurityCheklistUseCase.
-func (s *SecurityChecklistRepo) GetURLList(ctx context.Context, flagDomain string, params domain_overview.URLListParams) (*domain_overview.URLListResponse, error) {
-	return s.repo.GetURLList(context.TODO(), flagDomain, params)
+func (s *SecurityChecklistRepo) GetURLList(ctx context.Context, scope domain_overview.DataScope, params domain_overview.URLListParams) (*domain_overview.URLListResponse, error) {
+	return s.repo.GetURLList(context.TODO(), scope, params)
+}
+
+func (s *SecurityChecklistRepo) GetOutOfScopeActivity(ctx context.Context, scope domain_overview.DataScope) (*domain_overview.OutOfScopeActivityResponse, error) {
+	return s.repo.GetOutOfScopeActivity(ctx, scope)
 }
 
 // GetSecurityChecklistDetailByESID implements domain_overview.SecurityCheklistUseCase.
-func (s *SecurityChecklistRepo) GetSecurityChecklistDetailByESID(ctx context.Context, esID string) (*domain_overview.DetailIdSecurityChecklistItem, error) {
-	return s.repo.GetSecurityChecklistDetailByESID(context.TODO(), esID)
+func (s *SecurityChecklistRepo) GetSecurityChecklistDetailByESID(ctx context.Context, esID string, scope domain_overview.DataScope) (*domain_overview.DetailIdSecurityChecklistItem, error) {
+	return s.repo.GetSecurityChecklistDetailByESID(context.TODO(), esID, scope)
 }
 
 // GetTotalFindings - existing method (unchanged)
-func (s *SecurityChecklistRepo) GetTotalFindings(ctx context.Context, domainName string) (*[]domain_overview.SeverityCountTotalFindings, error) {
-	return s.repo.GetTotalFindings(ctx, domainName)
+func (s *SecurityChecklistRepo) GetTotalFindings(ctx context.Context, scope domain_overview.DataScope) (*[]domain_overview.SeverityCountTotalFindings, error) {
+	return s.repo.GetTotalFindings(ctx, scope)
 }
 
 // GetTotalBugStatusList - new method with pagination and sorting
-func (s *SecurityChecklistRepo) GetTotalBugStatusList(ctx context.Context, domainName string) (*domain_overview.ResponseTotalBugStatusItem, error) {
-	return s.repo.GetTotalBugStatusList(ctx, domainName)
+func (s *SecurityChecklistRepo) GetTotalBugStatusList(ctx context.Context, scope domain_overview.DataScope) (*domain_overview.ResponseTotalBugStatusItem, error) {
+	return s.repo.GetTotalBugStatusList(ctx, scope)
 }
 
 // GetSecurityChecklistTable - new method with pagination and sorting
-func (s *SecurityChecklistRepo) GetSecurityChecklistTable(ctx context.Context, domainName string, params domain_overview.PaginationParams) (*domain_overview.SecurityChecklistTableResponse, error) {
+func (s *SecurityChecklistRepo) GetSecurityChecklistTable(ctx context.Context, scope domain_overview.DataScope, params domain_overview.PaginationParams) (*domain_overview.SecurityChecklistTableResponse, error) {
 	// Validate pagination parameters
 
 	// Validate sort order
@@ -76,19 +78,13 @@ func (s *SecurityChecklistRepo) GetSecurityChecklistTable(ctx context.Context, d
 		params.SortOrder = "newest" // fallback to default
 	}
 
-	return s.repo.GetSecurityChecklistTable(ctx, domainName, params)
-}
-
-// GetDomainByClientID - existing method (unchanged)
-func (s *SecurityChecklistRepo) GetDomainByClientID(id string) (*model.DomainClient, error) {
-	return s.clientRepo.GetDomainByClientID(id)
+	return s.repo.GetSecurityChecklistTable(ctx, scope, params)
 }
 
 // Constructor - updated to implement the new interface
-func NewSecurityChecklist(repo domain.SecurityChecklistRepository, clientRepo domain.ClientRepository, listVuln domain.ListVulnerabilityRepository, bulkSecurityChecklist domain.BulkUpdateSecurityChecklistRepository) domain_overview.SecurityCheklistUseCase {
+func NewSecurityChecklist(repo domain.SecurityChecklistRepository, listVuln domain.ListVulnerabilityRepository, bulkSecurityChecklist domain.BulkUpdateSecurityChecklistRepository) domain_overview.SecurityCheklistUseCase {
 	return &SecurityChecklistRepo{
 		repo:                  repo,
-		clientRepo:            clientRepo,
 		listVuln:              listVuln,
 		bulkSecurityChecklist: bulkSecurityChecklist,
 	}
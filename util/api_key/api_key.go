@@ -2,28 +2,32 @@ package util_apikey
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
-	"fmt"
-	"strings"
-	"time"
+	"encoding/hex"
 )
 
-func GenerateSecureAPIKey() string {
-	randomBytes := make([]byte, 32)
-	_, err := rand.Read(randomBytes)
-	if err != nil {
-		return ""
-	}
-
-	randomString := base64.URLEncoding.EncodeToString(randomBytes)
-
-	timestamp := time.Now().Unix()
+// ApiKeyPrefix awalan semua API key supaya mudah dikenali (misal oleh secret scanner)
+const ApiKeyPrefix = "xops_"
 
-	apiKey := fmt.Sprintf("%s_%d_%s",
-		"sk",
-		timestamp,
-		strings.TrimRight(randomString, "="),
-	)
+// GenerateAPIKey menghasilkan key lengkap dan prefix yang boleh ditampilkan.
+// Format: xops_<8 hex prefix>_<secret>
+func GenerateAPIKey() (key string, prefix string, err error) {
+	prefixBytes := make([]byte, 4)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	secretBytes := make([]byte, 32)
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+	prefix = ApiKeyPrefix + hex.EncodeToString(prefixBytes)
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(secretBytes)
+	return key, prefix, nil
+}
 
-	return apiKey
+// HashAPIKey key sudah acak 256 bit jadi cukup sha256, tidak perlu bcrypt
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
 }
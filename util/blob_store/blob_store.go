@@ -0,0 +1,66 @@
+package util_blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"xops-admin/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore penyimpanan file upload (logo client, lampiran) di luar folder yang di-serve publik.
+// File hanya bisa diakses lewat URL bertanda tangan yang berlaku singkat.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Open mengembalikan isi file beserta content type-nya, ErrNotFound jika tidak ada
+	Open(ctx context.Context, key string) (io.ReadCloser, string, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+
+	defaultLocalDir = "storage/blobs"
+)
+
+// NewFromConfig memilih backend sesuai BLOB_BACKEND, default disk lokal
+func NewFromConfig(cfg *config.InitConfig) (BlobStore, error) {
+	switch strings.ToLower(cfg.BlobBackend) {
+	case "", BackendLocal:
+		dir := cfg.BlobLocalDir
+		if dir == "" {
+			dir = defaultLocalDir
+		}
+		return NewLocalStore(dir, cfg.BlobPublicURL, cfg.LinkSigningSecret)
+	case BackendS3:
+		return NewS3Store(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	}
+	return nil, fmt.Errorf("unknown blob backend %q", cfg.BlobBackend)
+}
+
+// validKey mencegah key keluar dari folder / bucket, misalnya "../app.env"
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
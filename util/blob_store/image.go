@@ -0,0 +1,63 @@
+package util_blobstore
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	// decoder GIF didaftarkan supaya logo GIF bisa dikonversi ke PNG
+	_ "image/gif"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image type")
+	ErrImageTooLarge    = errors.New("image too large")
+)
+
+// batas dimensi supaya file kecil dengan resolusi raksasa tidak menghabiskan memori saat decode
+const maxImagePixels = 4096 * 4096
+
+// SanitizedImage hasil encode ulang, tanpa metadata EXIF / komentar dari file asli
+type SanitizedImage struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+// SanitizeImage mengenali tipe file dari magic bytes (bukan ekstensi / header Content-Type),
+// hanya menerima PNG, JPEG dan GIF, lalu meng-encode ulang gambarnya. GIF disimpan sebagai PNG.
+func SanitizeImage(data []byte, maxBytes int64) (*SanitizedImage, error) {
+	if int64(len(data)) > maxBytes {
+		return nil, ErrImageTooLarge
+	}
+	detected := http.DetectContentType(data)
+	if detected != "image/png" && detected != "image/jpeg" && detected != "image/gif" {
+		return nil, ErrUnsupportedImage
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	var out bytes.Buffer
+	if detected == "image/jpeg" {
+		if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, err
+		}
+		return &SanitizedImage{Data: out.Bytes(), ContentType: "image/jpeg", Ext: ".jpg"}, nil
+	}
+	if err := png.Encode(&out, img); err != nil {
+		return nil, err
+	}
+	return &SanitizedImage{Data: out.Bytes(), ContentType: "image/png", Ext: ".png"}, nil
+}
@@ -0,0 +1,107 @@
+package util_blobstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodeTestImage(t *testing.T, format string) []byte {
+	t.Helper()
+	img := image.NewPaletted(image.Rect(0, 0, 8, 8), color.Palette{color.White, color.Black})
+	img.SetColorIndex(3, 3, 1)
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSanitizeImage(t *testing.T) {
+	cases := map[string]struct {
+		contentType, ext string
+	}{
+		"png":  {"image/png", ".png"},
+		"jpeg": {"image/jpeg", ".jpg"},
+		"gif":  {"image/png", ".png"},
+	}
+	for format, want := range cases {
+		t.Run(format, func(t *testing.T) {
+			img, err := SanitizeImage(encodeTestImage(t, format), 1<<20)
+			if err != nil {
+				t.Fatalf("SanitizeImage: %v", err)
+			}
+			if img.ContentType != want.contentType || img.Ext != want.ext {
+				t.Errorf("got %s %s, want %s %s", img.ContentType, img.Ext, want.contentType, want.ext)
+			}
+		})
+	}
+}
+
+// polyglot: PNG valid dengan HTML / zip di belakangnya, hasil encode ulang tidak boleh membawanya
+func TestSanitizeImageStripsPolyglotPayload(t *testing.T) {
+	payload := []byte("<script>alert(document.cookie)</script>PK\x03\x04")
+	data := append(encodeTestImage(t, "png"), payload...)
+
+	img, err := SanitizeImage(data, 1<<20)
+	if err != nil {
+		t.Fatalf("SanitizeImage: %v", err)
+	}
+	if bytes.Contains(img.Data, []byte("<script>")) || bytes.Contains(img.Data, []byte("PK\x03\x04")) {
+		t.Error("payload survived re-encoding")
+	}
+}
+
+func TestSanitizeImageRejects(t *testing.T) {
+	cases := map[string]struct {
+		data []byte
+		want error
+	}{
+		// polyglot GIF/JS: "/*" terbaca sebagai lebar 10799 piksel sehingga ditolak sebelum decode
+		"gif magic with script": {[]byte("GIF89a/*<script>alert(1)</script>*/"), ErrImageTooLarge},
+		"svg":                   {[]byte(`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"/>`), ErrUnsupportedImage},
+		"html":                  {[]byte("<!DOCTYPE html><html><body>hi</body></html>"), ErrUnsupportedImage},
+		"truncated png":         {encodeTestImage(t, "png")[:40], ErrUnsupportedImage},
+		"empty":                 {[]byte{}, ErrUnsupportedImage},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := SanitizeImage(tc.data, 1<<20); !errors.Is(err, tc.want) {
+				t.Fatalf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeImageTooLarge(t *testing.T) {
+	data := encodeTestImage(t, "png")
+	if _, err := SanitizeImage(data, int64(len(data)-1)); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("oversized file: err = %v", err)
+	}
+
+	// file kecil yang mengaku berukuran 20000x20000 lewat header IHDR
+	bomb := append([]byte(nil), data...)
+	ihdr := bomb[16:29]
+	binary.BigEndian.PutUint32(ihdr[0:4], 20000)
+	binary.BigEndian.PutUint32(ihdr[4:8], 20000)
+	binary.BigEndian.PutUint32(bomb[29:33], crc32.ChecksumIEEE(bomb[12:29]))
+	if _, err := SanitizeImage(bomb, 1<<20); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("oversized dimensions: err = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package util_blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	util_signedtoken "xops-admin/util/signed_token"
+)
+
+// SignedURLPurpose purpose token link unduhan blob lokal
+const SignedURLPurpose = "blob_download"
+
+// LocalStore menyimpan blob di disk, sebaiknya di volume yang tidak ikut hilang saat container dibuat ulang.
+// Content type disimpan di file pendamping ".type".
+type LocalStore struct {
+	dir     string
+	baseURL string
+	secret  string
+}
+
+func NewLocalStore(dir, baseURL, secret string) (*LocalStore, error) {
+	if secret == "" {
+		return nil, errors.New("local blob store requires LINK_SIGNING_SECRET")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &LocalStore{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/"), secret: secret}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0640); err != nil {
+		return err
+	}
+	return os.WriteFile(path+".type", []byte(contentType), 0640)
+}
+
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, "", ErrNotFound
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := "application/octet-stream"
+	if raw, err := os.ReadFile(path + ".type"); err == nil && len(raw) > 0 {
+		contentType = string(raw)
+	}
+	return file, contentType, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	os.Remove(path + ".type")
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL link ke endpoint /api/blobs yang memverifikasi token sebelum mengirim file
+func (s *LocalStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	token, _, err := util_signedtoken.Generate(s.secret, SignedURLPurpose, key, ttl)
+	if err != nil {
+		return "", err
+	}
+	return s.baseURL + "/api/blobs?token=" + url.QueryEscape(token), nil
+}
+
+// KeyFromToken key blob dari token link unduhan, error jika token tidak valid atau kedaluwarsa
+func (s *LocalStore) KeyFromToken(token string) (string, error) {
+	claims, err := util_signedtoken.Parse(s.secret, SignedURLPurpose, token)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
@@ -0,0 +1,149 @@
+package util_blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	util_signedtoken "xops-admin/util/signed_token"
+)
+
+const testSecret = "test-link-signing-secret"
+
+func TestValidKey(t *testing.T) {
+	cases := map[string]bool{
+		"logos/abc.png":      true,
+		"a/b/c.txt":          true,
+		"":                   false,
+		"../app.env":         false,
+		"logos/../app.env":   false,
+		"logos/./abc.png":    false,
+		"/etc/passwd":        false,
+		"logos//abc.png":     false,
+		"logos/":             false,
+		`logos\..\app.env`:   false,
+		"logos/abc.png/..":   false,
+		"..":                 false,
+		"logos/..hidden.png": true,
+	}
+	for key, want := range cases {
+		if got := validKey(key); got != want {
+			t.Errorf("validKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func newTestLocalStore(t *testing.T) (*LocalStore, string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "blobs")
+	store, err := NewLocalStore(dir, "https://api.example.com/", testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, dir
+}
+
+func TestLocalStoreRoundtrip(t *testing.T) {
+	store, _ := newTestLocalStore(t)
+	ctx := context.Background()
+	if err := store.Put(ctx, "logos/a.png", []byte("data"), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	file, contentType, err := store.Open(ctx, "logos/a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(file)
+	file.Close()
+	if string(body) != "data" || contentType != "image/png" {
+		t.Errorf("got %q %q", body, contentType)
+	}
+	if err := store.Delete(ctx, "logos/a.png"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Open(ctx, "logos/a.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("after delete: err = %v", err)
+	}
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	store, dir := newTestLocalStore(t)
+	ctx := context.Background()
+	secret := filepath.Join(filepath.Dir(dir), "app.env")
+	if err := os.WriteFile(secret, []byte("SECRET=1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put(ctx, "../escaped.txt", []byte("x"), "text/plain"); err == nil {
+		t.Error("Put accepted ../ key")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escaped.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("file written outside blob directory")
+	}
+	if _, _, err := store.Open(ctx, "../app.env"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open ../app.env: err = %v", err)
+	}
+	if err := store.Delete(ctx, "../app.env"); err == nil {
+		t.Error("Delete accepted ../ key")
+	}
+	if _, err := os.Stat(secret); err != nil {
+		t.Error("file outside blob directory was removed")
+	}
+	if _, err := store.SignedURL(ctx, "../app.env", time.Minute); err == nil {
+		t.Error("SignedURL accepted ../ key")
+	}
+}
+
+func tokenFromURL(t *testing.T, raw string) string {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "api.example.com" || u.Path != "/api/blobs" {
+		t.Fatalf("unexpected signed url %s", raw)
+	}
+	return u.Query().Get("token")
+}
+
+func TestLocalStoreSignedURL(t *testing.T) {
+	store, _ := newTestLocalStore(t)
+	raw, err := store.SignedURL(context.Background(), "logos/a.png", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := tokenFromURL(t, raw)
+
+	key, err := store.KeyFromToken(token)
+	if err != nil || key != "logos/a.png" {
+		t.Fatalf("KeyFromToken = %q, %v", key, err)
+	}
+	if _, err := store.KeyFromToken(token + "x"); err == nil {
+		t.Error("tampered token accepted")
+	}
+	other, _ := NewLocalStore(t.TempDir(), "https://api.example.com", "another-secret")
+	if _, err := other.KeyFromToken(token); err == nil {
+		t.Error("token accepted with another secret")
+	}
+	// token dengan secret sama tapi purpose lain (misalnya reset password) tidak berlaku
+	foreign, _, _ := util_signedtoken.Generate(testSecret, "password_reset", "logos/a.png", time.Minute)
+	if _, err := store.KeyFromToken(foreign); err == nil {
+		t.Error("token with another purpose accepted")
+	}
+}
+
+func TestLocalStoreSignedURLExpires(t *testing.T) {
+	store, _ := newTestLocalStore(t)
+	raw, err := store.SignedURL(context.Background(), "logos/a.png", -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.KeyFromToken(tokenFromURL(t, raw)); !errors.Is(err, util_signedtoken.ErrExpiredToken) {
+		t.Fatalf("err = %v, want ErrExpiredToken", err)
+	}
+}
@@ -0,0 +1,171 @@
+package util_domainverify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	MethodDNS  = "dns"
+	MethodHTTP = "http"
+
+	// TxtRecordPrefix subdomain tempat record TXT, nilainya TxtValuePrefix + token
+	TxtRecordPrefix = "_xops-verification."
+	TxtValuePrefix  = "xops-verification="
+	// WellKnownPath file yang isinya persis token
+	WellKnownPath = "/.well-known/xops-verification.txt"
+
+	maxFileSize = 4096
+)
+
+var (
+	ErrNotVerified        = errors.New("verification record not found")
+	ErrRedirectNotAllowed = errors.New("redirect to another host is not allowed")
+	ErrInternalAddress    = errors.New("domain resolves to an internal address")
+)
+
+// Resolver sama dengan method di *net.Resolver, bisa diganti stub saat test
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Fetcher mengambil isi file verifikasi, bisa diganti stub saat test
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+}
+
+type Verifier struct {
+	Resolver Resolver
+	Fetcher  Fetcher
+}
+
+// NewVerifier memakai DNS sistem dan HTTP client dengan timeout jika argumen nil
+func NewVerifier(resolver Resolver, fetcher Fetcher) *Verifier {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	if fetcher == nil {
+		fetcher = &HTTPFetcher{Client: newHTTPClient()}
+	}
+	return &Verifier{Resolver: resolver, Fetcher: fetcher}
+}
+
+// newHTTPClient client untuk mengambil file verifikasi dari domain yang diisi admin. Redirect
+// hanya diikuti dalam host yang sama dan koneksi ke alamat internal ditolak supaya fitur ini
+// tidak bisa dipakai untuk menjangkau jaringan internal (SSRF).
+func newHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: refuseInternalAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:       10 * time.Second,
+		Transport:     transport,
+		CheckRedirect: sameHostRedirect,
+	}
+}
+
+func sameHostRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 3 {
+		return http.ErrUseLastResponse
+	}
+	if !strings.EqualFold(req.URL.Hostname(), via[0].URL.Hostname()) {
+		return ErrRedirectNotAllowed
+	}
+	return nil
+}
+
+// refuseInternalAddress dipanggil setelah DNS di-resolve, jadi juga menahan DNS rebinding
+func refuseInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return ErrInternalAddress
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || isSharedAddress(ip))
+}
+
+// 100.64.0.0/10 (carrier-grade NAT) tidak termasuk IsPrivate tapi juga bukan alamat publik
+func isSharedAddress(ip net.IP) bool {
+	ip4 := ip.To4()
+	return ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64
+}
+
+func NewToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func TxtRecordName(domain string) string {
+	return TxtRecordPrefix + domain
+}
+
+func FileURL(domain string) string {
+	return "https://" + domain + WellKnownPath
+}
+
+// Verify mencoba record TXT dulu lalu file well-known, dan mengembalikan metode yang berhasil.
+// Error terakhir dikembalikan supaya admin tahu kenapa verifikasi gagal.
+func (v *Verifier) Verify(ctx context.Context, domain, token string) (string, error) {
+	records, dnsErr := v.Resolver.LookupTXT(ctx, TxtRecordName(domain))
+	if dnsErr == nil {
+		for _, record := range records {
+			if strings.TrimSpace(record) == TxtValuePrefix+token {
+				return MethodDNS, nil
+			}
+		}
+		dnsErr = ErrNotVerified
+	}
+
+	body, httpErr := v.Fetcher.Fetch(ctx, FileURL(domain))
+	if httpErr == nil {
+		if strings.TrimSpace(string(body)) == token {
+			return MethodHTTP, nil
+		}
+		httpErr = ErrNotVerified
+	}
+	return "", fmt.Errorf("dns: %v; http: %v", dnsErr, httpErr)
+}
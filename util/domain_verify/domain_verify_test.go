@@ -0,0 +1,171 @@
+package util_domainverify
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const testToken = "0123456789abcdef"
+
+type stubResolver struct {
+	records map[string][]string
+	// block menahan lookup sampai context selesai, untuk menguji timeout
+	block bool
+}
+
+func (r *stubResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if r.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	records, ok := r.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+type stubFetcher struct {
+	files map[string]string
+	block bool
+	urls  []string
+}
+
+func (f *stubFetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+	f.urls = append(f.urls, url)
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	body, ok := f.files[url]
+	if !ok {
+		return nil, errors.New("unexpected status 404")
+	}
+	return []byte(body), nil
+}
+
+func TestVerifyTXTRecord(t *testing.T) {
+	resolver := &stubResolver{records: map[string][]string{
+		"_xops-verification.example.com": {"google-site-verification=abc", " xops-verification=" + testToken + " "},
+	}}
+	fetcher := &stubFetcher{}
+	method, err := NewVerifier(resolver, fetcher).Verify(context.Background(), "example.com", testToken)
+	if err != nil || method != MethodDNS {
+		t.Fatalf("Verify = %q, %v; want dns", method, err)
+	}
+	if len(fetcher.urls) != 0 {
+		t.Error("file fetched although TXT record matched")
+	}
+}
+
+func TestVerifyWellKnownFile(t *testing.T) {
+	fetcher := &stubFetcher{files: map[string]string{
+		"https://example.com/.well-known/xops-verification.txt": testToken + "\n",
+	}}
+	method, err := NewVerifier(&stubResolver{}, fetcher).Verify(context.Background(), "example.com", testToken)
+	if err != nil || method != MethodHTTP {
+		t.Fatalf("Verify = %q, %v; want http", method, err)
+	}
+}
+
+func TestVerifyWrongToken(t *testing.T) {
+	resolver := &stubResolver{records: map[string][]string{
+		"_xops-verification.example.com": {"xops-verification=other-token"},
+	}}
+	fetcher := &stubFetcher{files: map[string]string{
+		"https://example.com/.well-known/xops-verification.txt": "other-token",
+	}}
+	if method, err := NewVerifier(resolver, fetcher).Verify(context.Background(), "example.com", testToken); err == nil {
+		t.Fatalf("Verify = %q, want error", method)
+	}
+	// token yang hanya berupa awalan dari nilai record tidak boleh lolos
+	resolver.records["_xops-verification.example.com"] = []string{"xops-verification=" + testToken + "-extra"}
+	if _, err := NewVerifier(resolver, &stubFetcher{}).Verify(context.Background(), "example.com", testToken); err == nil {
+		t.Fatal("prefix match accepted")
+	}
+}
+
+func TestVerifyTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := NewVerifier(&stubResolver{block: true}, &stubFetcher{block: true}).Verify(ctx, "example.com", testToken)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected error after timeout")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Verify did not honour the context deadline")
+	}
+}
+
+func TestSameHostRedirect(t *testing.T) {
+	request := func(raw string) *http.Request {
+		u, _ := url.Parse(raw)
+		return &http.Request{URL: u}
+	}
+	via := []*http.Request{request("https://example.com/.well-known/xops-verification.txt")}
+
+	if err := sameHostRedirect(request("https://EXAMPLE.com/verify.txt"), via); err != nil {
+		t.Errorf("same host redirect refused: %v", err)
+	}
+	if err := sameHostRedirect(request("https://evil.example.net/token"), via); !errors.Is(err, ErrRedirectNotAllowed) {
+		t.Errorf("cross host redirect: err = %v", err)
+	}
+	if err := sameHostRedirect(request("http://169.254.169.254/latest/meta-data"), via); !errors.Is(err, ErrRedirectNotAllowed) {
+		t.Errorf("redirect to metadata address: err = %v", err)
+	}
+	long := []*http.Request{via[0], via[0], via[0]}
+	if err := sameHostRedirect(request("https://example.com/again"), long); err != http.ErrUseLastResponse {
+		t.Errorf("too many redirects: err = %v", err)
+	}
+}
+
+func TestRefuseInternalAddress(t *testing.T) {
+	cases := map[string]bool{
+		"93.184.216.34:443":   true,
+		"[2606:4700::1]:443":  true,
+		"127.0.0.1:443":       false,
+		"10.1.2.3:443":        false,
+		"172.16.0.1:443":      false,
+		"192.168.1.1:443":     false,
+		"169.254.169.254:80":  false,
+		"100.64.0.1:443":      false,
+		"0.0.0.0:443":         false,
+		"[::1]:443":           false,
+		"[fd00::1]:443":       false,
+		"[fe80::1]:443":       false,
+		"[::ffff:10.0.0.1]:0": false,
+	}
+	for address, allowed := range cases {
+		err := refuseInternalAddress("tcp", address, nil)
+		if allowed && err != nil {
+			t.Errorf("%s refused: %v", address, err)
+		}
+		if !allowed && !errors.Is(err, ErrInternalAddress) {
+			t.Errorf("%s allowed", address)
+		}
+	}
+}
+
+func TestHTTPFetcherRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testToken))
+	}))
+	defer server.Close()
+
+	fetcher := &HTTPFetcher{Client: newHTTPClient()}
+	if _, err := fetcher.Fetch(context.Background(), server.URL+WellKnownPath); !errors.Is(err, ErrInternalAddress) {
+		t.Fatalf("err = %v, want ErrInternalAddress", err)
+	}
+}
@@ -0,0 +1,243 @@
+package util_oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	testClientID = "xops-client"
+	testSecret   = "s3cret"
+	testCode     = "auth-code"
+	testKid      = "key-1"
+)
+
+// testProvider IdP tiruan: discovery, JWKS dan token endpoint yang memeriksa PKCE
+type testProvider struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	idToken   string
+	// metadata diubah sebelum dikirim, untuk menguji discovery yang salah
+	mutate func(m map[string]string)
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		m := map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		}
+		if p.mutate != nil {
+			p.mutate(m)
+		}
+		json.NewEncoder(w).Encode(m)
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": testKid,
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		id, secret, _ := r.BasicAuth()
+		switch {
+		case r.Form.Get("grant_type") != "authorization_code",
+			r.Form.Get("code") != testCode,
+			id != testClientID || secret != testSecret,
+			CodeChallenge(r.Form.Get("code_verifier")) != p.challenge:
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     p.idToken,
+		})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKid
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (p *testProvider) claims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            p.server.URL,
+		"aud":            testClientID,
+		"sub":            "user-1",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"name":           "Alice",
+		"nonce":          nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	p := newTestProvider(t)
+	metadata, err := Discover(context.Background(), p.server.URL+"/")
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	if metadata.TokenEndpoint != p.server.URL+"/token" || metadata.JwksURI != p.server.URL+"/jwks" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+}
+
+func TestDiscoverRejectsBadMetadata(t *testing.T) {
+	cases := map[string]func(m map[string]string){
+		"issuer mismatch":  func(m map[string]string) { m["issuer"] = "https://evil.example.com" },
+		"missing jwks_uri": func(m map[string]string) { delete(m, "jwks_uri") },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := newTestProvider(t)
+			p.mutate = mutate
+			if _, err := Discover(context.Background(), p.server.URL); err == nil {
+				t.Fatal("expected discovery error")
+			}
+		})
+	}
+}
+
+func TestAuthCodeURLUsesPKCE(t *testing.T) {
+	metadata := &ProviderMetadata{AuthorizationEndpoint: "https://idp.example.com/authorize?tenant=x"}
+	raw := AuthCodeURL(metadata, testClientID, "https://app.example.com/cb", "state", "nonce", "verifier")
+	if !strings.HasPrefix(raw, "https://idp.example.com/authorize?tenant=x&") {
+		t.Fatalf("query not appended: %s", raw)
+	}
+	for _, want := range []string{"code_challenge=" + CodeChallenge("verifier"), "code_challenge_method=S256", "nonce=nonce", "state=state"} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("%s missing %s", raw, want)
+		}
+	}
+}
+
+func TestExchangeCode(t *testing.T) {
+	p := newTestProvider(t)
+	verifier, _ := RandomString()
+	p.challenge = CodeChallenge(verifier)
+	p.idToken = "raw-id-token"
+	metadata, err := Discover(context.Background(), p.server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idToken, err := ExchangeCode(context.Background(), metadata, testClientID, testSecret, testCode, "https://app.example.com/cb", verifier)
+	if err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+	if idToken != "raw-id-token" {
+		t.Errorf("id_token = %q", idToken)
+	}
+
+	if _, err := ExchangeCode(context.Background(), metadata, testClientID, testSecret, testCode, "https://app.example.com/cb", "wrong-verifier"); err == nil {
+		t.Error("expected error for wrong code_verifier")
+	}
+	if _, err := ExchangeCode(context.Background(), metadata, testClientID, testSecret, "other-code", "https://app.example.com/cb", verifier); err == nil {
+		t.Error("expected error for unknown code")
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	p := newTestProvider(t)
+	metadata, err := Discover(context.Background(), p.server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := VerifyIDToken(context.Background(), metadata, testClientID, "n-1", p.sign(t, p.claims("n-1")))
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if claims.Email != "alice@example.com" || !claims.EmailVerified || claims.Subject != "user-1" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]func() string{
+		"wrong nonce": func() string { return p.sign(t, p.claims("n-2")) },
+		"wrong issuer": func() string {
+			c := p.claims("n-1")
+			c["iss"] = "https://evil.example.com"
+			return p.sign(t, c)
+		},
+		"wrong audience": func() string {
+			c := p.claims("n-1")
+			c["aud"] = "other-client"
+			return p.sign(t, c)
+		},
+		"expired": func() string {
+			c := p.claims("n-1")
+			c["exp"] = time.Now().Add(-time.Minute).Unix()
+			return p.sign(t, c)
+		},
+		"missing exp": func() string {
+			c := p.claims("n-1")
+			delete(c, "exp")
+			return p.sign(t, c)
+		},
+		"signed by unknown key": func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims("n-1"))
+			token.Header["kid"] = testKid
+			signed, _ := token.SignedString(otherKey)
+			return signed
+		},
+		"hmac algorithm": func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, p.claims("n-1"))
+			token.Header["kid"] = testKid
+			signed, _ := token.SignedString([]byte("secret"))
+			return signed
+		},
+	}
+	for name, token := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := VerifyIDToken(context.Background(), metadata, testClientID, "n-1", token()); err == nil {
+				t.Fatal("expected id_token to be rejected")
+			}
+		})
+	}
+}
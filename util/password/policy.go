@@ -0,0 +1,87 @@
+package util_password
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"xops-admin/config"
+	"xops-admin/helper/errorenum"
+)
+
+const (
+	defaultMinLength = 12
+	// maxLength batas input bcrypt, byte sesudahnya ditolak oleh bcrypt
+	maxLength = 72
+)
+
+// PolicyError daftar aturan password yang belum terpenuhi, dikirim ke client sebagai array pesan
+type PolicyError struct {
+	Messages []string
+}
+
+func (e *PolicyError) Error() string {
+	return strings.Join(e.Messages, "; ")
+}
+
+func (e *PolicyError) Is(target error) bool {
+	return target == errorenum.WeakPassword
+}
+
+func (e *PolicyError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Messages)
+}
+
+// CheckPolicy ValidatePolicy dalam bentuk error, nil berarti valid
+func CheckPolicy(password string) error {
+	if messages := ValidatePolicy(password); len(messages) > 0 {
+		return &PolicyError{Messages: messages}
+	}
+	return nil
+}
+
+// ValidatePolicy mengecek password terhadap policy di config dan mengembalikan
+// daftar aturan yang belum terpenuhi (kosong berarti valid)
+func ValidatePolicy(password string) []string {
+	loadconfig, _ := config.LoadConfig(".")
+	minLength := loadconfig.PasswordMinLength
+	if minLength <= 0 {
+		minLength = defaultMinLength
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	var messages []string
+	if len([]rune(password)) < minLength {
+		messages = append(messages, fmt.Sprintf("Password must be at least %d characters", minLength))
+	}
+	if len(password) > maxLength {
+		messages = append(messages, fmt.Sprintf("Password must be at most %d bytes", maxLength))
+	}
+	if loadconfig.PasswordRequireUpper && !hasUpper {
+		messages = append(messages, "Password must contain an uppercase letter")
+	}
+	if loadconfig.PasswordRequireLower && !hasLower {
+		messages = append(messages, "Password must contain a lowercase letter")
+	}
+	if loadconfig.PasswordRequireDigit && !hasDigit {
+		messages = append(messages, "Password must contain a number")
+	}
+	if loadconfig.PasswordRequireSymbol && !hasSymbol {
+		messages = append(messages, "Password must contain a symbol")
+	}
+	return messages
+}
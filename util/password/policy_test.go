@@ -0,0 +1,46 @@
+package util_password
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"xops-admin/helper/errorenum"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	if err := CheckPolicy("correct-horse-battery"); err != nil {
+		t.Fatalf("valid password rejected: %v", err)
+	}
+
+	err := CheckPolicy("short")
+	if !errors.Is(err, errorenum.WeakPassword) {
+		t.Fatalf("err = %v, want WeakPassword", err)
+	}
+	// pesan aturan yang gagal ikut terkirim ke client, bukan hanya "password policy"
+	body, _ := json.Marshal(map[string]any{"message": err})
+	if !strings.Contains(string(body), "at least 12 characters") {
+		t.Errorf("response body %s does not name the failed rule", body)
+	}
+}
+
+// password di atas 72 byte ditolak policy sebelum sampai ke bcrypt
+func TestCheckPolicyRejectsOverBcryptLimit(t *testing.T) {
+	exact := strings.Repeat("a", maxLength)
+	if err := CheckPolicy(exact); err != nil {
+		t.Fatalf("%d byte password rejected: %v", maxLength, err)
+	}
+	if _, err := bcrypt.GenerateFromPassword([]byte(exact), bcrypt.MinCost); err != nil {
+		t.Fatalf("bcrypt rejected %d bytes: %v", maxLength, err)
+	}
+
+	// 40 karakter multibyte = 80 byte walau hanya 40 rune
+	long := strings.Repeat("é", 40)
+	var policyErr *PolicyError
+	if err := CheckPolicy(long); !errors.As(err, &policyErr) || len(policyErr.Messages) != 1 || !strings.Contains(policyErr.Messages[0], "72 bytes") {
+		t.Fatalf("err = %v, want only the 72 byte rule", err)
+	}
+}
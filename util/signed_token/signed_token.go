@@ -0,0 +1,80 @@
+package util_signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// Claims adalah isi token link (reset password, aktivasi akun, ...)
+type Claims struct {
+	Purpose   string
+	Subject   string
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// Generate membuat token "payload.signature" yang ditandatangani HMAC-SHA256.
+// Nonce dikembalikan supaya pemanggil bisa menyimpannya untuk menjamin sekali pakai.
+func Generate(secret, purpose, subject string, ttl time.Duration) (string, *Claims, error) {
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", nil, err
+	}
+	claims := &Claims{
+		Purpose:   purpose,
+		Subject:   subject,
+		Nonce:     hex.EncodeToString(nonceBytes),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	payload := strings.Join([]string{claims.Purpose, claims.Subject, strconv.FormatInt(claims.ExpiresAt.Unix(), 10), claims.Nonce}, "|")
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + sign(secret, encoded), claims, nil
+}
+
+// Parse memverifikasi tanda tangan, purpose dan masa berlaku token
+func Parse(secret, purpose, token string) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(secret, encoded))) {
+		return nil, ErrInvalidToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 4 || parts[0] != purpose {
+		return nil, ErrInvalidToken
+	}
+	exp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	claims := &Claims{
+		Purpose:   parts[0],
+		Subject:   parts[1],
+		Nonce:     parts[3],
+		ExpiresAt: time.Unix(exp, 0),
+	}
+	if time.Now().After(claims.ExpiresAt) {
+		return nil, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+func sign(secret, encoded string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package util_jwttoken
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// keyring berisi kunci publik aktif (urutan pertama) dan kunci yang sudah dipensiunkan.
+// Token lama yang ditandatangani kunci pensiun tetap valid sampai kedaluwarsa.
+type keyring struct {
+	activeKid string
+	keys      map[string]*rsa.PublicKey
+	order     []string
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+var (
+	keyringCache   = map[string]*keyring{}
+	privateCache   = map[string]*rsa.PrivateKey{}
+	keyringCacheMu sync.RWMutex
+)
+
+// KeyID thumbprint RFC 7638 dari kunci publik, dipakai sebagai header "kid"
+func KeyID(key *rsa.PublicKey) string {
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// parseKeyring membaca daftar kunci publik base64 PEM yang dipisah koma
+func parseKeyring(publicKeys string) (*keyring, error) {
+	keyringCacheMu.RLock()
+	cached, ok := keyringCache[publicKeys]
+	keyringCacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	ring := &keyring{keys: map[string]*rsa.PublicKey{}}
+	for _, encoded := range strings.Split(publicKeys, ",") {
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("err: %w", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(decoded)
+		if err != nil {
+			return nil, fmt.Errorf("err : %w", err)
+		}
+		kid := KeyID(key)
+		if _, exists := ring.keys[kid]; exists {
+			continue
+		}
+		if ring.activeKid == "" {
+			ring.activeKid = kid
+		}
+		ring.keys[kid] = key
+		ring.order = append(ring.order, kid)
+	}
+	if ring.activeKid == "" {
+		return nil, fmt.Errorf("err : empty keyring")
+	}
+
+	keyringCacheMu.Lock()
+	keyringCache[publicKeys] = ring
+	keyringCacheMu.Unlock()
+	return ring, nil
+}
+
+func parsePrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	keyringCacheMu.RLock()
+	cached, ok := privateCache[privateKey]
+	keyringCacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+	//decode privatekey base64
+	decodedPrivateKey, err := base64.StdEncoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("err: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(decodedPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("err: %w", err)
+	}
+	keyringCacheMu.Lock()
+	privateCache[privateKey] = key
+	keyringCacheMu.Unlock()
+	return key, nil
+}
+
+// lookup mencari kunci berdasarkan kid. Token lama tanpa kid hanya dicek dengan kunci aktif.
+func (r *keyring) lookup(kid string) (*rsa.PublicKey, error) {
+	if kid == "" {
+		return r.keys[r.activeKid], nil
+	}
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("err : unknown kid %s", kid)
+	}
+	return key, nil
+}
+
+// JWKS mengubah keyring menjadi JSON Web Key Set untuk /.well-known/jwks.json
+func JWKS(publicKeys string) (*JSONWebKeySet, error) {
+	ring, err := parseKeyring(publicKeys)
+	if err != nil {
+		return nil, err
+	}
+	set := &JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(ring.order))}
+	for _, kid := range ring.order {
+		key := ring.keys[kid]
+		set.Keys = append(set.Keys, JSONWebKey{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	return set, nil
+}
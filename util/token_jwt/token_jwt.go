@@ -1,8 +1,8 @@
 package util_jwttoken
 
 import (
-	"encoding/base64"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -14,37 +14,69 @@ type TokenDetails struct {
 	Token     *string
 	TokenUuid string
 	UserID    string
+	SessionID string
 	ExpiresIn *int64
+	// IssuedAt unix milidetik, dibandingkan dengan waktu revoke token user
+	IssuedAt int64
+	// terisi hanya untuk token impersonation
+	ImpersonatorID  string
+	ImpersonationID string
 }
 
 func GenerateTokenJwt(jwtTokenTime time.Duration, userID string, privateKey string) (*TokenDetails, error) {
+	return GenerateSessionTokenJwt(jwtTokenTime, userID, "", privateKey)
+}
+
+// GenerateSessionTokenJwt sama seperti GenerateTokenJwt tapi menyertakan claim "sid"
+// supaya token bisa dikaitkan ke satu model.Session
+func GenerateSessionTokenJwt(jwtTokenTime time.Duration, userID string, sessionID string, privateKey string) (*TokenDetails, error) {
+	return generateToken(jwtTokenTime, userID, sessionID, "", "", privateKey)
+}
+
+// GenerateImpersonationTokenJwt token akses atas nama userID yang dipakai adminID.
+// Admin dicatat di claim "act" (RFC 8693) dan id audit di claim "imp", tanpa "sid"
+// sehingga token tidak terikat ke session dan tidak bisa di-refresh.
+func GenerateImpersonationTokenJwt(jwtTokenTime time.Duration, userID string, adminID string, impersonationID string, privateKey string) (*TokenDetails, error) {
+	return generateToken(jwtTokenTime, userID, "", adminID, impersonationID, privateKey)
+}
+
+func generateToken(jwtTokenTime time.Duration, userID, sessionID, adminID, impersonationID, privateKey string) (*TokenDetails, error) {
 	time := time.Now()
 	tokenDetail := &TokenDetails{
 		ExpiresIn: new(int64),
 		Token:     new(string),
 	}
 	*tokenDetail.ExpiresIn = time.Add(jwtTokenTime).Unix()
+	tokenDetail.IssuedAt = time.UnixMilli()
 	tokenDetail.TokenUuid = util_uuid.GenerateID()
 	tokenDetail.UserID = userID
-	//decode privatekey base64
-	decodedPrivateKey, err := base64.StdEncoding.DecodeString(privateKey)
-	if err != nil {
-		return nil, fmt.Errorf("err: %w", err)
-	}
-	//verifikasi lewat rsa
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(decodedPrivateKey)
+	tokenDetail.SessionID = sessionID
+	tokenDetail.ImpersonatorID = adminID
+	tokenDetail.ImpersonationID = impersonationID
+	key, err := parsePrivateKey(privateKey)
 	if err != nil {
-		return nil, fmt.Errorf("err: %w", err)
+		return nil, err
 	}
 
 	atClaims := make(jwt.MapClaims)
 	atClaims["sub"] = userID
 	atClaims["token_uuid"] = tokenDetail.TokenUuid
 	atClaims["exp"] = tokenDetail.ExpiresIn
-	atClaims["iat"] = time.Unix()
+	// NumericDate boleh pecahan (RFC 7519), iat disimpan sampai milidetik supaya token yang
+	// terbit tepat setelah revoke di detik yang sama tidak ikut ditolak
+	atClaims["iat"] = float64(tokenDetail.IssuedAt) / 1000
 	atClaims["nbf"] = time.Unix()
+	if sessionID != "" {
+		atClaims["sid"] = sessionID
+	}
+	if impersonationID != "" {
+		atClaims["act"] = map[string]interface{}{"sub": adminID}
+		atClaims["imp"] = impersonationID
+	}
 
-	*tokenDetail.Token, err = jwt.NewWithClaims(jwt.SigningMethodRS256, atClaims).SignedString(key)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, atClaims)
+	jwtToken.Header["kid"] = KeyID(&key.PublicKey)
+	*tokenDetail.Token, err = jwtToken.SignedString(key)
 	if err != nil {
 		return nil, fmt.Errorf("create: sign token: %w", err)
 	}
@@ -52,23 +84,20 @@ func GenerateTokenJwt(jwtTokenTime time.Duration, userID string, privateKey stri
 	return tokenDetail, nil
 }
 
-func ValidateToken(token string, publicKey string) (*TokenDetails, error) {
-	decodedPublicKey, err := base64.StdEncoding.DecodeString(publicKey)
-	if err != nil {
-		return nil, fmt.Errorf("err: %w", err)
-	}
-
-	key, err := jwt.ParseRSAPublicKeyFromPEM(decodedPublicKey)
-
+// ValidateToken memverifikasi token dengan keyring (kunci publik dipisah koma, kunci
+// aktif di depan). Kunci dipilih berdasarkan header "kid".
+func ValidateToken(token string, publicKeys string) (*TokenDetails, error) {
+	ring, err := parseKeyring(publicKeys)
 	if err != nil {
-		return nil, fmt.Errorf("err : %w", err)
+		return nil, err
 	}
 
 	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("err : %s", t.Header["alg"])
 		}
-		return key, nil
+		kid, _ := t.Header["kid"].(string)
+		return ring.lookup(kid)
 	})
 
 	if err != nil {
@@ -80,8 +109,23 @@ func ValidateToken(token string, publicKey string) (*TokenDetails, error) {
 		return nil, fmt.Errorf("validate: invalid token")
 	}
 
+	sessionID, _ := claims["sid"].(string)
+	impersonationID, _ := claims["imp"].(string)
+	var impersonatorID string
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		impersonatorID, _ = act["sub"].(string)
+	}
+	exp, _ := claims["exp"].(float64)
+	iat, _ := claims["iat"].(float64)
+	expiresIn := int64(exp)
 	return &TokenDetails{
 		TokenUuid: fmt.Sprint(claims["token_uuid"]),
 		UserID:    fmt.Sprint(claims["sub"]),
+		SessionID: sessionID,
+		ExpiresIn: &expiresIn,
+		IssuedAt:  int64(math.Round(iat * 1000)),
+
+		ImpersonatorID:  impersonatorID,
+		ImpersonationID: impersonationID,
 	}, nil
 }
@@ -0,0 +1,50 @@
+package util_jwttoken
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+func testKeyPair(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})
+	return base64.StdEncoding.EncodeToString(privatePEM), base64.StdEncoding.EncodeToString(publicPEM)
+}
+
+// iat dibawa sampai milidetik supaya revoke di detik yang sama bisa dibedakan
+func TestIssuedAtMilliseconds(t *testing.T) {
+	privateKey, publicKey := testKeyPair(t)
+	before := time.Now().UnixMilli()
+	issued, err := GenerateSessionTokenJwt(time.Hour, "user-1", "session-1", privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issued.IssuedAt < before || issued.IssuedAt > time.Now().UnixMilli() {
+		t.Fatalf("IssuedAt = %d, want unix milliseconds around %d", issued.IssuedAt, before)
+	}
+
+	validated, err := ValidateToken(*issued.Token, publicKey)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if validated.IssuedAt != issued.IssuedAt {
+		t.Errorf("validated IssuedAt = %d, want %d", validated.IssuedAt, issued.IssuedAt)
+	}
+	if validated.SessionID != "session-1" || validated.UserID != "user-1" {
+		t.Errorf("claims = %q/%q, want user-1/session-1", validated.UserID, validated.SessionID)
+	}
+}
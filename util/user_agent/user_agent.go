@@ -0,0 +1,52 @@
+package util_useragent
+
+import "strings"
+
+// Browser menebak nama browser dari header User-Agent
+func Browser(ua string) string {
+	ua = strings.ToLower(ua)
+	switch {
+	case ua == "":
+		return "Unknown browser"
+	case strings.Contains(ua, "edg/"):
+		return "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		return "Opera"
+	case strings.Contains(ua, "firefox/"):
+		return "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		return "Chrome"
+	case strings.Contains(ua, "safari/"):
+		return "Safari"
+	case strings.Contains(ua, "curl/"):
+		return "curl"
+	case strings.Contains(ua, "postman"):
+		return "Postman"
+	default:
+		return "Unknown browser"
+	}
+}
+
+// OS menebak sistem operasi dari header User-Agent
+func OS(ua string) string {
+	ua = strings.ToLower(ua)
+	switch {
+	case strings.Contains(ua, "windows"):
+		return "Windows"
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		return "iOS"
+	case strings.Contains(ua, "android"):
+		return "Android"
+	case strings.Contains(ua, "mac os x") || strings.Contains(ua, "macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "linux"):
+		return "Linux"
+	default:
+		return "Unknown OS"
+	}
+}
+
+// Describe menghasilkan label singkat seperti "Chrome on Windows"
+func Describe(ua string) string {
+	return Browser(ua) + " on " + OS(ua)
+}
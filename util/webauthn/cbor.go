@@ -0,0 +1,149 @@
+package util_webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var errInvalidCBOR = errors.New("webauthn: invalid cbor")
+
+// decodeCBOR decoder CBOR minimal (hanya panjang definite) yang cukup untuk
+// attestationObject dan COSE key. Mengembalikan nilai dan sisa byte.
+func decodeCBOR(data []byte) (interface{}, []byte, error) {
+	return decodeCBORDepth(data, 0)
+}
+
+func decodeCBORDepth(data []byte, depth int) (interface{}, []byte, error) {
+	if len(data) == 0 || depth > 16 {
+		return nil, nil, errInvalidCBOR
+	}
+	major := data[0] >> 5
+	info := data[0] & 0x1f
+	data = data[1:]
+
+	// simple value & float
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data, nil
+		case 21:
+			return true, data, nil
+		case 22, 23:
+			return nil, data, nil
+		case 25:
+			if len(data) < 2 {
+				return nil, nil, errInvalidCBOR
+			}
+			return nil, data[2:], nil
+		case 26:
+			if len(data) < 4 {
+				return nil, nil, errInvalidCBOR
+			}
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), data[4:], nil
+		case 27:
+			if len(data) < 8 {
+				return nil, nil, errInvalidCBOR
+			}
+			return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+		}
+		return nil, nil, errInvalidCBOR
+	}
+
+	length, data, err := readCBORLength(info, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		if length > math.MaxInt64 {
+			return nil, nil, errInvalidCBOR
+		}
+		return int64(length), data, nil
+	case 1:
+		if length > math.MaxInt64 {
+			return nil, nil, errInvalidCBOR
+		}
+		return -1 - int64(length), data, nil
+	case 2, 3:
+		if uint64(len(data)) < length {
+			return nil, nil, errInvalidCBOR
+		}
+		value := data[:length]
+		if major == 3 {
+			return string(value), data[length:], nil
+		}
+		return append([]byte(nil), value...), data[length:], nil
+	case 4:
+		if length > uint64(len(data)) {
+			return nil, nil, errInvalidCBOR
+		}
+		items := make([]interface{}, 0, length)
+		for i := uint64(0); i < length; i++ {
+			var item interface{}
+			item, data, err = decodeCBORDepth(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, data, nil
+	case 5:
+		if length > uint64(len(data)) {
+			return nil, nil, errInvalidCBOR
+		}
+		items := make(map[interface{}]interface{}, length)
+		for i := uint64(0); i < length; i++ {
+			var key, value interface{}
+			key, data, err = decodeCBORDepth(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, errInvalidCBOR
+			}
+			value, data, err = decodeCBORDepth(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items[key] = value
+		}
+		return items, data, nil
+	case 6:
+		// tag diabaikan, ambil isinya saja
+		return decodeCBORDepth(data, depth+1)
+	}
+	return nil, nil, errInvalidCBOR
+}
+
+func readCBORLength(info byte, data []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		if len(data) < 1 {
+			return 0, nil, errInvalidCBOR
+		}
+		return uint64(data[0]), data[1:], nil
+	case info == 25:
+		if len(data) < 2 {
+			return 0, nil, errInvalidCBOR
+		}
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26:
+		if len(data) < 4 {
+			return 0, nil, errInvalidCBOR
+		}
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27:
+		if len(data) < 8 {
+			return 0, nil, errInvalidCBOR
+		}
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	}
+	// panjang indefinite tidak dipakai di WebAuthn (CTAP2 canonical)
+	return 0, nil, errInvalidCBOR
+}
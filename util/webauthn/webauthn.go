@@ -0,0 +1,252 @@
+package util_webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// algoritma COSE yang didukung
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+)
+
+var (
+	ErrInvalidClientData   = errors.New("webauthn: invalid client data")
+	ErrInvalidAuthData     = errors.New("webauthn: invalid authenticator data")
+	ErrUnsupportedKey      = errors.New("webauthn: unsupported public key")
+	ErrInvalidSignature    = errors.New("webauthn: invalid signature")
+	ErrUserNotPresent      = errors.New("webauthn: user presence flag not set")
+	ErrRelyingPartyInvalid = errors.New("webauthn: rp id hash mismatch")
+	ErrSignCountRegressed  = errors.New("webauthn: sign count did not increase")
+)
+
+type ClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	CredentialID []byte
+	// PublicKey dalam format PKIX DER, hanya ada saat registrasi
+	PublicKey []byte
+	Algorithm int
+}
+
+func (a *AuthenticatorData) UserVerified() bool {
+	return a.Flags&flagUserVerified != 0
+}
+
+// NewChallenge challenge acak 32 byte dalam base64url
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return EncodeBase64URL(b), nil
+}
+
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64URL menerima base64url dengan atau tanpa padding
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// VerifyClientData mengecek type, challenge dan origin dari clientDataJSON
+func VerifyClientData(clientDataJSON []byte, ceremonyType, challenge string, origins []string) error {
+	var clientData ClientData
+	if err := json.Unmarshal(clientDataJSON, &clientData); err != nil {
+		return ErrInvalidClientData
+	}
+	if clientData.Type != ceremonyType {
+		return ErrInvalidClientData
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimRight(clientData.Challenge, "=")), []byte(challenge)) != 1 {
+		return ErrInvalidClientData
+	}
+	for _, origin := range origins {
+		if strings.TrimSpace(origin) == clientData.Origin {
+			return nil
+		}
+	}
+	return ErrInvalidClientData
+}
+
+// ParseAttestationObject mengambil authenticator data dari attestationObject.
+// Attestation statement tidak diverifikasi karena kita meminta attestation "none".
+func ParseAttestationObject(attestationObject []byte, rpID string) (*AuthenticatorData, error) {
+	decoded, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, ErrInvalidAuthData
+	}
+	object, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrInvalidAuthData
+	}
+	rawAuthData, ok := object["authData"].([]byte)
+	if !ok {
+		return nil, ErrInvalidAuthData
+	}
+	authData, err := ParseAuthenticatorData(rawAuthData, rpID)
+	if err != nil {
+		return nil, err
+	}
+	if authData.CredentialID == nil {
+		return nil, ErrInvalidAuthData
+	}
+	return authData, nil
+}
+
+// ParseAuthenticatorData memvalidasi rpIdHash dan flag user present
+func ParseAuthenticatorData(data []byte, rpID string) (*AuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, ErrInvalidAuthData
+	}
+	authData := &AuthenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	expected := sha256.Sum256([]byte(rpID))
+	if subtle.ConstantTimeCompare(authData.RPIDHash, expected[:]) != 1 {
+		return nil, ErrRelyingPartyInvalid
+	}
+	if authData.Flags&flagUserPresent == 0 {
+		return nil, ErrUserNotPresent
+	}
+	if authData.Flags&flagAttestedCredData == 0 {
+		return authData, nil
+	}
+
+	rest := data[37:]
+	// aaguid (16) + panjang credential id (2)
+	if len(rest) < 18 {
+		return nil, ErrInvalidAuthData
+	}
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLength == 0 || len(rest) < idLength {
+		return nil, ErrInvalidAuthData
+	}
+	authData.CredentialID = append([]byte(nil), rest[:idLength]...)
+
+	coseKey, _, err := decodeCBOR(rest[idLength:])
+	if err != nil {
+		return nil, ErrInvalidAuthData
+	}
+	publicKey, alg, err := parseCOSEKey(coseKey)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, ErrUnsupportedKey
+	}
+	authData.PublicKey = der
+	authData.Algorithm = alg
+	return authData, nil
+}
+
+// VerifySignature memverifikasi signature assertion atas authenticatorData || sha256(clientDataJSON)
+func VerifySignature(publicKeyDER []byte, alg int, authenticatorData, clientDataJSON, signature []byte) error {
+	publicKey, err := x509.ParsePKIXPublicKey(publicKeyDER)
+	if err != nil {
+		return ErrUnsupportedKey
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authenticatorData...), clientDataHash[:]...)
+	digest := sha256.Sum256(signed)
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if alg != AlgES256 || !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return ErrInvalidSignature
+		}
+	case *rsa.PublicKey:
+		if alg != AlgRS256 || rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+			return ErrInvalidSignature
+		}
+	case ed25519.PublicKey:
+		if alg != AlgEdDSA || !ed25519.Verify(key, signed, signature) {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedKey
+	}
+	return nil
+}
+
+// CheckSignCount counter yang tidak naik menandakan authenticator kemungkinan diklon.
+// Authenticator yang tidak punya counter selalu mengirim 0.
+func CheckSignCount(stored, received uint32) error {
+	if (received != 0 || stored != 0) && received <= stored {
+		return ErrSignCountRegressed
+	}
+	return nil
+}
+
+func parseCOSEKey(decoded interface{}) (crypto.PublicKey, int, error) {
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, 0, ErrUnsupportedKey
+	}
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+
+	switch {
+	case kty == 2 && alg == AlgES256:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, ErrUnsupportedKey
+		}
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return publicKey, AlgES256, nil
+	case kty == 3 && alg == AlgRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, AlgRS256, nil
+	case kty == 1 && alg == AlgEdDSA:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return ed25519.PublicKey(x), AlgEdDSA, nil
+	}
+	return nil, 0, ErrUnsupportedKey
+}
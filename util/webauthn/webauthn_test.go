@@ -0,0 +1,217 @@
+package util_webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// vektor dari authenticator Ed25519 dengan seed 0x01..0x20, rp id xops.sector.co.id,
+// attestation "none" dan credential id "credential-0001!"
+const (
+	testRPID              = "xops.sector.co.id"
+	testOrigin            = "https://xops.sector.co.id"
+	testChallenge         = "dGVzdC1jaGFsbGVuZ2UtMDAwMQ"
+	testAttestationObject = "a363666d74646e6f6e656761747453746d74a06861757468446174615900712f86e5a2bcb5908e0e66bd4999073983704bc83253272c4122ff518e2a9cb3d2450000000000000000000000000000000000000000001063726564656e7469616c2d3030303121a401010327200621582079b5562e8fe654f94078b112e8a98ba7901f853ae695bed7e0e3910bad049664"
+	testPublicKey         = "79b5562e8fe654f94078b112e8a98ba7901f853ae695bed7e0e3910bad049664"
+	testClientDataJSON    = `{"type":"webauthn.get","challenge":"dGVzdC1jaGFsbGVuZ2UtMDAwMQ","origin":"https://xops.sector.co.id"}`
+	// assertion dengan flag UP|UV dan sign count 7
+	testAuthenticatorData = "2f86e5a2bcb5908e0e66bd4999073983704bc83253272c4122ff518e2a9cb3d20500000007"
+	testSignature         = "50127ed401b4064e1c2d22128e67903d03b1660972cd1abe5284f2881a6013748ee32b8cc4062c83cbec863f3bdac42f745e9dae913f127fb8ba66b17b690b0c"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func registerTestCredential(t *testing.T) *AuthenticatorData {
+	t.Helper()
+	authData, err := ParseAttestationObject(mustHex(t, testAttestationObject), testRPID)
+	if err != nil {
+		t.Fatalf("ParseAttestationObject: %v", err)
+	}
+	return authData
+}
+
+func TestParseAttestationObject(t *testing.T) {
+	authData := registerTestCredential(t)
+	if string(authData.CredentialID) != "credential-0001!" {
+		t.Errorf("credential id = %q", authData.CredentialID)
+	}
+	if authData.Algorithm != AlgEdDSA || authData.SignCount != 0 || !authData.UserVerified() {
+		t.Errorf("unexpected auth data %+v", authData)
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(authData.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal([]byte(publicKey.(ed25519.PublicKey)), mustHex(t, testPublicKey)) {
+		t.Error("public key does not match the vector")
+	}
+}
+
+func TestParseAttestationObjectWrongRPID(t *testing.T) {
+	if _, err := ParseAttestationObject(mustHex(t, testAttestationObject), "evil.example.com"); !errors.Is(err, ErrRelyingPartyInvalid) {
+		t.Fatalf("err = %v, want ErrRelyingPartyInvalid", err)
+	}
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	credential := registerTestCredential(t)
+	clientDataJSON := []byte(testClientDataJSON)
+	rawAuthData := mustHex(t, testAuthenticatorData)
+
+	if err := VerifyClientData(clientDataJSON, "webauthn.get", testChallenge, []string{"https://other.example.com", " " + testOrigin}); err != nil {
+		t.Fatalf("VerifyClientData: %v", err)
+	}
+	authData, err := ParseAuthenticatorData(rawAuthData, testRPID)
+	if err != nil {
+		t.Fatalf("ParseAuthenticatorData: %v", err)
+	}
+	if authData.SignCount != 7 {
+		t.Errorf("sign count = %d, want 7", authData.SignCount)
+	}
+	if err := VerifySignature(credential.PublicKey, credential.Algorithm, rawAuthData, clientDataJSON, mustHex(t, testSignature)); err != nil {
+		t.Fatalf("VerifySignature: %v", err)
+	}
+}
+
+func TestVerifySignatureRejectsTampering(t *testing.T) {
+	credential := registerTestCredential(t)
+	rawAuthData := mustHex(t, testAuthenticatorData)
+	signature := mustHex(t, testSignature)
+
+	badSignature := append([]byte(nil), signature...)
+	badSignature[0] ^= 0xff
+	bumpedCount := append([]byte(nil), rawAuthData...)
+	bumpedCount[36] = 8
+
+	cases := map[string]struct {
+		alg                 int
+		authData, signature []byte
+		clientDataJSON      string
+	}{
+		"bad signature":       {AlgEdDSA, rawAuthData, badSignature, testClientDataJSON},
+		"modified auth data":  {AlgEdDSA, bumpedCount, signature, testClientDataJSON},
+		"modified clientData": {AlgEdDSA, rawAuthData, signature, `{"type":"webauthn.get","challenge":"other","origin":"https://xops.sector.co.id"}`},
+		"algorithm mismatch":  {AlgES256, rawAuthData, signature, testClientDataJSON},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := VerifySignature(credential.PublicKey, tc.alg, tc.authData, []byte(tc.clientDataJSON), tc.signature)
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("err = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestParseAuthenticatorDataRejects(t *testing.T) {
+	rawAuthData := mustHex(t, testAuthenticatorData)
+	notPresent := append([]byte(nil), rawAuthData...)
+	notPresent[32] = flagUserVerified
+
+	if _, err := ParseAuthenticatorData(rawAuthData, "evil.example.com"); !errors.Is(err, ErrRelyingPartyInvalid) {
+		t.Errorf("wrong rp id: err = %v", err)
+	}
+	if _, err := ParseAuthenticatorData(notPresent, testRPID); !errors.Is(err, ErrUserNotPresent) {
+		t.Errorf("no user presence: err = %v", err)
+	}
+	if _, err := ParseAuthenticatorData(rawAuthData[:36], testRPID); !errors.Is(err, ErrInvalidAuthData) {
+		t.Errorf("truncated: err = %v", err)
+	}
+}
+
+func TestVerifyClientDataRejects(t *testing.T) {
+	cases := map[string]struct {
+		clientDataJSON, ceremony, challenge string
+	}{
+		"wrong origin":    {`{"type":"webauthn.get","challenge":"dGVzdC1jaGFsbGVuZ2UtMDAwMQ","origin":"https://evil.example.com"}`, "webauthn.get", testChallenge},
+		"wrong challenge": {testClientDataJSON, "webauthn.get", "b3RoZXItY2hhbGxlbmdl"},
+		"wrong ceremony":  {testClientDataJSON, "webauthn.create", testChallenge},
+		"not json":        {"not-json", "webauthn.get", testChallenge},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyClientData([]byte(tc.clientDataJSON), tc.ceremony, tc.challenge, []string{testOrigin})
+			if !errors.Is(err, ErrInvalidClientData) {
+				t.Fatalf("err = %v, want ErrInvalidClientData", err)
+			}
+		})
+	}
+}
+
+func TestCheckSignCount(t *testing.T) {
+	cases := []struct {
+		stored, received uint32
+		wantErr          bool
+	}{
+		{0, 0, false},
+		{0, 1, false},
+		{7, 8, false},
+		{7, 7, true},
+		{7, 3, true},
+		// authenticator yang tiba-tiba mengirim 0 setelah pernah menaikkan counter
+		{7, 0, true},
+	}
+	for _, tc := range cases {
+		err := CheckSignCount(tc.stored, tc.received)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("CheckSignCount(%d, %d) = %v", tc.stored, tc.received, err)
+		}
+	}
+}
+
+// ES256 paling umum dipakai passkey, signature ECDSA acak jadi key dibuat saat test
+func TestES256Roundtrip(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := make([]byte, 32), make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+	// {1: 2, 3: -7, -1: 1, -2: x, -3: y}
+	cose := append([]byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}, x...)
+	cose = append(append(cose, 0x22, 0x58, 0x20), y...)
+
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	registration := append(rpIDHash[:], flagUserPresent|flagAttestedCredData, 0, 0, 0, 0)
+	registration = append(registration, make([]byte, 16)...)
+	registration = append(registration, 0, 2, 0xca, 0xfe)
+	registration = append(registration, cose...)
+	credential, err := ParseAuthenticatorData(registration, testRPID)
+	if err != nil {
+		t.Fatalf("ParseAuthenticatorData: %v", err)
+	}
+	if credential.Algorithm != AlgES256 {
+		t.Fatalf("algorithm = %d", credential.Algorithm)
+	}
+
+	assertion := append(rpIDHash[:], flagUserPresent, 0, 0, 0, 1)
+	clientDataHash := sha256.Sum256([]byte(testClientDataJSON))
+	digest := sha256.Sum256(append(append([]byte(nil), assertion...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySignature(credential.PublicKey, AlgES256, assertion, []byte(testClientDataJSON), signature); err != nil {
+		t.Fatalf("VerifySignature: %v", err)
+	}
+	signature[len(signature)-1] ^= 0x01
+	if err := VerifySignature(credential.PublicKey, AlgES256, assertion, []byte(testClientDataJSON), signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered signature: err = %v", err)
+	}
+}